@@ -0,0 +1,99 @@
+// cmd/convert/convert.go
+package convert
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fromFormat string
+	toFormat   string
+	outputFile string
+)
+
+func NewConvertCmd() *cobra.Command {
+	convertCmd := &cobra.Command{
+		Use:   "convert <log-file>",
+		Short: "Re-emit a log file written by this tool in another format",
+		Long: `Read a log file previously written by this tool and write its events in a
+different export format, without scanning AWS again.
+
+Options:
+  --from         Input format: text, json, ndjson, json-document, cloudevents, or native (default: detect)
+  --to           Output format: text, json, ndjson, json-document, cloudevents, or native
+  --output-file  File to write; must not already exist
+
+Text logs only record a summary of each event, so converting from text keeps
+the timestamp, name, source, user, resources, and flat request parameters and
+response elements. Gzipped logs written with --compress are read directly.
+
+Examples:
+  # Turn a text log into JSON
+  cloudtrail-logs convert ~/aws-monitor-logs/kms/kms-events-2024-01-15.log --to json --output-file events.json
+
+  # Wrap a JSON log in a single document with metadata
+  cloudtrail-logs convert events.json --to json-document --output-file events-doc.json`,
+		Args: cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if fromFormat != "" {
+				if err := writer.ValidateFormat(fromFormat); err != nil {
+					return fmt.Errorf("--from: %v", err)
+				}
+			}
+			if err := writer.ValidateFormat(toFormat); err != nil {
+				return fmt.Errorf("--to: %v", err)
+			}
+			if _, err := os.Stat(outputFile); err == nil {
+				return fmt.Errorf("output file %s already exists", outputFile)
+			}
+			return nil
+		},
+		RunE: runConvert,
+	}
+
+	convertCmd.Flags().StringVar(&fromFormat, "from", "", "Input format (default: detect from the file)")
+	convertCmd.Flags().StringVar(&toFormat, "to", "", "Output format (text, json, ndjson, json-document, cloudevents, or native)")
+	convertCmd.Flags().StringVar(&outputFile, "output-file", "", "File to write the converted events to")
+	convertCmd.MarkFlagRequired("to")
+	convertCmd.MarkFlagRequired("output-file")
+
+	return convertCmd
+}
+
+func runConvert(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %v", err)
+	}
+	defer f.Close()
+
+	entries, err := writer.ReadEntries(f, fromFormat)
+	if err != nil {
+		return err
+	}
+
+	// Tag the output with the service the events came from (kms.amazonaws.com -> kms)
+	service := "cloudtrail"
+	if len(entries) > 0 && entries[0].Event.EventSource != nil {
+		service, _, _ = strings.Cut(*entries[0].Event.EventSource, ".")
+	}
+
+	logWriter := writer.NewLogWriter("", service, &writer.ExportOptions{
+		Filename: outputFile,
+		Format:   toFormat,
+	})
+	if err := logWriter.WriteBatch(entries); err != nil {
+		return err
+	}
+	if err := logWriter.Close(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Converted %d events to %s: %s\n", len(entries), toFormat, outputFile)
+	return nil
+}
@@ -0,0 +1,134 @@
+// cmd/digest/digest.go
+package digest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/classify"
+	"github.com/dhairya13703/cloudtrail-logs/internal/monitor"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+	"github.com/spf13/cobra"
+)
+
+var (
+	day                string
+	eventSource        string
+	top                int
+	format             string
+	classificationFile string
+	reportFile         string
+)
+
+func NewDigestCmd() *cobra.Command {
+	digestCmd := &cobra.Command{
+		Use:   "digest",
+		Short: "Summarize a day of CloudTrail events",
+		Long: `Produce a single summary report for one day of CloudTrail events instead of raw
+events: top events, top users, error counts, and notable actions. Meant for
+scheduled runs whose output is emailed or posted somewhere.
+
+Options:
+  --date                Day to summarize: YYYY-MM-DD, today, or yesterday (default yesterday)
+  --source              Event source to summarize, or "all" (default kms.amazonaws.com)
+  --top                 Entries in each ranking (default 10)
+  --format              Report format: text or markdown (default text)
+  --classification-file Rules file; events rated high or critical are notable
+  --report-file         Write the report to this file instead of stdout
+
+Without a classification file, sensitive KMS actions such as ScheduleKeyDeletion,
+DisableKey, and PutKeyPolicy are reported as notable.
+
+Examples:
+  # Yesterday's KMS digest
+  cloudtrail-logs digest
+
+  # Markdown digest of all services for a given day
+  cloudtrail-logs digest --date 2024-01-15 --source all --format markdown --report-file digest.md`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := monitor.ValidateDigestFormat(format); err != nil {
+				return err
+			}
+			if top < 1 {
+				return fmt.Errorf("--top must be at least 1")
+			}
+			return nil
+		},
+		RunE: runDigest,
+	}
+
+	digestCmd.Flags().StringVar(&day, "date", "", "Day to summarize (YYYY-MM-DD, today, or yesterday)")
+	digestCmd.Flags().StringVar(&eventSource, "source", "kms.amazonaws.com", "Event source to summarize, or \"all\"")
+	digestCmd.Flags().IntVar(&top, "top", 10, "Number of entries in each ranking")
+	digestCmd.Flags().StringVar(&format, "format", monitor.DigestFormatText, "Report format (text or markdown)")
+	digestCmd.Flags().StringVar(&classificationFile, "classification-file", "", "YAML/JSON rules file; high and critical events are notable")
+	digestCmd.Flags().StringVar(&reportFile, "report-file", "", "Write the report to this file instead of stdout")
+
+	return digestCmd
+}
+
+func runDigest(cmd *cobra.Command, args []string) error {
+	start, end, err := timeutil.DayRange(day, timeutil.Now())
+	if err != nil {
+		return fmt.Errorf("invalid --date: %v", err)
+	}
+
+	ctx := context.Background()
+	profile, _ := cmd.Flags().GetString("profile")
+	region, _ := cmd.Flags().GetString("region")
+	if !cmd.Flags().Changed("region") {
+		// Let the profile's configured region take precedence over the default
+		region = ""
+	}
+	if len(aws.SplitRegions(region)) > 1 {
+		return fmt.Errorf("digest queries one region at a time; pass a single --region")
+	}
+
+	// Keep the report clean when it goes to stdout
+	var console io.Writer = os.Stderr
+	client, err := aws.NewAWSClient(ctx, profile, region, console)
+	if err != nil {
+		return fmt.Errorf("AWS client initialization failed:\n%v", err)
+	}
+
+	var classifier *classify.Classifier
+	if classificationFile != "" {
+		if classifier, err = classify.Load(classificationFile); err != nil {
+			return err
+		}
+	}
+
+	source := eventSource
+	if source == "all" {
+		source = ""
+	}
+
+	digest, err := monitor.BuildDigest(ctx, client, monitor.DigestOptions{
+		Start:       start,
+		End:         end,
+		EventSource: source,
+		Top:         top,
+		Classifier:  classifier,
+	})
+	if err != nil {
+		return err
+	}
+
+	if reportFile == "" {
+		return digest.Write(os.Stdout, format)
+	}
+
+	f, err := os.Create(reportFile)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %v", err)
+	}
+	defer f.Close()
+	if err := digest.Write(f, format); err != nil {
+		return err
+	}
+	fmt.Fprintf(console, "Digest written to %s\n", reportFile)
+	return nil
+}
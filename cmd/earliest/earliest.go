@@ -0,0 +1,90 @@
+// cmd/earliest/earliest.go
+package earliest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/monitor"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+	"github.com/spf13/cobra"
+)
+
+var (
+	eventSource string
+	precision   time.Duration
+)
+
+func NewEarliestCmd() *cobra.Command {
+	earliestCmd := &cobra.Command{
+		Use:   "earliest",
+		Short: "Estimate how far back CloudTrail event history goes",
+		Long: `Estimate the timestamp of the earliest event available to LookupEvents by
+binary searching the event history, so you know how far back a scan can reach
+before running one. CloudTrail keeps up to 90 days of event history.
+
+Options:
+  --source     Only consider events from this source, e.g. kms.amazonaws.com (default all)
+  --precision  Stop once the estimate is this narrow (default 1h)
+
+Examples:
+  # How far back does event history go?
+  cloudtrail-logs earliest
+
+  # Earliest KMS event, to the minute
+  cloudtrail-logs earliest --source kms.amazonaws.com --precision 1m`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if precision < time.Second {
+				return fmt.Errorf("--precision must be at least 1s")
+			}
+			return nil
+		},
+		RunE: runEarliest,
+	}
+
+	earliestCmd.Flags().StringVar(&eventSource, "source", "", "Only consider events from this source (e.g. kms.amazonaws.com)")
+	earliestCmd.Flags().DurationVar(&precision, "precision", time.Hour, "Stop once the estimate is this narrow (e.g. 1m, 1h)")
+
+	return earliestCmd
+}
+
+func runEarliest(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	profile, _ := cmd.Flags().GetString("profile")
+	region, _ := cmd.Flags().GetString("region")
+	if !cmd.Flags().Changed("region") {
+		// Let the profile's configured region take precedence over the default
+		region = ""
+	}
+	if len(aws.SplitRegions(region)) > 1 {
+		return fmt.Errorf("earliest queries one region at a time; pass a single --region")
+	}
+
+	// Keep stdout to the answer
+	client, err := aws.NewAWSClient(ctx, profile, region, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("AWS client initialization failed:\n%v", err)
+	}
+
+	now := time.Now()
+	earliest, err := monitor.FindEarliest(ctx, client.CloudTrail, now, monitor.EarliestOptions{
+		EventSource: eventSource,
+		Precision:   precision,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !earliest.Found {
+		fmt.Println("No events found in the last 90 days of event history")
+		return nil
+	}
+	fmt.Printf("Earliest event: between %s and %s\n",
+		timeutil.FormatTime(earliest.After), timeutil.FormatTime(earliest.Before))
+	fmt.Printf("History reaches back about %.1f days (%d lookups)\n",
+		now.Sub(earliest.Before).Hours()/24, earliest.Probes)
+	return nil
+}
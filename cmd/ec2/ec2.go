@@ -0,0 +1,142 @@
+// cmd/ec2/ec2.go
+package ec2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/exit"
+	"github.com/dhairya13703/cloudtrail-logs/internal/monitor"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	instanceID string
+	eventName  string
+	userName   string
+	sourceIP   string
+
+	// Time filters
+	lastN     string
+	startTime string
+	endTime   string
+
+	// Export options
+	exportFile   string
+	exportFormat string
+)
+
+func NewEC2Cmd() *cobra.Command {
+	ec2Cmd := &cobra.Command{
+		Use:   "ec2",
+		Short: "Monitor EC2 events",
+		Long: `Monitor AWS EC2 API activity through CloudTrail logs.
+
+Search Options:
+  --instance-id  Filter by instance ID (in resources or request parameters)
+  --event        Filter by event name (e.g., "StopInstances", "RunInstances")
+  --user         Filter by username
+  --source-ip    Filter by caller IP address or CIDR (IPv4 or IPv6)
+
+Time Range Options:
+  --last-n       Look back time (e.g., 30m, 2h, 7d, yesterday, this-week)
+  --start/--end  Custom time range (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD HH:mm, or YYYY-MM-DD)
+
+Export Options:
+  --export-file    Export to specific file, named pipe, or Unix socket
+  --export-format  Export format (text, json, ndjson, json-document, cloudevents, or native)
+
+Examples:
+  # Everything that happened to an instance in the last hour
+  cloudtrail-logs ec2 --instance-id i-0abc123 --last-n 1h
+
+  # Who stopped instances yesterday
+  cloudtrail-logs ec2 --event StopInstances --last-n yesterday --export-format json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if lastN == "" && (startTime == "" || endTime == "") {
+				return fmt.Errorf("time range is required: use either --last-n or both --start and --end")
+			}
+			if instanceID == "" && eventName == "" && userName == "" && sourceIP == "" {
+				return fmt.Errorf("at least one search criteria is required: --instance-id, --event, --user, or --source-ip")
+			}
+			if sourceIP != "" {
+				if err := monitor.ValidateSourceIP(sourceIP); err != nil {
+					return err
+				}
+			}
+			return writer.ValidateFormat(exportFormat)
+		},
+		RunE: runEC2,
+	}
+
+	ec2Cmd.Flags().StringVar(&instanceID, "instance-id", "", "Filter by EC2 instance ID")
+	ec2Cmd.Flags().StringVar(&eventName, "event", "", "Filter by event name, or several comma-separated")
+	ec2Cmd.Flags().StringVar(&userName, "user", "", "Filter by username")
+	ec2Cmd.Flags().StringVar(&sourceIP, "source-ip", "", "Filter by caller IP address or CIDR")
+	ec2Cmd.Flags().StringVar(&lastN, "last-n", "", "Look back time (e.g., 5m, 2h, 7d, yesterday, this-week)")
+	ec2Cmd.Flags().StringVar(&startTime, "start", "", "Start time")
+	ec2Cmd.Flags().StringVar(&endTime, "end", "", "End time")
+	ec2Cmd.Flags().StringVar(&exportFile, "export-file", "", "Export to specific file")
+	ec2Cmd.Flags().StringVar(&exportFormat, "export-format", "text", "Export format (text, json, ndjson, json-document, cloudevents, or native)")
+
+	return ec2Cmd
+}
+
+func runEC2(cmd *cobra.Command, args []string) error {
+	start, end, err := timeutil.ValidateAndParseTimeRange(lastN, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C stops the scan between pages; what matched so far is still reported
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	profile, _ := cmd.Flags().GetString("profile")
+	region, _ := cmd.Flags().GetString("region")
+	if !cmd.Flags().Changed("region") {
+		// Let the profile's configured region take precedence over the default
+		region = ""
+	}
+	outputDir, _ := cmd.Flags().GetString("output")
+
+	// Quiet runs skip the login banner along with the per-event output
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	var banner io.Writer
+	if quiet {
+		banner = io.Discard
+	}
+
+	client, err := aws.NewAWSClient(ctx, profile, region, banner)
+	if err != nil {
+		return fmt.Errorf("AWS client initialization failed:\n%v", err)
+	}
+
+	filters := monitor.FilterOptions{
+		InstanceID: instanceID,
+		EventName:  eventName,
+		UserName:   userName,
+		SourceIP:   sourceIP,
+	}
+	exportOptions := &writer.ExportOptions{
+		Filename: exportFile,
+		Format:   exportFormat,
+		Region:   client.Region,
+		Profile:  profile,
+	}
+
+	ec2Monitor := monitor.NewEC2Monitor(client, outputDir, exportOptions, &monitor.OutputOptions{Quiet: quiet})
+	err = ec2Monitor.MonitorEC2Events(ctx, filters, start, end)
+	if errors.Is(err, monitor.ErrInterrupted) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &exit.Error{Code: exit.Interrupted}
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+// cmd/kms/env.go
+package kms
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Prefix for environment variables that supply default filter values
+const envPrefix = "CLOUDTRAIL_LOGS_"
+
+// Filter flags that can be defaulted from the environment, e.g.
+// CLOUDTRAIL_LOGS_USER for --user and CLOUDTRAIL_LOGS_HUMANS_ONLY for --humans-only
+var envFilterFlags = []string{
+	"key", "event", "user", "operation", "role", "has-param", "response-param", "source-ip", "min-tls",
+	"error-code", "error-message", "errors-only", "success-only", "humans-only", "console-only",
+	"mutations-only", "mutation-verbs", "exclude-event",
+}
+
+// A flag given on the command line also overrides the environment defaults of
+// the flags it's mutually exclusive with
+var envExclusiveFlags = map[string][]string{
+	"errors-only":  {"success-only"},
+	"error-code":   {"success-only"},
+	"success-only": {"errors-only", "error-code"},
+}
+
+// envVarName maps a flag name to its environment variable
+func envVarName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// overriddenBy reports whether any of the flags was given on the command line
+func overriddenBy(cmd *cobra.Command, flags []string) bool {
+	for _, flag := range flags {
+		if cmd.Flags().Changed(flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyEnvDefaults fills filter flags that weren't given on the command line
+// from their environment variables. The flags are left unmarked as changed so
+// they still behave as defaults.
+func applyEnvDefaults(cmd *cobra.Command) error {
+	for _, name := range envFilterFlags {
+		value, ok := os.LookupEnv(envVarName(name))
+		if !ok || value == "" || cmd.Flags().Changed(name) {
+			continue
+		}
+		if overriddenBy(cmd, envExclusiveFlags[name]) {
+			continue
+		}
+		if err := cmd.Flags().Lookup(name).Value.Set(value); err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", envVarName(name), value, err)
+		}
+	}
+	return nil
+}
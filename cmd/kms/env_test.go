@@ -0,0 +1,65 @@
+// cmd/kms/env_test.go
+package kms
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvVarName(t *testing.T) {
+	if got := envVarName("exclude-event"); got != "CLOUDTRAIL_LOGS_EXCLUDE_EVENT" {
+		t.Errorf("envVarName(exclude-event) = %s", got)
+	}
+}
+
+func TestEnvDefaults(t *testing.T) {
+	t.Setenv("CLOUDTRAIL_LOGS_USER", "alice")
+	t.Setenv("CLOUDTRAIL_LOGS_EXCLUDE_EVENT", "GenerateDataKey,Decrypt")
+	t.Setenv("CLOUDTRAIL_LOGS_HUMANS_ONLY", "true")
+
+	cmd := parsedKMSCmd(t)
+	if err := applyEnvDefaults(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if user, _ := cmd.Flags().GetString("user"); user != "alice" {
+		t.Errorf("--user = %q, want alice from the environment", user)
+	}
+	if excluded, _ := cmd.Flags().GetStringSlice("exclude-event"); !reflect.DeepEqual(excluded, []string{"GenerateDataKey", "Decrypt"}) {
+		t.Errorf("--exclude-event = %v", excluded)
+	}
+	if humans, _ := cmd.Flags().GetBool("humans-only"); !humans {
+		t.Error("--humans-only not set from the environment")
+	}
+	// Still defaults, so they don't look like command line choices
+	if cmd.Flags().Changed("user") {
+		t.Error("--user marked as changed by the environment")
+	}
+}
+
+func TestFlagsOverrideEnvDefaults(t *testing.T) {
+	t.Setenv("CLOUDTRAIL_LOGS_USER", "alice")
+	t.Setenv("CLOUDTRAIL_LOGS_EXCLUDE_EVENT", "GenerateDataKey")
+	t.Setenv("CLOUDTRAIL_LOGS_SUCCESS_ONLY", "true")
+
+	cmd := parsedKMSCmd(t, "--user", "bob", "--exclude-event", "Encrypt", "--errors-only")
+	if err := applyEnvDefaults(cmd); err != nil {
+		t.Fatal(err)
+	}
+	if user, _ := cmd.Flags().GetString("user"); user != "bob" {
+		t.Errorf("--user = %q, want the flag's bob", user)
+	}
+	if excluded, _ := cmd.Flags().GetStringSlice("exclude-event"); !reflect.DeepEqual(excluded, []string{"Encrypt"}) {
+		t.Errorf("--exclude-event = %v, want only the flag's Encrypt", excluded)
+	}
+	// --errors-only on the command line rules out the environment's --success-only
+	if success, _ := cmd.Flags().GetBool("success-only"); success {
+		t.Error("--success-only applied from the environment alongside --errors-only")
+	}
+}
+
+func TestInvalidEnvDefault(t *testing.T) {
+	t.Setenv("CLOUDTRAIL_LOGS_ERRORS_ONLY", "sometimes")
+	if err := applyEnvDefaults(parsedKMSCmd(t)); err == nil {
+		t.Error("applyEnvDefaults accepted a non-boolean CLOUDTRAIL_LOGS_ERRORS_ONLY")
+	}
+}
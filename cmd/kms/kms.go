@@ -3,10 +3,26 @@ package kms
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
 
 	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/bytesize"
+	"github.com/dhairya13703/cloudtrail-logs/internal/classify"
+	"github.com/dhairya13703/cloudtrail-logs/internal/elastic"
+	"github.com/dhairya13703/cloudtrail-logs/internal/emf"
+	"github.com/dhairya13703/cloudtrail-logs/internal/exit"
 	"github.com/dhairya13703/cloudtrail-logs/internal/monitor"
+	"github.com/dhairya13703/cloudtrail-logs/internal/retry"
+	"github.com/dhairya13703/cloudtrail-logs/internal/syslog"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
 	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
 	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
 	"github.com/spf13/cobra"
@@ -22,15 +38,94 @@ var (
 	endTime   string
 
 	// Event filters
-	eventName   string
-	userName    string
-	operation   string
-	errorsOnly  bool
-	successOnly bool
+	eventName     string
+	userName      string
+	operation     string
+	role          string
+	minTLS        string
+	errorsOnly    bool
+	successOnly   bool
+	humansOnly    bool
+	consoleOnly   bool
+	mutations     bool
+	mutationVerbs []string
+	excludeEvents []string
+	hasParam      string
+
+	responseParam string
+	sourceIP      string
+	errorMessage  string
+	errorCode     string
+
+	includeMalformed bool
+	insightsOnly     bool
+	clientSideOnly   bool
+	exactMatch       bool
+
+	// Classification
+	classificationFile string
+	minSeverity        string
+	alertSeverity      string
+
+	// Sampling
+	sampleRate float64
+	seed       int64
 
 	// Export options
-	exportFile   string
-	exportFormat string
+	exportFile       string
+	exportFormat     string
+	filenameTemplate string
+	fileSeparator    string
+	batchWrites      bool
+	splitFiles       bool
+	compress         bool
+	maxFileSize      string
+	regionDirs       bool
+	writeRetries     int
+	esURL            string
+	esIndex          string
+	esBatchSize      int
+	syslogAddr       string
+
+	// Output options
+	quietNoResults    bool
+	noResultsExitCode int
+	groupByRequest    bool
+	order             string
+	timelineBy        string
+	maxMemory         string
+	showIndex         bool
+	showCLI           bool
+	idsOnly           bool
+	stdoutJSON        bool
+	consoleJSON       bool
+	explain           bool
+	truncateValuesAt  int
+	histogramOn       bool
+	sizeStats         bool
+	summary           bool
+	histogramBucket   time.Duration
+	tableOutput       bool
+	normalizeARNs     bool
+	explodeARNs       bool
+	alertThreshold    int
+	alertBy           string
+	alertFail         bool
+	failOnErrors      bool
+	expectedUsersFile string
+	requireContext    bool
+	baselineFile      string
+	updateBaseline    bool
+	emfOutput         string
+	emfNamespace      string
+	stateFile         string
+	manifestFile      string
+	throttleCooldown  time.Duration
+	throttleResumes   int
+	regionConcurrency int
+	concurrency       int
+	maxAttempts       int
+	noProgress        bool
 )
 
 func NewKMSCmd() *cobra.Command {
@@ -41,15 +136,23 @@ func NewKMSCmd() *cobra.Command {
         
 Search Options:
   --key          Optional: Filter by specific KMS key ID or ARN
-  --event        Filter by event name (e.g., "Decrypt", "GenerateDataKey")
+  --event        Filter by event name (e.g., "Decrypt"), or any of a comma-separated list ("Decrypt,GenerateDataKey")
   --user         Filter by username
   --operation    Filter by operation type
+  --role         Filter by the IAM role behind assumed-role sessions
+  --min-tls      Find calls made over TLS older than this version (e.g. 1.2)
+  --has-param    Find calls whose requestParameters contain a (dotted) key
+  --response-param  Find calls whose responseElements hold key=value (dotted key)
+  --source-ip    Find calls from an IP address or CIDR (IPv4 or IPv6)
 
 Time Range Options:
   1. Relative time (--last-n):
      - Minutes: e.g., --last-n 5m (last 5 minutes)
      - Hours: e.g., --last-n 2h (last 2 hours)
-     Maximum: 24 hours
+     - Days: e.g., --last-n 7d (last 7 days)
+     Maximum: 90 days
+     Named ranges: today, yesterday, this-week, last-week, this-month, last-month
+     Presets: names defined under timeRanges in the config file
 
   2. Custom time range (--start and --end):
      Format options:
@@ -59,11 +162,92 @@ Time Range Options:
 
 Filter Options:
   --errors-only  Show only error events
+  --error-code   Show only events with this errorCode (case-insensitive, e.g. AccessDenied)
+  --error-message  Show only events whose errorMessage matches this text or regex (case-insensitive)
   --success-only Show only successful events
+  --insights-only  Show only CloudTrail Insights anomaly events
+  --humans-only  Drop AWS service and service-linked role activity
+  --console-only Show only requests made from the AWS Management Console
+  --mutations-only  Show only state-changing calls (Create*, Delete*, Put*, ...)
+  --mutation-verbs  Comma-separated verbs --mutations-only matches (replaces the defaults)
+  --exclude-event  Drop events with this name (repeatable or comma-separated), e.g. Decrypt
+  --exact        Match --event and --user exactly, letting CloudTrail filter them server-side
+  --client-side-only  Never filter server-side, even on a full key ARN
+  --include-malformed  Keep events missing EventName/EventTime, marked as incomplete
+  --sample-rate  Keep only a fraction of matched events (e.g. 0.1)
+  --seed         Seed for reproducible sampling
+
+Classification Options:
+  --classification-file  YAML/JSON rules mapping event names or regexes to severities and tags
+  --min-severity         Show only events classified at or above this severity
+  --alert-severity       Alert on events at or above this severity (see --alert-fail)
 
 Export Options:
-  --export-file    Export to specific file
-  --export-format  Export format (text or json)
+  --export-file    Export to specific file, named pipe, or Unix socket
+                   (takes precedence over --output and --filename-template)
+  --export-format  Export format (text, json, ndjson, json-document, cloudevents, or native)
+  --filename-template  Log filename template using {service}, {date}, {region}, {profile}
+  --region-dirs        Write log files under <output>/<service>/<region>/
+  --file-separator     Separator between events in text files (line, blank, none)
+  --batch-writes       Write each page of events at once instead of per event
+  --split-files        Write each event to its own <EventId> file in the output directory
+  --write-retries      Retries for transient file write errors (default 2; 0 disables)
+  --compress           Gzip the log file, adding .gz to its name
+  --max-file-size      Roll over to <name>.1.log, <name>.2.log, ... once the log file reaches this size
+  --es-url             Also bulk-index events into Elasticsearch/OpenSearch at this URL
+  --es-index           Index to write to (default cloudtrail-logs)
+  --es-batch-size      Documents per _bulk request (default 500)
+  --syslog             Also send events as RFC5424 messages to [udp://|tcp://]host:port
+  --manifest           Write a JSON index of the files produced by the run
+
+Output Options:
+  --quiet             Skip per-event console output and the login banner (summary and files are kept)
+  --quiet-no-results  Print nothing at all when no events match
+  --no-results-exit-code  Exit code to use when --quiet-no-results finds nothing
+  --group-by-request  Present events sharing a CloudTrail requestID together
+  --order             Output order: newest (as returned, default) or oldest first
+  --timeline-by       Print each resource's events oldest first under its own header (resource)
+  --max-memory        Spool events held for --order oldest/--group-by-request/--timeline-by to disk past this size (e.g. 512MB)
+  --index             Number each event (#1, #2, ...) in console and file output
+  --show-cli          Print the equivalent aws cloudtrail lookup-events command
+  --ids-only          Print only the matched EventIds, one per line
+  --stdout-json       Print each matched event to stdout as one JSON object per line, for jq
+  --explain           Show which filters each matched event satisfied
+  --console-json      Print each event as indented JSON instead of the field layout
+  --truncate-values   Shorten values longer than N characters in console and log output
+  --histogram         Chart matched events per hour after the scan
+  --histogram-bucket  Bucket size for --histogram (e.g. 15m, 6h; default 1h)
+  --size-stats        Report total, average, median, and largest raw event size
+  --summary           Print event counts per event name, per user, and by outcome (default true)
+  --table             Show matched events as a table sized to the terminal
+  --normalize-arns    Canonicalize key ids, aliases, and ARNs to full ARNs
+  --explode-arns      Show each resource ARN's partition/service/region/account/resource
+  --alert-threshold   Flag users/keys with more than N events in the window
+  --alert-by          Count events per "user" or "key" (default user)
+  --alert-fail        Exit non-zero when the alert threshold is exceeded
+  --fail-on-error-events  Exit non-zero if any matched event has an errorCode (CI gating)
+  --expected-users-file   Flag events from principals not listed in this file
+  --baseline-file         Flag event+user combinations not in this saved baseline
+  --update-baseline       Add this run's combinations to --baseline-file (creates it)
+  --require-encryption-context  Flag Encrypt/Decrypt/GenerateDataKey* calls without an encryptionContext
+  --emf-output        Emit CloudWatch EMF metrics to a file, or "-" for stdout
+  --emf-namespace     CloudWatch namespace for EMF metrics
+  --state-file        Checkpoint pagination so an interrupted scan can resume
+  --checkpoint-file   Alias for --state-file
+  --throttle-cooldown  Pause this long when throttling outlasts retries, then resume (default 30s; 0 disables)
+  --throttle-resumes   How many times a scan may pause and resume after throttling (default 3)
+  --max-attempts      Attempts per LookupEvents call on throttling or transient errors
+  --concurrency       Workers that filter and parse each page's events (default: one per CPU)
+  --region-concurrency  Regions scanned in parallel when --region lists several (default 4);
+                      not --concurrency, which already sets the per-page workers
+  --no-progress       Don't show scan progress on stderr
+
+Environment:
+  Filter flags default to CLOUDTRAIL_LOGS_<FLAG> when not given on the command
+  line, e.g. CLOUDTRAIL_LOGS_USER, CLOUDTRAIL_LOGS_ROLE, CLOUDTRAIL_LOGS_HUMANS_ONLY=true.
+  Supported: key, event, user, operation, role, has-param, response-param,
+  source-ip, min-tls, error-message, errors-only, success-only, humans-only, console-only,
+  mutations-only, mutation-verbs, exclude-event.
 
 Examples:
   # Search all Decrypt operations
@@ -78,19 +262,178 @@ Examples:
   # Search all KMS operations by a user
   cloudtrail-logs kms --user admin --last-n 1h --export-file user-activity.json`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := applyEnvDefaults(cmd); err != nil {
+				return err
+			}
+
 			// Validate time range is provided
 			if lastN == "" && (startTime == "" || endTime == "") {
 				return fmt.Errorf("time range is required: use either --last-n or both --start and --end")
 			}
 
 			// Validate at least one search criteria is provided
-			if keyID == "" && eventName == "" && userName == "" && operation == "" {
-				return fmt.Errorf("at least one search criteria is required: --key, --event, --user, or --operation")
+			if keyID == "" && eventName == "" && userName == "" && operation == "" && role == "" && minTLS == "" && hasParam == "" && responseParam == "" && sourceIP == "" && !insightsOnly {
+				return fmt.Errorf("at least one search criteria is required: --key, --event, --user, --operation, --role, --min-tls, --has-param, --response-param, --source-ip, or --insights-only")
 			}
 
 			if errorsOnly && successOnly {
 				return fmt.Errorf("cannot use both --errors-only and --success-only")
 			}
+			if errorCode != "" && successOnly {
+				return fmt.Errorf("cannot use both --error-code and --success-only")
+			}
+
+			if err := writer.ValidateFormat(exportFormat); err != nil {
+				return err
+			}
+			if err := writer.ValidateFilenameTemplate(filenameTemplate); err != nil {
+				return err
+			}
+			if err := writer.ValidateSeparator(fileSeparator); err != nil {
+				return err
+			}
+
+			if splitFiles && (exportFile != "" || exportFormat == writer.FormatJSONDocument) {
+				return fmt.Errorf("--split-files writes to the output directory and can't be combined with --export-file or --export-format %s", writer.FormatJSONDocument)
+			}
+			if splitFiles && compress {
+				return fmt.Errorf("cannot use --compress with --split-files")
+			}
+			if maxFileSize != "" {
+				size, err := bytesize.Parse(maxFileSize)
+				if err != nil {
+					return fmt.Errorf("invalid --max-file-size: %v", err)
+				}
+				if size <= 0 {
+					return fmt.Errorf("--max-file-size must be greater than zero")
+				}
+				if splitFiles || exportFormat == writer.FormatJSONDocument {
+					return fmt.Errorf("--max-file-size only applies to appended log files and can't be combined with --split-files or --export-format %s", writer.FormatJSONDocument)
+				}
+			}
+
+			for _, ignored := range flagsIgnoredByExportFile(cmd) {
+				fmt.Fprintf(os.Stderr, theme.Warning("Warning: --%s is ignored because --export-file is set\n"), ignored)
+			}
+			if esURL != "" {
+				if err := elastic.ValidateURL(esURL); err != nil {
+					return err
+				}
+				if esBatchSize < 1 {
+					return fmt.Errorf("--es-batch-size must be at least 1")
+				}
+			}
+			if syslogAddr != "" {
+				if _, _, err := syslog.ParseAddress(syslogAddr); err != nil {
+					return err
+				}
+			}
+
+			if errorMessage != "" {
+				if _, err := monitor.CompileErrorMessage(errorMessage); err != nil {
+					return err
+				}
+			}
+			if responseParam != "" {
+				if err := monitor.ValidateResponseParam(responseParam); err != nil {
+					return err
+				}
+			}
+			if sourceIP != "" {
+				if err := monitor.ValidateSourceIP(sourceIP); err != nil {
+					return err
+				}
+			}
+			if minTLS != "" {
+				if err := monitor.ValidateTLSVersion(minTLS); err != nil {
+					return err
+				}
+			}
+
+			if (minSeverity != "" || alertSeverity != "") && classificationFile == "" {
+				return fmt.Errorf("--min-severity and --alert-severity require --classification-file")
+			}
+
+			if sampleRate < 0 || sampleRate > 1 {
+				return fmt.Errorf("--sample-rate must be between 0 and 1")
+			}
+
+			if truncateValuesAt < 0 {
+				return fmt.Errorf("--truncate-values must be a positive number")
+			}
+
+			if histogramBucket <= 0 {
+				return fmt.Errorf("--histogram-bucket must be a positive duration")
+			}
+
+			if alertThreshold < 0 {
+				return fmt.Errorf("--alert-threshold must be a positive number")
+			}
+			if err := monitor.ValidateAlertBy(alertBy); err != nil {
+				return err
+			}
+			if err := monitor.ValidateOrder(order); err != nil {
+				return err
+			}
+			if err := monitor.ValidateTimelineBy(timelineBy); err != nil {
+				return err
+			}
+			if timelineBy != "" && groupByRequest {
+				return fmt.Errorf("cannot use --timeline-by with --group-by-request")
+			}
+			if maxMemory != "" {
+				if _, err := bytesize.Parse(maxMemory); err != nil {
+					return fmt.Errorf("invalid --max-memory: %v", err)
+				}
+			}
+
+			// Grouped events are only written at the end, so a checkpoint would skip them on resume
+			if stateFile != "" && groupByRequest {
+				return fmt.Errorf("cannot use --state-file with --group-by-request")
+			}
+			if stateFile != "" && timelineBy != "" {
+				return fmt.Errorf("cannot use --state-file with --timeline-by")
+			}
+			if consoleJSON && tableOutput {
+				return fmt.Errorf("cannot use --console-json with --table")
+			}
+			if stdoutJSON && idsOnly {
+				return fmt.Errorf("cannot use --stdout-json with --ids-only")
+			}
+			if updateBaseline && baselineFile == "" {
+				return fmt.Errorf("--update-baseline requires --baseline-file")
+			}
+			if writeRetries < 0 {
+				return fmt.Errorf("--write-retries cannot be negative")
+			}
+			if throttleCooldown < 0 || throttleResumes < 0 {
+				return fmt.Errorf("--throttle-cooldown and --throttle-resumes cannot be negative")
+			}
+			if regionConcurrency < 1 {
+				return fmt.Errorf("--region-concurrency must be at least 1")
+			}
+			if maxAttempts < 1 {
+				return fmt.Errorf("--max-attempts must be at least 1")
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+			if stateFile != "" && order == monitor.OrderOldest {
+				return fmt.Errorf("cannot use --state-file with --order %s", monitor.OrderOldest)
+			}
+			// A resumed run would overwrite the document with only the remaining events
+			if stateFile != "" && exportFormat == writer.FormatJSONDocument {
+				return fmt.Errorf("cannot use --state-file with --export-format %s", writer.FormatJSONDocument)
+			}
+			// The checkpoint holds a single pagination token
+			if region, _ := cmd.Flags().GetString("region"); stateFile != "" && len(aws.SplitRegions(region)) > 1 {
+				return fmt.Errorf("cannot use --state-file with more than one region")
+			}
+			// A document holds every event, so it can't be split up by region
+			perRegion := regionDirs || strings.Contains(filenameTemplate, "{region}")
+			if region, _ := cmd.Flags().GetString("region"); perRegion && exportFormat == writer.FormatJSONDocument && exportFile == "" && len(aws.SplitRegions(region)) > 1 {
+				return fmt.Errorf("--export-format %s writes a single file and can't be split by region with --region-dirs or {region}", writer.FormatJSONDocument)
+			}
 
 			return nil
 		},
@@ -99,62 +442,317 @@ Examples:
 
 	// Search flags
 	kmsCmd.Flags().StringVar(&keyID, "key", "", "Optional: Filter by KMS key ID or ARN")
-	kmsCmd.Flags().StringVar(&eventName, "event", "", "Filter by event name")
+	kmsCmd.Flags().StringVar(&eventName, "event", "", "Filter by event name, or several comma-separated (e.g. Decrypt,Encrypt)")
 	kmsCmd.Flags().StringVar(&userName, "user", "", "Filter by username")
 	kmsCmd.Flags().StringVar(&operation, "operation", "", "Filter by operation type")
+	kmsCmd.Flags().StringVar(&role, "role", "", "Filter by assumed-role session issuer name")
+	kmsCmd.Flags().StringVar(&hasParam, "has-param", "", "Keep events whose requestParameters contain this (dotted) key")
+	kmsCmd.Flags().StringVar(&responseParam, "response-param", "", "Keep events whose responseElements hold key=value (dotted key)")
+	kmsCmd.Flags().StringVar(&sourceIP, "source-ip", "", "Keep events from this IP address or CIDR (e.g. 203.0.113.7, 10.0.0.0/8)")
+	kmsCmd.Flags().StringVar(&minTLS, "min-tls", "", "Keep only calls made over a TLS version below this (e.g. 1.2)")
 
 	// Time range flags
-	kmsCmd.Flags().StringVar(&lastN, "last-n", "", "Look back time (e.g., 5m, 2h)")
+	kmsCmd.Flags().StringVar(&lastN, "last-n", "", "Look back time (e.g., 5m, 2h, 7d, yesterday, this-week)")
 	kmsCmd.Flags().StringVar(&startTime, "start", "", "Start time")
 	kmsCmd.Flags().StringVar(&endTime, "end", "", "End time")
 
 	// Filter flags
+	kmsCmd.Flags().StringVar(&errorMessage, "error-message", "", "Keep events whose errorMessage matches this text or regular expression (case-insensitive)")
 	kmsCmd.Flags().BoolVar(&errorsOnly, "errors-only", false, "Show only error events")
+	kmsCmd.Flags().StringVar(&errorCode, "error-code", "", "Show only events with this errorCode, e.g. AccessDenied (case-insensitive; implies --errors-only)")
 	kmsCmd.Flags().BoolVar(&successOnly, "success-only", false, "Show only successful events")
+	kmsCmd.Flags().BoolVar(&humansOnly, "humans-only", false, "Keep only IAM users, assumed roles, root, and federated users")
+	kmsCmd.Flags().BoolVar(&mutations, "mutations-only", false, "Keep only events whose names start with a state-changing verb")
+	kmsCmd.Flags().StringSliceVar(&mutationVerbs, "mutation-verbs", monitor.DefaultMutationVerbs, "Verbs matched by --mutations-only")
+	kmsCmd.Flags().StringSliceVar(&excludeEvents, "exclude-event", nil, "Drop events with this exact name, ignoring case (repeatable)")
+	kmsCmd.Flags().BoolVar(&consoleOnly, "console-only", false, "Keep only requests made from the AWS Management Console")
+	kmsCmd.Flags().BoolVar(&insightsOnly, "insights-only", false, "Show only CloudTrail Insights anomaly events")
+	kmsCmd.Flags().BoolVar(&exactMatch, "exact", false, "Match --event and --user exactly (case-sensitive) so CloudTrail can filter them server-side")
+	kmsCmd.Flags().BoolVar(&clientSideOnly, "client-side-only", false, "Disable server-side lookup filtering")
+	kmsCmd.Flags().BoolVar(&includeMalformed, "include-malformed", false, "Keep events missing EventName/EventTime using placeholder values")
+	kmsCmd.Flags().Float64Var(&sampleRate, "sample-rate", 0, "Keep only this fraction of matched events (0 disables sampling)")
+	kmsCmd.Flags().Int64Var(&seed, "seed", 1, "Seed for reproducible sampling")
+
+	// Classification flags
+	kmsCmd.Flags().StringVar(&classificationFile, "classification-file", "", "YAML/JSON file mapping event names to severities and tags")
+	kmsCmd.Flags().StringVar(&minSeverity, "min-severity", "", "Show only events at or above this severity (info, low, medium, high, critical)")
+	kmsCmd.Flags().StringVar(&alertSeverity, "alert-severity", "", "Alert on events at or above this severity")
 
 	// Export flags
 	kmsCmd.Flags().StringVar(&exportFile, "export-file", "", "Export to specific file")
-	kmsCmd.Flags().StringVar(&exportFormat, "export-format", "text", "Export format (text or json)")
+	kmsCmd.Flags().StringVar(&exportFormat, "export-format", "text", "Export format (text, json, ndjson, json-document, cloudevents, or native)")
+	kmsCmd.Flags().StringVar(&fileSeparator, "file-separator", writer.SeparatorLine, "Separator between events in text log files (line, blank, or none)")
+	kmsCmd.Flags().IntVar(&writeRetries, "write-retries", 2, "Retries with backoff for transient log file errors (permission errors are not retried)")
+	kmsCmd.Flags().BoolVar(&splitFiles, "split-files", false, "Write each matched event to its own file named by EventId")
+	kmsCmd.Flags().BoolVar(&compress, "compress", false, "Gzip the log file, adding .gz to its name")
+	kmsCmd.Flags().StringVar(&maxFileSize, "max-file-size", "", "Roll over to a numbered log file once the current one reaches this size (e.g. 100MB)")
+	kmsCmd.Flags().BoolVar(&batchWrites, "batch-writes", false, "Write matched events once per page instead of per event")
+	kmsCmd.Flags().StringVar(&esURL, "es-url", "", "Bulk-index matched events into Elasticsearch/OpenSearch at this URL")
+	kmsCmd.Flags().StringVar(&esIndex, "es-index", elastic.DefaultIndex, "Elasticsearch/OpenSearch index name")
+	kmsCmd.Flags().IntVar(&esBatchSize, "es-batch-size", elastic.DefaultBatchSize, "Documents per _bulk request")
+	kmsCmd.Flags().StringVar(&syslogAddr, "syslog", "", "Send matched events as RFC5424 messages to [udp://|tcp://]host:port")
+	kmsCmd.Flags().BoolVar(&regionDirs, "region-dirs", false, "Write log files under <output>/<service>/<region>/ so multi-region output stays separate")
+	kmsCmd.Flags().StringVar(&filenameTemplate, "filename-template", writer.DefaultFilenameTemplate, "Log filename template (placeholders: {service}, {date}, {region}, {profile})")
+
+	// Output flags
+	kmsCmd.Flags().BoolVar(&quietNoResults, "quiet-no-results", false, "Print nothing when no events match")
+	kmsCmd.Flags().IntVar(&noResultsExitCode, "no-results-exit-code", 0, "Exit code when --quiet-no-results finds no events")
+	kmsCmd.Flags().BoolVar(&showIndex, "index", false, "Prefix each event with a sequence number (added as \"index\" in json)")
+	kmsCmd.Flags().BoolVar(&histogramOn, "histogram", false, "Print an ASCII chart of matched events per time bucket")
+	kmsCmd.Flags().DurationVar(&histogramBucket, "histogram-bucket", time.Hour, "Bucket size for --histogram")
+	kmsCmd.Flags().BoolVar(&summary, "summary", true, "Print counts per event name, per user, and errors vs successes at the end (--summary=false to skip)")
+	kmsCmd.Flags().BoolVar(&sizeStats, "size-stats", false, "Report total, average, median, and largest size of matched event JSON")
+	kmsCmd.Flags().IntVar(&truncateValuesAt, "truncate-values", 0, "Shorten string values longer than N characters (0 keeps them whole)")
+	kmsCmd.Flags().BoolVar(&consoleJSON, "console-json", false, "Print each matched event as indented JSON, like the json export")
+	kmsCmd.Flags().BoolVar(&explain, "explain", false, "Print which filters each matched event satisfied")
+	kmsCmd.Flags().BoolVar(&idsOnly, "ids-only", false, "Print only matched EventIds, one per line")
+	kmsCmd.Flags().BoolVar(&stdoutJSON, "stdout-json", false, "Print each matched event to stdout as one JSON object per line, for jq (file export is unaffected)")
+	kmsCmd.Flags().BoolVar(&tableOutput, "table", false, "Show matched events as an aligned table instead of blocks")
+	kmsCmd.Flags().BoolVar(&showCLI, "show-cli", false, "Print the equivalent AWS CLI lookup-events command before scanning")
+	kmsCmd.Flags().BoolVar(&groupByRequest, "group-by-request", false, "Group events that share a CloudTrail requestID")
+	kmsCmd.Flags().StringVar(&maxMemory, "max-memory", "", "Spool held-back events to a temp file once they pass this size (e.g. 512MB)")
+	kmsCmd.Flags().StringVar(&order, "order", monitor.OrderNewest, "Output order (newest or oldest first)")
+	kmsCmd.Flags().StringVar(&timelineBy, "timeline-by", "", "Show a chronological timeline per resource (resource)")
+	kmsCmd.Flags().BoolVar(&explodeARNs, "explode-arns", false, "Print resource ARNs split into partition, service, region, account, and resource")
+	kmsCmd.Flags().BoolVar(&normalizeARNs, "normalize-arns", false, "Canonicalize resource identifiers to full ARNs in output and grouping")
+	kmsCmd.Flags().IntVar(&alertThreshold, "alert-threshold", 0, "Flag principals with more than N events (0 disables)")
+	kmsCmd.Flags().StringVar(&alertBy, "alert-by", monitor.AlertByUser, "Principal to count for alerting (user or key)")
+	kmsCmd.Flags().BoolVar(&alertFail, "alert-fail", false, "Exit non-zero when the alert threshold is exceeded")
+	kmsCmd.Flags().BoolVar(&requireContext, "require-encryption-context", false, "Flag Encrypt/Decrypt/GenerateDataKey* calls made without an encryptionContext")
+	kmsCmd.Flags().StringVar(&baselineFile, "baseline-file", "", "JSON file of known event+user combinations; new ones are flagged")
+	kmsCmd.Flags().BoolVar(&updateBaseline, "update-baseline", false, "Add this run's combinations to --baseline-file, creating it if needed")
+	kmsCmd.Flags().StringVar(&expectedUsersFile, "expected-users-file", "", "File of expected usernames/ARNs, one per line; others are flagged")
+	kmsCmd.Flags().BoolVar(&failOnErrors, "fail-on-error-events", false, "Exit non-zero if any matched event has an errorCode")
+	kmsCmd.Flags().StringVar(&emfOutput, "emf-output", "", "Write CloudWatch EMF metrics to this file (\"-\" for stdout)")
+	kmsCmd.Flags().StringVar(&stateFile, "state-file", "", "Checkpoint pagination to this file and resume from it on the next run")
+	kmsCmd.Flags().StringVar(&stateFile, "checkpoint-file", "", "Alias for --state-file")
+	kmsCmd.Flags().DurationVar(&throttleCooldown, "throttle-cooldown", 30*time.Second, "Pause this long when CloudTrail throttling outlasts retries, then resume from the same page")
+	kmsCmd.Flags().IntVar(&throttleResumes, "throttle-resumes", 3, "Maximum throttling pauses per scan before giving up")
+	kmsCmd.Flags().IntVar(&regionConcurrency, "region-concurrency", monitor.DefaultRegionConcurrency, "Regions scanned in parallel when --region lists several")
+	kmsCmd.Flags().BoolVar(&noProgress, "no-progress", false, "Don't show scan progress on stderr")
+	kmsCmd.Flags().IntVar(&maxAttempts, "max-attempts", retry.Default.MaxAttempts, "Attempts per LookupEvents call on throttling or transient errors, with jittered exponential backoff")
+	kmsCmd.Flags().IntVar(&concurrency, "concurrency", runtime.GOMAXPROCS(0), "Workers that filter and parse each page's events (pages are still fetched in order)")
+	kmsCmd.Flags().StringVar(&manifestFile, "manifest", "", "Write a JSON index of the files produced (path, format, region, event count) here")
+	kmsCmd.Flags().StringVar(&emfNamespace, "emf-namespace", emf.DefaultNamespace, "CloudWatch namespace for EMF metrics")
 
 	return kmsCmd
 }
 
+// flagsIgnoredByExportFile lists the log directory layout flags given along
+// with --export-file, which takes precedence over them
+func flagsIgnoredByExportFile(cmd *cobra.Command) []string {
+	if exportFile, _ := cmd.Flags().GetString("export-file"); exportFile == "" {
+		return nil
+	}
+	var ignored []string
+	for _, flag := range []string{"output", "filename-template", "region-dirs"} {
+		if cmd.Flags().Changed(flag) {
+			ignored = append(ignored, flag)
+		}
+	}
+	return ignored
+}
+
 func runKMS(cmd *cobra.Command, args []string) error {
 	start, end, err := timeutil.ValidateAndParseTimeRange(lastN, startTime, endTime)
 	if err != nil {
 		return err
 	}
 
-	ctx := context.Background()
+	// Ctrl-C stops the scan between pages; what matched so far is still
+	// written, summarized, and checkpointed
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 	profile, _ := cmd.Flags().GetString("profile")
 	region, _ := cmd.Flags().GetString("region")
+	if !cmd.Flags().Changed("region") {
+		// Let the profile's configured region take precedence over the default
+		region = ""
+	}
 	outputDir, _ := cmd.Flags().GetString("output")
 
+	// Console output is held back until the first match when quiet on no results
+	var console io.Writer = os.Stdout
+	if idsOnly || stdoutJSON {
+		console = io.Discard
+	} else if quietNoResults {
+		console = monitor.NewDeferredWriter(os.Stdout)
+	}
+
+	// Quiet runs skip the login banner along with the per-event output
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	banner := console
+	if quiet {
+		banner = io.Discard
+	}
+
 	// Initialize AWS client
-	client, err := aws.NewAWSClient(ctx, profile, region)
+	client, err := aws.NewAWSClient(ctx, profile, region, banner)
 	if err != nil {
 		return fmt.Errorf("AWS client initialization failed:\n%v", err)
 	}
 
+	// Load classification rules
+	var classifier *classify.Classifier
+	minSeverityLevel, alertSeverityLevel := classify.SeverityNone, classify.SeverityNone
+	if classificationFile != "" {
+		classifier, err = classify.Load(classificationFile)
+		if err != nil {
+			return err
+		}
+		if minSeverity != "" {
+			if minSeverityLevel, err = classify.ParseSeverity(minSeverity); err != nil {
+				return err
+			}
+		}
+		if alertSeverity != "" {
+			if alertSeverityLevel, err = classify.ParseSeverity(alertSeverity); err != nil {
+				return err
+			}
+		}
+	}
+
+	var expectedUsers monitor.ExpectedUsers
+	if expectedUsersFile != "" {
+		if expectedUsers, err = monitor.LoadExpectedUsers(expectedUsersFile); err != nil {
+			return err
+		}
+	}
+
+	var baseline *monitor.Baseline
+	if baselineFile != "" {
+		if baseline, err = monitor.LoadBaseline(baselineFile, updateBaseline); err != nil {
+			return err
+		}
+	}
+
+	// Already validated in PreRunE
+	var errorMessagePattern *regexp.Regexp
+	if errorMessage != "" {
+		errorMessagePattern, _ = monitor.CompileErrorMessage(errorMessage)
+	}
+
 	// Create filter options
 	filters := monitor.FilterOptions{
-		KeyID:       keyID,
-		EventName:   eventName,
-		UserName:    userName,
-		Operation:   operation,
-		ErrorsOnly:  errorsOnly,
-		SuccessOnly: successOnly,
+		KeyID:         keyID,
+		EventName:     eventName,
+		UserName:      userName,
+		Operation:     operation,
+		ErrorsOnly:    errorsOnly,
+		ErrorCode:     errorCode,
+		SuccessOnly:   successOnly,
+		Role:          role,
+		MinTLS:        minTLS,
+		HumansOnly:    humansOnly,
+		ConsoleOnly:   consoleOnly,
+		MutationsOnly: mutations,
+		MutationVerbs: mutationVerbs,
+		ExcludeEvents: excludeEvents,
+		HasParam:      hasParam,
+		ResponseParam: responseParam,
+		SourceIP:      sourceIP,
+		ErrorMessage:  errorMessagePattern,
+		SampleRate:    sampleRate,
+		Seed:          seed,
+
+		IncludeMalformed: includeMalformed,
+		InsightsOnly:     insightsOnly,
+		Exact:            exactMatch,
+		ClientSideOnly:   clientSideOnly,
+		Classifier:       classifier,
+		MinSeverity:      minSeverityLevel,
 	}
 
 	// Create export options
 	exportOptions := &writer.ExportOptions{
-		Filename: exportFile,
-		Format:   exportFormat,
+		Filename:         exportFile,
+		Format:           exportFormat,
+		FilenameTemplate: filenameTemplate,
+		Separator:        fileSeparator,
+		SplitFiles:       splitFiles,
+		Compress:         compress,
+		RegionDirs:       regionDirs,
+		WriteRetries:     writeRetries,
+		Region:           client.Region,
+		Profile:          profile,
+	}
+
+	// Already validated in PreRunE
+	var maxMemoryBytes int64
+	if maxMemory != "" {
+		maxMemoryBytes, _ = bytesize.Parse(maxMemory)
+	}
+	if maxFileSize != "" {
+		exportOptions.MaxFileSize, _ = bytesize.Parse(maxFileSize)
+	}
+
+	// Create output options
+	outputOptions := &monitor.OutputOptions{
+		Console:           console,
+		Quiet:             quiet,
+		Progress:          !noProgress && !quiet && !quietNoResults,
+		QuietNoResults:    quietNoResults,
+		GroupByRequest:    groupByRequest,
+		Order:             order,
+		TimelineBy:        timelineBy,
+		MaxMemory:         maxMemoryBytes,
+		ShowIndex:         showIndex,
+		ShowCLI:           showCLI,
+		IDsOnly:           idsOnly,
+		StdoutJSON:        stdoutJSON,
+		TruncateValues:    truncateValuesAt,
+		Histogram:         histogramOn,
+		HistogramBucket:   histogramBucket,
+		Explain:           explain,
+		SizeStats:         sizeStats,
+		Summary:           summary,
+		ConsoleJSON:       consoleJSON,
+		ESURL:             esURL,
+		ESIndex:           esIndex,
+		ESBatchSize:       esBatchSize,
+		Syslog:            syslogAddr,
+		Table:             tableOutput,
+		AlertThreshold:    alertThreshold,
+		AlertBy:           alertBy,
+		AlertFail:         alertFail,
+		FailOnErrorEvents: failOnErrors,
+		ExpectedUsers:     expectedUsers,
+		Baseline:          baseline,
+
+		RequireEncryptionContext: requireContext,
+		AlertSeverity:            alertSeverityLevel,
+		EMFOutput:                emfOutput,
+		EMFNamespace:             emfNamespace,
+		StateFile:                stateFile,
+		ExactWindow:              lastN == "",
+		Manifest:                 manifestFile,
+		ThrottleCooldown:         throttleCooldown,
+		ThrottleResumes:          throttleResumes,
+		RegionConcurrency:        regionConcurrency,
+		BatchWrites:              batchWrites,
+		Concurrency:              concurrency,
+		MaxAttempts:              maxAttempts,
+		NormalizeARNs:            normalizeARNs,
+		ExplodeARNs:              explodeARNs,
 	}
 
 	// Initialize monitor
-	kmsMonitor := monitor.NewKMSMonitor(client, outputDir, exportOptions)
+	kmsMonitor := monitor.NewKMSMonitor(client, outputDir, exportOptions, outputOptions)
 
 	// Run monitoring with filters
-	return kmsMonitor.MonitorKMSEvents(ctx, filters, start, end)
+	err = kmsMonitor.MonitorKMSEvents(ctx, filters, start, end)
+	if errors.Is(err, monitor.ErrInterrupted) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &exit.Error{Code: exit.Interrupted}
+	}
+	if errors.Is(err, monitor.ErrNoResults) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		if noResultsExitCode != 0 {
+			return &exit.Error{Code: noResultsExitCode}
+		}
+		return nil
+	}
+	return err
 }
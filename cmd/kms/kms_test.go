@@ -0,0 +1,40 @@
+// cmd/kms/kms_test.go
+package kms
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// parsedKMSCmd returns the kms command, under a root carrying the global
+// --output flag, with args parsed
+func parsedKMSCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	root := &cobra.Command{Use: "cloudtrail-logs"}
+	root.PersistentFlags().String("output", "logs", "")
+	cmd := NewKMSCmd()
+	root.AddCommand(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestFlagsIgnoredByExportFile(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--export-file", "out.json", "--output", "/tmp/logs"}, []string{"output"}},
+		{[]string{"--export-file", "out.json", "--filename-template", "{date}.log", "--region-dirs"}, []string{"filename-template", "region-dirs"}},
+		{[]string{"--export-file", "out.json"}, nil},
+		{[]string{"--output", "/tmp/logs", "--region-dirs"}, nil}, // no export file to take precedence
+	}
+	for _, tc := range tests {
+		if got := flagsIgnoredByExportFile(parsedKMSCmd(t, tc.args...)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%v: ignored %v, want %v", tc.args, got, tc.want)
+		}
+	}
+}
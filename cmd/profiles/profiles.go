@@ -0,0 +1,24 @@
+// cmd/profiles/profiles.go
+package profiles
+
+import (
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+func NewProfilesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "profiles",
+		Aliases: []string{"list-profiles"},
+		Short:   "List the AWS profiles available to --profile",
+		Long: `List the profiles configured in the AWS credentials and config files, so you
+can pick one for --profile before running a scan.
+
+Examples:
+  cloudtrail-logs profiles`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			aws.PrintAWSProfiles()
+		},
+	}
+}
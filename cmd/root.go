@@ -1,18 +1,37 @@
 package cmd
 
 import (
-	"github.com/dhairya13703/cloudtrail-logs/cmd/kms"
-	// "github.com/dhairya13703/cloudtrail-logs/cmd/sns"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/dhairya13703/cloudtrail-logs/cmd/convert"
+	"github.com/dhairya13703/cloudtrail-logs/cmd/digest"
+	"github.com/dhairya13703/cloudtrail-logs/cmd/earliest"
+	"github.com/dhairya13703/cloudtrail-logs/cmd/ec2"
+	"github.com/dhairya13703/cloudtrail-logs/cmd/kms"
+	"github.com/dhairya13703/cloudtrail-logs/cmd/profiles"
+	"github.com/dhairya13703/cloudtrail-logs/cmd/sns"
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/config"
+	"github.com/dhairya13703/cloudtrail-logs/internal/terminal"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
 	"github.com/spf13/cobra"
 )
 
 var (
-	profile   string
-	region    string
-	outputDir string
+	profile    string
+	region     string
+	outputDir  string
+	noColor    bool
+	legend     bool
+	configFile string
+	timezone   string
+	quiet      bool
+	assumeRole string
+	externalID string
+	allRegions bool
 )
 
 var rootCmd = &cobra.Command{
@@ -20,6 +39,37 @@ var rootCmd = &cobra.Command{
 	Short: "AWS Resource Monitor - CloudTrail event monitoring tool",
 	Long: `AWS Resource Monitor helps you track AWS resource usage through CloudTrail logs.
 It supports monitoring various services like KMS, EC2, SNS, and more.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Colors are only for people watching a terminal, not pipes or CI logs
+		theme.SetColorEnabled(!noColor && terminal.Interactive(os.Stdout))
+		if err := timeutil.SetLocation(timezone); err != nil {
+			return err
+		}
+		if err := aws.SetAssumeRole(assumeRole, externalID); err != nil {
+			return err
+		}
+		if allRegions {
+			if cmd.Flags().Changed("region") {
+				return fmt.Errorf("--all-regions and --region are mutually exclusive")
+			}
+			if err := cmd.Flags().Set("region", strings.Join(aws.DefaultRegions, ",")); err != nil {
+				return err
+			}
+		}
+		if legend {
+			fmt.Println(theme.Legend())
+		}
+
+		path, explicit := configFile, cmd.Flags().Changed("config")
+		if !explicit {
+			path = config.DefaultPath()
+		}
+		cfg, err := config.Load(path, explicit)
+		if err != nil {
+			return err
+		}
+		return timeutil.SetPresets(cfg.TimeRanges)
+	},
 }
 
 func Execute() error {
@@ -35,11 +85,23 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&profile, "profile", "default", "AWS profile to use")
-	rootCmd.PersistentFlags().StringVar(&region, "region", "us-east-1", "AWS region to monitor")
+	rootCmd.PersistentFlags().StringVar(&assumeRole, "assume-role-arn", "", "IAM role to assume with the profile's credentials (e.g. a central CloudTrail account)")
+	rootCmd.PersistentFlags().StringVar(&externalID, "external-id", "", "External ID to pass when assuming --assume-role-arn")
+	rootCmd.PersistentFlags().StringVar(&region, "region", aws.DefaultRegion, "AWS region to monitor, or a comma-separated list (defaults to the profile's region)")
+	rootCmd.PersistentFlags().BoolVar(&allRegions, "all-regions", false, "Scan every region enabled by default in AWS accounts")
 	rootCmd.PersistentFlags().StringVar(&outputDir, "output", defaultOutputDir, "Directory for log files")
+	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&quiet, "quiet", false, "Skip per-event console output and the login banner; files and the final summary are still written")
+	rootCmd.PersistentFlags().BoolVar(&legend, "legend", false, "Print what each output color means")
+	rootCmd.PersistentFlags().StringVar(&timezone, "timezone", "UTC", "Time zone for --start/--end and displayed times (e.g. America/New_York, Local)")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file (default $XDG_CONFIG_HOME/cloudtrail-logs/config.yaml)")
 
 	// Add service commands
 	rootCmd.AddCommand(kms.NewKMSCmd())
-	// rootCmd.AddCommand(ec2.NewEC2Cmd())
-	// rootCmd.AddCommand(sns.NewSNSCmd())
-}
\ No newline at end of file
+	rootCmd.AddCommand(digest.NewDigestCmd())
+	rootCmd.AddCommand(profiles.NewProfilesCmd())
+	rootCmd.AddCommand(convert.NewConvertCmd())
+	rootCmd.AddCommand(earliest.NewEarliestCmd())
+	rootCmd.AddCommand(ec2.NewEC2Cmd())
+	rootCmd.AddCommand(sns.NewSNSCmd())
+}
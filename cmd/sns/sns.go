@@ -0,0 +1,142 @@
+// cmd/sns/sns.go
+package sns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/exit"
+	"github.com/dhairya13703/cloudtrail-logs/internal/monitor"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+	"github.com/spf13/cobra"
+)
+
+var (
+	topicARN  string
+	eventName string
+	userName  string
+	sourceIP  string
+
+	// Time filters
+	lastN     string
+	startTime string
+	endTime   string
+
+	// Export options
+	exportFile   string
+	exportFormat string
+)
+
+func NewSNSCmd() *cobra.Command {
+	snsCmd := &cobra.Command{
+		Use:   "sns",
+		Short: "Monitor SNS events",
+		Long: `Monitor AWS SNS API activity through CloudTrail logs.
+
+Search Options:
+  --topic-arn    Filter by topic ARN (in resources or request parameters)
+  --event        Filter by event name (e.g., "Publish", "SetTopicAttributes")
+  --user         Filter by username
+  --source-ip    Filter by caller IP address or CIDR (IPv4 or IPv6)
+
+Time Range Options:
+  --last-n       Look back time (e.g., 30m, 2h, 7d, yesterday, this-week)
+  --start/--end  Custom time range (YYYY-MM-DD HH:mm:ss, YYYY-MM-DD HH:mm, or YYYY-MM-DD)
+
+Export Options:
+  --export-file    Export to specific file, named pipe, or Unix socket
+  --export-format  Export format (text, json, ndjson, json-document, cloudevents, or native)
+
+Examples:
+  # Everything that happened to a topic in the last two hours
+  cloudtrail-logs sns --topic-arn arn:aws:sns:us-east-1:123456789012:alerts --last-n 2h
+
+  # Subscription changes yesterday
+  cloudtrail-logs sns --event Subscribe --last-n yesterday --export-format json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if lastN == "" && (startTime == "" || endTime == "") {
+				return fmt.Errorf("time range is required: use either --last-n or both --start and --end")
+			}
+			if topicARN == "" && eventName == "" && userName == "" && sourceIP == "" {
+				return fmt.Errorf("at least one search criteria is required: --topic-arn, --event, --user, or --source-ip")
+			}
+			if sourceIP != "" {
+				if err := monitor.ValidateSourceIP(sourceIP); err != nil {
+					return err
+				}
+			}
+			return writer.ValidateFormat(exportFormat)
+		},
+		RunE: runSNS,
+	}
+
+	snsCmd.Flags().StringVar(&topicARN, "topic-arn", "", "Filter by SNS topic ARN")
+	snsCmd.Flags().StringVar(&eventName, "event", "", "Filter by event name, or several comma-separated")
+	snsCmd.Flags().StringVar(&userName, "user", "", "Filter by username")
+	snsCmd.Flags().StringVar(&sourceIP, "source-ip", "", "Filter by caller IP address or CIDR")
+	snsCmd.Flags().StringVar(&lastN, "last-n", "", "Look back time (e.g., 5m, 2h, 7d, yesterday, this-week)")
+	snsCmd.Flags().StringVar(&startTime, "start", "", "Start time")
+	snsCmd.Flags().StringVar(&endTime, "end", "", "End time")
+	snsCmd.Flags().StringVar(&exportFile, "export-file", "", "Export to specific file")
+	snsCmd.Flags().StringVar(&exportFormat, "export-format", "text", "Export format (text, json, ndjson, json-document, cloudevents, or native)")
+
+	return snsCmd
+}
+
+func runSNS(cmd *cobra.Command, args []string) error {
+	start, end, err := timeutil.ValidateAndParseTimeRange(lastN, startTime, endTime)
+	if err != nil {
+		return err
+	}
+
+	// Ctrl-C stops the scan between pages; what matched so far is still reported
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	profile, _ := cmd.Flags().GetString("profile")
+	region, _ := cmd.Flags().GetString("region")
+	if !cmd.Flags().Changed("region") {
+		// Let the profile's configured region take precedence over the default
+		region = ""
+	}
+	outputDir, _ := cmd.Flags().GetString("output")
+
+	// Quiet runs skip the login banner along with the per-event output
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	var banner io.Writer
+	if quiet {
+		banner = io.Discard
+	}
+
+	client, err := aws.NewAWSClient(ctx, profile, region, banner)
+	if err != nil {
+		return fmt.Errorf("AWS client initialization failed:\n%v", err)
+	}
+
+	filters := monitor.FilterOptions{
+		TopicARN:  topicARN,
+		EventName: eventName,
+		UserName:  userName,
+		SourceIP:  sourceIP,
+	}
+	exportOptions := &writer.ExportOptions{
+		Filename: exportFile,
+		Format:   exportFormat,
+		Region:   client.Region,
+		Profile:  profile,
+	}
+
+	snsMonitor := monitor.NewSNSMonitor(client, outputDir, exportOptions, &monitor.OutputOptions{Quiet: quiet})
+	err = snsMonitor.MonitorSNSEvents(ctx, filters, start, end)
+	if errors.Is(err, monitor.ErrInterrupted) {
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+		return &exit.Error{Code: exit.Interrupted}
+	}
+	return err
+}
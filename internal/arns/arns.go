@@ -0,0 +1,94 @@
+// internal/arns/arns.go
+package arns
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+// Bare KMS key ids, including multi-Region keys (mrk-...)
+var kmsKeyIDPattern = regexp.MustCompile(`^(mrk-[0-9a-f]{32}|[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})$`)
+
+// Context supplies the partition, region, and account used to expand
+// identifiers that CloudTrail recorded without them
+type Context struct {
+	Partition string
+	Region    string
+	Account   string
+}
+
+// Normalize canonicalizes a resource identifier so that the different forms
+// CloudTrail uses for the same resource compare equal. KMS key ids, "key/<id>",
+// and "alias/<name>" are expanded to full ARNs when the region and account are
+// known. Other values are returned trimmed but otherwise unchanged.
+func Normalize(id string, ctx Context) string {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return id
+	}
+
+	if arn.IsARN(id) {
+		parsed, err := arn.Parse(id)
+		if err != nil {
+			return id
+		}
+		return parsed.String()
+	}
+
+	var resource string
+	switch {
+	case kmsKeyIDPattern.MatchString(strings.ToLower(id)):
+		resource = "key/" + strings.ToLower(id)
+	case strings.HasPrefix(id, "key/"):
+		resource = "key/" + strings.ToLower(strings.TrimPrefix(id, "key/"))
+	case strings.HasPrefix(id, "alias/"):
+		resource = id
+	default:
+		return id
+	}
+
+	if ctx.Region == "" || ctx.Account == "" {
+		return resource
+	}
+	partition := ctx.Partition
+	if partition == "" {
+		partition = "aws"
+	}
+	return arn.ARN{
+		Partition: partition,
+		Service:   "kms",
+		Region:    ctx.Region,
+		AccountID: ctx.Account,
+		Resource:  resource,
+	}.String()
+}
+
+// Field is one labeled component of an exploded ARN
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Explode splits an ARN into its partition, service, region, account, and
+// resource. Empty components (e.g. the region of an S3 bucket) are shown as "-".
+func Explode(id string) ([]Field, bool) {
+	parsed, err := arn.Parse(strings.TrimSpace(id))
+	if err != nil {
+		return nil, false
+	}
+	dash := func(value string) string {
+		if value == "" {
+			return "-"
+		}
+		return value
+	}
+	return []Field{
+		{Name: "partition", Value: parsed.Partition},
+		{Name: "service", Value: parsed.Service},
+		{Name: "region", Value: dash(parsed.Region)},
+		{Name: "account", Value: dash(parsed.AccountID)},
+		{Name: "resource", Value: parsed.Resource},
+	}, true
+}
@@ -0,0 +1,84 @@
+// internal/arns/arns_test.go
+package arns
+
+import (
+	"reflect"
+	"testing"
+)
+
+const (
+	keyID  = "1234abcd-12ab-34cd-56ef-1234567890ab"
+	keyARN = "arn:aws:kms:us-east-1:123456789012:key/" + keyID
+)
+
+func TestNormalizeKeyForms(t *testing.T) {
+	ctx := Context{Region: "us-east-1", Account: "123456789012"}
+	for _, id := range []string{
+		keyID,
+		"1234ABCD-12AB-34CD-56EF-1234567890AB",
+		"key/" + keyID,
+		keyARN,
+		"  " + keyARN + "\n",
+	} {
+		if got := Normalize(id, ctx); got != keyARN {
+			t.Errorf("Normalize(%q) = %q, want %q", id, got, keyARN)
+		}
+	}
+}
+
+func TestNormalizeAliasAndMultiRegionKey(t *testing.T) {
+	ctx := Context{Partition: "aws-us-gov", Region: "us-gov-west-1", Account: "123456789012"}
+	tests := map[string]string{
+		"alias/payments":                         "arn:aws-us-gov:kms:us-gov-west-1:123456789012:alias/payments",
+		"mrk-1234abcd12ab34cd56ef1234567890ab":   "arn:aws-us-gov:kms:us-gov-west-1:123456789012:key/mrk-1234abcd12ab34cd56ef1234567890ab",
+		"arn:aws:s3:::my-bucket":                 "arn:aws:s3:::my-bucket",
+		"my-bucket":                              "my-bucket", // not a KMS identifier
+		"arn:aws:kms:us-east-1:123456789012:bad": "arn:aws:kms:us-east-1:123456789012:bad",
+	}
+	for id, want := range tests {
+		if got := Normalize(id, ctx); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
+
+func TestNormalizeWithoutContext(t *testing.T) {
+	// Without a region and account, the short forms still agree with each other
+	for _, id := range []string{keyID, "key/" + keyID} {
+		if got := Normalize(id, Context{}); got != "key/"+keyID {
+			t.Errorf("Normalize(%q) = %q, want key/%s", id, got, keyID)
+		}
+	}
+	if got := Normalize("", Context{}); got != "" {
+		t.Errorf("Normalize(\"\") = %q", got)
+	}
+}
+
+func TestExplode(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want []Field
+	}{
+		{keyARN, []Field{
+			{"partition", "aws"}, {"service", "kms"}, {"region", "us-east-1"},
+			{"account", "123456789012"}, {"resource", "key/" + keyID},
+		}},
+		// S3 bucket ARNs have no region or account
+		{"arn:aws:s3:::my-bucket/logs/2024", []Field{
+			{"partition", "aws"}, {"service", "s3"}, {"region", "-"},
+			{"account", "-"}, {"resource", "my-bucket/logs/2024"},
+		}},
+	}
+	for _, tc := range tests {
+		got, ok := Explode(tc.arn)
+		if !ok || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Explode(%s) = %v, %v; want %v", tc.arn, got, ok, tc.want)
+		}
+	}
+
+	for _, id := range []string{keyID, "alias/payments", "arn:aws:kms"} {
+		if fields, ok := Explode(id); ok {
+			t.Errorf("Explode(%s) = %v, want it rejected as not an ARN", id, fields)
+		}
+	}
+}
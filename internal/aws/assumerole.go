@@ -0,0 +1,50 @@
+// internal/aws/assumerole.go
+package aws
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// assumeRole is the role NewAWSClient switches to after loading the profile;
+// an empty ARN keeps the profile's own credentials
+var assumeRole struct {
+	ARN        string
+	ExternalID string
+}
+
+// SetAssumeRole makes every client created afterwards assume roleARN with the
+// profile's credentials. externalID is optional and needs a role to go with it.
+func SetAssumeRole(roleARN, externalID string) error {
+	if roleARN == "" {
+		if externalID != "" {
+			return fmt.Errorf("--external-id requires --assume-role-arn")
+		}
+		assumeRole.ARN, assumeRole.ExternalID = "", ""
+		return nil
+	}
+	if !strings.HasPrefix(roleARN, "arn:") || !strings.Contains(roleARN, ":role/") {
+		return fmt.Errorf("invalid --assume-role-arn %q: expected arn:aws:iam::<account>:role/<name>", roleARN)
+	}
+	assumeRole.ARN, assumeRole.ExternalID = roleARN, externalID
+	return nil
+}
+
+// withAssumedRole returns a copy of cfg whose credentials come from assuming
+// the configured role with cfg's credentials
+func withAssumedRole(cfg sdkaws.Config) sdkaws.Config {
+	provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), assumeRole.ARN, func(o *stscreds.AssumeRoleOptions) {
+		o.RoleSessionName = fmt.Sprintf("cloudtrail-logs-%d", time.Now().Unix())
+		if assumeRole.ExternalID != "" {
+			o.ExternalID = sdkaws.String(assumeRole.ExternalID)
+		}
+	})
+	assumed := cfg.Copy()
+	assumed.Credentials = sdkaws.NewCredentialsCache(provider)
+	return assumed
+}
@@ -3,86 +3,177 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/dhairya13703/cloudtrail-logs/internal/retry"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
 )
 
+// DefaultRegion is used when neither --region nor the profile specifies one
+const DefaultRegion = "us-east-1"
+
 type AWSClient struct {
 	CloudTrail *cloudtrail.Client
 	Region     string
 	Profile    string
+	Account    string
+
+	// Regions lists every region to scan, starting with Region
+	Regions []string
+
+	config sdkaws.Config
 }
 
-func NewAWSClient(ctx context.Context, profile, region string) (*AWSClient, error) {
-	// First validate if the profile exists
-	if err := ValidateProfile(profile); err != nil {
-		fmt.Printf("\nError: %v\n", err)
-		PrintAWSProfiles()
-		return nil, fmt.Errorf("invalid AWS profile: %s", profile)
+// NewAWSClient loads the profile and verifies its credentials. An empty region
+// falls back to the region configured for the profile, then to DefaultRegion.
+// region may be a comma-separated list; the first region is the client's own.
+// Progress and the identity banner are written to out (stdout when nil).
+// When a role was set with SetAssumeRole, the client uses the role's
+// credentials and Account is the role's account.
+func NewAWSClient(ctx context.Context, profile, region string, out io.Writer) (*AWSClient, error) {
+	if out == nil {
+		out = os.Stdout
 	}
 
-	fmt.Printf("Attempting to load AWS profile: %s\n", profile)
+	fmt.Fprintf(out, "Attempting to load AWS profile: %s\n", profile)
 
-	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithSharedConfigProfile(profile),
-		config.WithRegion(region),
-	)
+	regions := SplitRegions(region)
+	cfg, err := loadConfig(ctx, profile, regions)
+	var missing config.SharedConfigProfileNotExistError
+	if errors.As(err, &missing) {
+		fmt.Printf("\nError: profile '%s' not found in AWS credentials or config files\n", profile)
+		PrintAWSProfiles()
+		return nil, fmt.Errorf("invalid AWS profile: %s", profile)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to load SDK config: %v\nPlease check your AWS credentials and profile configuration", err)
 	}
+	region = cfg.Region
+	if len(regions) == 0 {
+		regions = []string{region}
+	}
 
 	// Verify credentials by making a test call to STS
 	stsClient := sts.NewFromConfig(cfg)
-	identity, err := stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	var identity *sts.GetCallerIdentityOutput
+	err = retry.Do(ctx, retry.Default, func(ctx context.Context) error {
+		identity, err = stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+		return err
+	})
 	if err != nil {
 		fmt.Printf("\nFailed to authenticate with profile '%s'\n", profile)
-		PrintAWSProfiles()
-		return nil, fmt.Errorf("failed to verify AWS credentials: %v\n\nPossible solutions:\n"+
-			"1. Run 'aws configure' to set up your credentials\n"+
-			"2. Check if the profile '%s' exists in ~/.aws/credentials\n"+
-			"3. Ensure your credentials are not expired\n", 
-			err, profile)
+		if !ssoLoginRequired(err, IsSSOProfile(profile)) && !credentialsExpired(err) {
+			PrintAWSProfiles()
+		}
+		return nil, describeAuthError(err, profile)
+	}
+
+	if skew, ok := clockSkew(identity.ResultMetadata, time.Now()); ok {
+		if warning := describeSkew(skew); warning != "" {
+			fmt.Fprintln(out, theme.Warning(warning))
+		}
+	}
+
+	// With --assume-role-arn the profile's identity is only the caller;
+	// CloudTrail is queried in the account the role belongs to
+	account := *identity.Account
+	var assumedARN string
+	if assumeRole.ARN != "" {
+		cfg = withAssumedRole(cfg)
+		var assumed *sts.GetCallerIdentityOutput
+		err = retry.Do(ctx, retry.Default, func(ctx context.Context) error {
+			assumed, err = sts.NewFromConfig(cfg).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to assume role '%s' with profile '%s': %v\n\n"+
+				"Check that the role's trust policy allows %s and that --external-id matches, if the role requires one",
+				assumeRole.ARN, profile, err, *identity.Arn)
+		}
+		account, assumedARN = *assumed.Account, *assumed.Arn
 	}
 
 	// Print identity information
-	fmt.Printf("\nAWS Authentication Successful:\n")
-	fmt.Printf("Account: %s\n", *identity.Account)
-	fmt.Printf("User ID: %s\n", *identity.UserId)
-	fmt.Printf("ARN: %s\n", *identity.Arn)
-	fmt.Printf("Using Profile: %s\n", profile)
-	fmt.Printf("Region: %s\n", region)
-	fmt.Println(strings.Repeat("-", 80))
+	fmt.Fprintf(out, "\nAWS Authentication Successful:\n")
+	fmt.Fprintf(out, "Account: %s\n", account)
+	fmt.Fprintf(out, "User ID: %s\n", *identity.UserId)
+	fmt.Fprintf(out, "ARN: %s\n", *identity.Arn)
+	if assumedARN != "" {
+		fmt.Fprintf(out, "Assumed Role: %s\n", assumedARN)
+	}
+	fmt.Fprintf(out, "Using Profile: %s\n", profile)
+	fmt.Fprintf(out, "Region: %s\n", strings.Join(regions, ", "))
+	fmt.Fprintln(out, strings.Repeat("-", 80))
 
 	return &AWSClient{
 		CloudTrail: cloudtrail.NewFromConfig(cfg),
 		Region:     region,
 		Profile:    profile,
+		Account:    account,
+		Regions:    regions,
+		config:     cfg,
 	}, nil
 }
 
+// loadConfig loads the profile's configuration. The first of regions wins;
+// without one the profile's configured region is used, then DefaultRegion.
+func loadConfig(ctx context.Context, profile string, regions []string) (sdkaws.Config, error) {
+	// Credential resolution (static keys, SSO, credential_process,
+	// source_profile) is left to the SDK; the GetCallerIdentity call in
+	// NewAWSClient is what proves the profile is usable. The SDK's own
+	// retries are disabled so every call follows the single retry.Default
+	// policy instead of stacking two.
+	loadOptions := []func(*config.LoadOptions) error{
+		config.WithSharedConfigProfile(profile),
+		config.WithRetryer(func() sdkaws.Retryer { return sdkaws.NopRetryer{} }),
+	}
+	if len(regions) > 0 {
+		loadOptions = append(loadOptions, config.WithRegion(regions[0]))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return cfg, err
+	}
+	if cfg.Region == "" {
+		cfg.Region = DefaultRegion
+	}
+	return cfg, nil
+}
+
+// CloudTrailIn returns a CloudTrail client for region that shares this
+// client's credentials
+func (c *AWSClient) CloudTrailIn(region string) *cloudtrail.Client {
+	if region == c.Region {
+		return c.CloudTrail
+	}
+	return cloudtrail.NewFromConfig(c.config, func(o *cloudtrail.Options) {
+		o.Region = region
+	})
+}
+
 // PrintAWSProfiles prints all available AWS profiles from the credentials file
 func PrintAWSProfiles() {
-	homeDir, err := os.UserHomeDir()
+	credentialsPath, configPath, err := sharedFilePaths()
 	if err != nil {
 		fmt.Println("Error getting home directory")
 		return
 	}
 
-	credentialsPath := filepath.Join(homeDir, ".aws", "credentials")
-	configPath := filepath.Join(homeDir, ".aws", "config")
-
 	fmt.Println("\nAvailable AWS Profiles:")
 
 	// Check credentials file
 	if _, err := os.Stat(credentialsPath); err == nil {
-		fmt.Println("\nFrom ~/.aws/credentials:")
+		fmt.Printf("\nFrom %s:\n", credentialsPath)
 		if content, err := os.ReadFile(credentialsPath); err == nil {
 			profiles := extractProfiles(string(content), false)
 			for _, p := range profiles {
@@ -93,7 +184,7 @@ func PrintAWSProfiles() {
 
 	// Check config file
 	if _, err := os.Stat(configPath); err == nil {
-		fmt.Println("\nFrom ~/.aws/config:")
+		fmt.Printf("\nFrom %s:\n", configPath)
 		if content, err := os.ReadFile(configPath); err == nil {
 			profiles := extractProfiles(string(content), true)
 			for _, p := range profiles {
@@ -107,6 +198,28 @@ func PrintAWSProfiles() {
 	fmt.Println()
 }
 
+// sharedFilePaths locates the shared credentials and config files, honoring
+// AWS_SHARED_CREDENTIALS_FILE and AWS_CONFIG_FILE like the SDK does
+func sharedFilePaths() (credentialsPath, configPath string, err error) {
+	credentialsPath = os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	configPath = os.Getenv("AWS_CONFIG_FILE")
+	if credentialsPath != "" && configPath != "" {
+		return credentialsPath, configPath, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", "", err
+	}
+	if credentialsPath == "" {
+		credentialsPath = filepath.Join(homeDir, ".aws", "credentials")
+	}
+	if configPath == "" {
+		configPath = filepath.Join(homeDir, ".aws", "config")
+	}
+	return credentialsPath, configPath, nil
+}
+
 // extractProfiles extracts profile names from AWS credential/config files
 func extractProfiles(content string, isConfig bool) []string {
 	var profiles []string
@@ -114,27 +227,23 @@ func extractProfiles(content string, isConfig bool) []string {
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
-			profile := line[1 : len(line)-1]
-			if isConfig {
-				// Remove "profile " prefix if present
-				profile = strings.TrimPrefix(profile, "profile ")
+			// Skip sections that aren't profiles, such as [sso-session name]
+			if profile, ok := sectionProfile(line[1:len(line)-1], isConfig); ok {
+				profiles = append(profiles, profile)
 			}
-			profiles = append(profiles, profile)
 		}
 	}
 	return profiles
 }
 
-// ValidateProfile checks if an AWS profile exists
+// ValidateProfile checks if an AWS profile has a section in the shared
+// credentials or config file. It doesn't check that the profile is usable.
 func ValidateProfile(profile string) error {
-	homeDir, err := os.UserHomeDir()
+	credentialsPath, configPath, err := sharedFilePaths()
 	if err != nil {
 		return fmt.Errorf("error getting home directory: %v", err)
 	}
 
-	credentialsPath := filepath.Join(homeDir, ".aws", "credentials")
-	configPath := filepath.Join(homeDir, ".aws", "config")
-
 	// Check if either file exists
 	credentialsExists := false
 	configExists := false
@@ -153,7 +262,7 @@ func ValidateProfile(profile string) error {
 	// Read credentials file
 	if credentialsExists {
 		if content, err := os.ReadFile(credentialsPath); err == nil {
-			if strings.Contains(string(content), fmt.Sprintf("[%s]", profile)) {
+			if profileSettings(string(content), profile, false) != nil {
 				return nil
 			}
 		}
@@ -162,11 +271,11 @@ func ValidateProfile(profile string) error {
 	// Read config file
 	if configExists {
 		if content, err := os.ReadFile(configPath); err == nil {
-			if strings.Contains(string(content), fmt.Sprintf("[profile %s]", profile)) {
+			if profileSettings(string(content), profile, true) != nil {
 				return nil
 			}
 		}
 	}
 
 	return fmt.Errorf("profile '%s' not found in AWS credentials or config files", profile)
-}
\ No newline at end of file
+}
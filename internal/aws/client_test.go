@@ -0,0 +1,153 @@
+// internal/aws/client_test.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// useSharedFiles points the SDK at a temporary config file holding content,
+// isolated from the environment's own profiles and region
+func useSharedFiles(t *testing.T, content string) {
+	t.Helper()
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config")
+	if err := os.WriteFile(configFile, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+	t.Setenv("AWS_PROFILE", "")
+}
+
+const testConfig = `[profile with-region]
+region = eu-west-2
+
+[profile without-region]
+output = json
+`
+
+func TestLoadConfigRegion(t *testing.T) {
+	useSharedFiles(t, testConfig)
+
+	tests := []struct {
+		profile string
+		regions []string
+		want    string
+	}{
+		{"with-region", nil, "eu-west-2"},                                // the profile's region
+		{"with-region", []string{"ap-south-1"}, "ap-south-1"},            // --region wins
+		{"with-region", []string{"us-west-2", "eu-west-1"}, "us-west-2"}, // the first listed
+		{"without-region", nil, DefaultRegion},
+	}
+	for _, tc := range tests {
+		cfg, err := loadConfig(context.Background(), tc.profile, tc.regions)
+		if err != nil {
+			t.Fatalf("loadConfig(%s, %v): %v", tc.profile, tc.regions, err)
+		}
+		if cfg.Region != tc.want {
+			t.Errorf("loadConfig(%s, %v) region = %s, want %s", tc.profile, tc.regions, cfg.Region, tc.want)
+		}
+	}
+}
+
+func TestLoadConfigMissingProfile(t *testing.T) {
+	useSharedFiles(t, testConfig)
+
+	_, err := loadConfig(context.Background(), "nope", nil)
+	var missing config.SharedConfigProfileNotExistError
+	if !errors.As(err, &missing) {
+		t.Errorf("loadConfig(nope) = %v, want a missing profile error", err)
+	}
+}
+
+func TestExtractProfiles(t *testing.T) {
+	credentials := "[default]\naws_access_key_id = AKIA\n\n  [ci]  \naws_access_key_id = AKIB\n"
+	if got := extractProfiles(credentials, false); !reflect.DeepEqual(got, []string{"default", "ci"}) {
+		t.Errorf("credentials profiles = %v", got)
+	}
+
+	// Config sections carry a "profile " prefix, except default, and other
+	// sections aren't profiles
+	config := "[default]\nregion = us-east-1\n[profile dev]\n[sso-session corp]\n[profile prod]\n"
+	if got := extractProfiles(config, true); !reflect.DeepEqual(got, []string{"default", "dev", "prod"}) {
+		t.Errorf("config profiles = %v", got)
+	}
+}
+
+func TestPrintAWSProfiles(t *testing.T) {
+	useSharedFiles(t, "[profile dev]\n[sso-session corp]\n")
+	if err := os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), []byte("[ci]\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	PrintAWSProfiles()
+	os.Stdout = stdout
+	w.Close()
+	printed, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := string(printed)
+	for _, want := range []string{"  - ci\n", "  - dev\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("profile %q not listed:\n%s", strings.TrimSpace(want), out)
+		}
+	}
+	if strings.Contains(out, "corp") {
+		t.Errorf("listed an sso-session as a profile:\n%s", out)
+	}
+}
+
+func TestSharedFilePathsFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "ci-credentials"))
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "ci-config"))
+	credentialsPath, configPath, err := sharedFilePaths()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credentialsPath != filepath.Join(dir, "ci-credentials") || configPath != filepath.Join(dir, "ci-config") {
+		t.Errorf("sharedFilePaths = %s, %s; want the env paths", credentialsPath, configPath)
+	}
+
+	// Either one falls back to ~/.aws on its own
+	t.Setenv("HOME", dir)
+	t.Setenv("AWS_CONFIG_FILE", "")
+	if _, configPath, _ := sharedFilePaths(); configPath != filepath.Join(dir, ".aws", "config") {
+		t.Errorf("config path = %s, want the default", configPath)
+	}
+}
+
+func TestValidateProfileFromEnvPaths(t *testing.T) {
+	useSharedFiles(t, "[profile from-config]\nregion = eu-west-1\n")
+	if err := os.WriteFile(os.Getenv("AWS_SHARED_CREDENTIALS_FILE"), []byte("[from-credentials]\naws_access_key_id = AKIA\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range []string{"from-config", "from-credentials"} {
+		if err := ValidateProfile(profile); err != nil {
+			t.Errorf("ValidateProfile(%s) = %v", profile, err)
+		}
+	}
+	if err := ValidateProfile("elsewhere"); err == nil {
+		t.Error("ValidateProfile accepted a profile in neither file")
+	}
+}
@@ -0,0 +1,133 @@
+// internal/aws/credentials.go
+package aws
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+)
+
+// expiredTokenCodes are the API error codes AWS returns for credentials that
+// were valid once but have since expired
+var expiredTokenCodes = map[string]bool{
+	"ExpiredToken":          true,
+	"ExpiredTokenException": true,
+	"RequestExpired":        true,
+}
+
+// ssoLoginCodes are the SSO and SSO OIDC error codes that mean the cached
+// access token can no longer be used and the user has to sign in again
+var ssoLoginCodes = map[string]bool{
+	"UnauthorizedException":       true,
+	"InvalidGrantException":       true,
+	"UnauthorizedClientException": true,
+	"ExpiredTokenException":       true,
+}
+
+// profileSettings returns the key/value pairs of the named profile's section,
+// or nil when the section isn't present. Config files name profiles
+// "[profile name]" (except default); credentials files use "[name]".
+func profileSettings(content, profile string, isConfig bool) map[string]string {
+	var settings map[string]string
+	inSection := false
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name, ok := sectionProfile(line[1:len(line)-1], isConfig)
+			inSection = ok && name == profile
+			if inSection && settings == nil {
+				settings = map[string]string{}
+			}
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			settings[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return settings
+}
+
+// sectionProfile returns the profile a section header declares. Config file
+// sections that aren't profiles, such as "[sso-session name]", report false.
+func sectionProfile(header string, isConfig bool) (string, bool) {
+	header = strings.TrimSpace(header)
+	if !isConfig {
+		return header, header != ""
+	}
+	if name, ok := strings.CutPrefix(header, "profile "); ok {
+		name = strings.TrimSpace(name)
+		return name, name != ""
+	}
+	if header == "default" {
+		return header, true
+	}
+	return "", false
+}
+
+// IsSSOProfile reports whether the profile signs in through IAM Identity
+// Center, either directly (sso_start_url) or via an sso-session section
+func IsSSOProfile(profile string) bool {
+	_, configPath, err := sharedFilePaths()
+	if err != nil {
+		return false
+	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		return false
+	}
+	settings := profileSettings(string(content), profile, true)
+	return settings["sso_start_url"] != "" || settings["sso_session"] != ""
+}
+
+// ssoLoginRequired reports whether err means the cached SSO token is missing,
+// expired, or was rejected
+func ssoLoginRequired(err error, ssoProfile bool) bool {
+	var tokenErr *ssocreds.InvalidTokenError
+	if errors.As(err, &tokenErr) {
+		return true
+	}
+	if !ssoProfile {
+		return false
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && ssoLoginCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	return strings.Contains(err.Error(), "SSO token")
+}
+
+// credentialsExpired reports whether err is AWS rejecting expired credentials
+func credentialsExpired(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && expiredTokenCodes[apiErr.ErrorCode()]
+}
+
+// describeAuthError turns a failed credential check into an error that tells
+// the user what to do next: sign in to SSO again, refresh expired
+// credentials, or fix the profile configuration
+func describeAuthError(err error, profile string) error {
+	ssoProfile := IsSSOProfile(profile)
+	switch {
+	case ssoLoginRequired(err, ssoProfile):
+		return fmt.Errorf("the SSO session for profile '%s' has expired or was never started: %v\n\n"+
+			"Run 'aws sso login --profile %s' and try again", profile, err, profile)
+	case credentialsExpired(err):
+		return fmt.Errorf("the credentials for profile '%s' have expired: %v\n\n"+
+			"Refresh the session token for the profile and try again", profile, err)
+	}
+	return fmt.Errorf("failed to verify AWS credentials: %v\n\nPossible solutions:\n"+
+		"1. Run 'aws configure' to set up your credentials\n"+
+		"2. Check if the profile '%s' exists in ~/.aws/credentials (or $AWS_SHARED_CREDENTIALS_FILE)\n"+
+		"3. Ensure your credentials are not expired\n",
+		err, profile)
+}
@@ -0,0 +1,119 @@
+// internal/aws/credentials_test.go
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/smithy-go"
+)
+
+const ssoConfig = `[default]
+region = us-east-1
+
+[profile start-url]
+sso_start_url = https://example.awsapps.com/start
+sso_account_id = 123456789012
+
+[profile session]
+sso_session = corp
+
+[sso-session corp]
+sso_start_url = https://example.awsapps.com/start
+
+[profile static]
+region = eu-west-1
+
+# [profile commented]
+# sso_start_url = https://example.awsapps.com/start
+`
+
+func TestIsSSOProfile(t *testing.T) {
+	useSharedFiles(t, ssoConfig)
+
+	tests := []struct {
+		profile string
+		want    bool
+	}{
+		{"start-url", true},
+		{"session", true},
+		{"static", false},
+		{"default", false},
+		{"commented", false},
+		// The sso-session section isn't a profile
+		{"corp", false},
+		{"missing", false},
+	}
+	for _, tc := range tests {
+		if got := IsSSOProfile(tc.profile); got != tc.want {
+			t.Errorf("IsSSOProfile(%s) = %v, want %v", tc.profile, got, tc.want)
+		}
+	}
+}
+
+func TestSSOLoginRequired(t *testing.T) {
+	unauthorized := &smithy.GenericAPIError{Code: "UnauthorizedException", Message: "Session token not found or invalid"}
+
+	tests := []struct {
+		name       string
+		err        error
+		ssoProfile bool
+		want       bool
+	}{
+		{"invalid token", fmt.Errorf("get identity: %w", &ssocreds.InvalidTokenError{}), false, true},
+		{"unauthorized on an SSO profile", unauthorized, true, true},
+		// Without SSO the same code means something else
+		{"unauthorized elsewhere", unauthorized, false, false},
+		{"SSO token message", errors.New("refresh cached SSO token failed"), true, true},
+		{"expired keys", &smithy.GenericAPIError{Code: "ExpiredToken"}, true, false},
+	}
+	for _, tc := range tests {
+		if got := ssoLoginRequired(tc.err, tc.ssoProfile); got != tc.want {
+			t.Errorf("%s: ssoLoginRequired = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDescribeAuthError(t *testing.T) {
+	useSharedFiles(t, ssoConfig)
+
+	tests := []struct {
+		name    string
+		err     error
+		profile string
+		want    string
+	}{
+		{"invalid token", &ssocreds.InvalidTokenError{}, "static", "aws sso login --profile static"},
+		{"unauthorized", &smithy.GenericAPIError{Code: "UnauthorizedException"}, "session", "aws sso login --profile session"},
+		{"expired token", &smithy.GenericAPIError{Code: "ExpiredToken"}, "static", "credentials for profile 'static' have expired"},
+		{"other", errors.New("no EC2 IMDS role found"), "missing", "Check if the profile 'missing' exists"},
+	}
+	messages := map[string]string{}
+	for _, tc := range tests {
+		got := describeAuthError(tc.err, tc.profile).Error()
+		if !strings.Contains(got, tc.want) {
+			t.Errorf("%s: describeAuthError = %q, want it to mention %q", tc.name, got, tc.want)
+		}
+		if !strings.Contains(got, tc.err.Error()) {
+			t.Errorf("%s: describeAuthError dropped the cause: %q", tc.name, got)
+		}
+		messages[tc.name] = got
+	}
+	if strings.Contains(messages["expired token"], "sso login") || strings.Contains(messages["other"], "sso login") {
+		t.Errorf("non-SSO failures suggest an SSO login:\n%s\n%s", messages["expired token"], messages["other"])
+	}
+}
+
+func TestNewAWSClientMissingProfile(t *testing.T) {
+	useSharedFiles(t, ssoConfig)
+
+	_, err := NewAWSClient(context.Background(), "missing", "", io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "invalid AWS profile: missing") {
+		t.Errorf("NewAWSClient(missing) = %v, want an invalid profile error", err)
+	}
+}
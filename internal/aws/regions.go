@@ -0,0 +1,30 @@
+// internal/aws/regions.go
+package aws
+
+import "strings"
+
+// DefaultRegions are the commercial regions enabled in every account, which
+// --all-regions scans. Opt-in regions have to be listed with --region.
+var DefaultRegions = []string{
+	"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	"ca-central-1", "sa-east-1",
+	"eu-central-1", "eu-west-1", "eu-west-2", "eu-west-3", "eu-north-1",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-south-1", "ap-southeast-1", "ap-southeast-2",
+}
+
+// SplitRegions parses a comma-separated region list, dropping blanks and
+// repeats while keeping the order given
+func SplitRegions(list string) []string {
+	var regions []string
+	seen := make(map[string]bool)
+	for _, region := range strings.Split(list, ",") {
+		region = strings.TrimSpace(region)
+		if region == "" || seen[region] {
+			continue
+		}
+		seen[region] = true
+		regions = append(regions, region)
+	}
+	return regions
+}
@@ -0,0 +1,45 @@
+// internal/aws/skew.go
+package aws
+
+import (
+	"net/http"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// MaxClockSkew is how far the host clock may drift from AWS before warning
+const MaxClockSkew = time.Minute
+
+// clockSkew estimates how far the host clock is ahead of AWS (negative when
+// behind) from the Date header of a response received at receivedAt. The
+// header has one-second resolution.
+func clockSkew(metadata middleware.Metadata, receivedAt time.Time) (time.Duration, bool) {
+	response, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response)
+	if !ok || response == nil {
+		return 0, false
+	}
+	serverTime, err := http.ParseTime(response.Header.Get("Date"))
+	if err != nil {
+		return 0, false
+	}
+	return receivedAt.Sub(serverTime).Truncate(time.Second), true
+}
+
+// describeSkew explains a skew beyond MaxClockSkew, or returns "" when the
+// clock is close enough
+func describeSkew(skew time.Duration) string {
+	direction := "ahead of"
+	magnitude := skew
+	if skew < 0 {
+		direction, magnitude = "behind", -skew
+	}
+	if magnitude <= MaxClockSkew {
+		return ""
+	}
+	return "Warning: the host clock is " + magnitude.String() + " " + direction + " AWS. " +
+		"Relative time ranges (--last-n) are computed from the host clock and may miss events; " +
+		"sync the clock with NTP."
+}
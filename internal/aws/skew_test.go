@@ -0,0 +1,77 @@
+// internal/aws/skew_test.go
+package aws
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// responseMetadata is the metadata of a call answered with the given Date
+// header, recorded the way the SDK's raw response middleware does
+func responseMetadata(t *testing.T, date string) middleware.Metadata {
+	t.Helper()
+	response := &smithyhttp.Response{Response: &http.Response{Header: http.Header{}}}
+	if date != "" {
+		response.Header.Set("Date", date)
+	}
+	next := middleware.DeserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (middleware.DeserializeOutput, middleware.Metadata, error) {
+		return middleware.DeserializeOutput{RawResponse: response}, middleware.Metadata{}, nil
+	})
+	_, metadata, err := awsmiddleware.AddRawResponse{}.HandleDeserialize(context.Background(), middleware.DeserializeInput{}, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return metadata
+}
+
+func TestClockSkew(t *testing.T) {
+	server := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	metadata := responseMetadata(t, server.Format(http.TimeFormat))
+
+	tests := []struct {
+		receivedAt time.Time
+		want       time.Duration
+	}{
+		{server.Add(500 * time.Millisecond), 0},
+		{server.Add(5 * time.Minute), 5 * time.Minute},
+		{server.Add(-90 * time.Second), -90 * time.Second},
+	}
+	for _, tc := range tests {
+		skew, ok := clockSkew(metadata, tc.receivedAt)
+		if !ok || skew != tc.want {
+			t.Errorf("clockSkew at %s = %s, %v; want %s", tc.receivedAt.Format(time.TimeOnly), skew, ok, tc.want)
+		}
+	}
+
+	for name, metadata := range map[string]middleware.Metadata{
+		"no response": {},
+		"no Date":     responseMetadata(t, ""),
+		"bad Date":    responseMetadata(t, "yesterday"),
+	} {
+		if skew, ok := clockSkew(metadata, server); ok {
+			t.Errorf("%s: clockSkew = %s, want no measurement", name, skew)
+		}
+	}
+}
+
+func TestDescribeSkew(t *testing.T) {
+	for _, skew := range []time.Duration{0, MaxClockSkew, -MaxClockSkew} {
+		if warning := describeSkew(skew); warning != "" {
+			t.Errorf("describeSkew(%s) = %q, want no warning", skew, warning)
+		}
+	}
+
+	if warning := describeSkew(10 * time.Minute); !strings.Contains(warning, "10m0s ahead of AWS") || !strings.Contains(warning, "NTP") {
+		t.Errorf("describeSkew(10m) = %q", warning)
+	}
+	if warning := describeSkew(-2 * time.Minute); !strings.Contains(warning, "2m0s behind AWS") {
+		t.Errorf("describeSkew(-2m) = %q", warning)
+	}
+}
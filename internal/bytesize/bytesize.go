@@ -0,0 +1,50 @@
+// internal/bytesize/bytesize.go
+package bytesize
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matches a size such as "512MB", "1.5GB", or "4096"
+var sizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([KMGT]?I?B?)$`)
+
+var multipliers = map[string]int64{
+	"":  1,
+	"B": 1,
+	"K": 1 << 10,
+	"M": 1 << 20,
+	"G": 1 << 30,
+	"T": 1 << 40,
+}
+
+// Parse reads a size in bytes. Units are binary, so KB and KiB both mean 1024.
+func Parse(size string) (int64, error) {
+	matches := sizePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(size)))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid size %q: use a number with an optional unit, e.g. 512MB or 2GB", size)
+	}
+	value, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %v", size, err)
+	}
+	unit := strings.TrimSuffix(strings.TrimSuffix(matches[2], "B"), "I")
+	return int64(value * float64(multipliers[unit])), nil
+}
+
+// Format renders a byte count with the largest unit that keeps it above 1
+func Format(size int64) string {
+	units := []string{"B", "KB", "MB", "GB", "TB"}
+	value := float64(size)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f %s", value, units[unit])
+}
@@ -0,0 +1,118 @@
+// internal/classify/classify.go
+package classify
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityInfo
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = []string{"none", "info", "low", "medium", "high", "critical"}
+
+func (s Severity) String() string {
+	if s < SeverityNone || int(s) >= len(severityNames) {
+		return "unknown"
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity converts a severity name (case-insensitive) to a Severity
+func ParseSeverity(name string) (Severity, error) {
+	for i, candidate := range severityNames {
+		if strings.EqualFold(name, candidate) && i > 0 {
+			return Severity(i), nil
+		}
+	}
+	return SeverityNone, fmt.Errorf("invalid severity %q: use info, low, medium, high, or critical", name)
+}
+
+// Rule maps an exact event name or a regular expression to a severity and tags
+type Rule struct {
+	Match    string   `yaml:"match"`
+	Pattern  string   `yaml:"pattern"`
+	Severity string   `yaml:"severity"`
+	Tags     []string `yaml:"tags"`
+
+	severity Severity
+	re       *regexp.Regexp
+}
+
+// Classification is the result of applying the rules to an event
+type Classification struct {
+	Severity Severity
+	Tags     []string
+}
+
+// Classifier applies rules in file order; the first matching rule wins
+type Classifier struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load reads a YAML or JSON classification file:
+//
+//	rules:
+//	  - match: ScheduleKeyDeletion
+//	    severity: critical
+//	    tags: [destructive]
+//	  - pattern: "^(Disable|Delete)"
+//	    severity: high
+func Load(path string) (*Classifier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read classification file: %v", err)
+	}
+
+	// JSON is a subset of YAML, so one decoder handles both
+	var classifier Classifier
+	if err := yaml.Unmarshal(data, &classifier); err != nil {
+		return nil, fmt.Errorf("invalid classification file %s: %v", path, err)
+	}
+
+	for i := range classifier.Rules {
+		rule := &classifier.Rules[i]
+		if (rule.Match == "") == (rule.Pattern == "") {
+			return nil, fmt.Errorf("classification rule %d: exactly one of match or pattern is required", i+1)
+		}
+		if rule.Pattern != "" {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("classification rule %d: invalid pattern: %v", i+1, err)
+			}
+			rule.re = re
+		}
+		severity, err := ParseSeverity(rule.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("classification rule %d: %v", i+1, err)
+		}
+		rule.severity = severity
+	}
+
+	return &classifier, nil
+}
+
+// Classify returns the classification of the first rule matching eventName
+func (c *Classifier) Classify(eventName string) (Classification, bool) {
+	if c == nil {
+		return Classification{}, false
+	}
+	for _, rule := range c.Rules {
+		if (rule.re != nil && rule.re.MatchString(eventName)) || (rule.re == nil && strings.EqualFold(rule.Match, eventName)) {
+			return Classification{Severity: rule.severity, Tags: rule.Tags}, true
+		}
+	}
+	return Classification{}, false
+}
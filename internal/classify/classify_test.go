@@ -0,0 +1,118 @@
+// internal/classify/classify_test.go
+package classify
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sampleRules = `rules:
+  - match: ScheduleKeyDeletion
+    severity: critical
+    tags: [destructive, kms]
+  - pattern: "^(Disable|Delete)"
+    severity: high
+  - match: decrypt
+    severity: Info
+`
+
+func writeRules(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAndClassify(t *testing.T) {
+	classifier, err := Load(writeRules(t, "rules.yaml", sampleRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		eventName string
+		want      Classification
+		ok        bool
+	}{
+		{"ScheduleKeyDeletion", Classification{SeverityCritical, []string{"destructive", "kms"}}, true},
+		{"DisableKey", Classification{Severity: SeverityHigh}, true},
+		{"DeleteAlias", Classification{Severity: SeverityHigh}, true},
+		{"Decrypt", Classification{Severity: SeverityInfo}, true}, // match is case-insensitive
+		{"ReDisableKey", Classification{}, false},                 // the pattern is anchored
+		{"Encrypt", Classification{}, false},
+	}
+	for _, tc := range tests {
+		got, ok := classifier.Classify(tc.eventName)
+		if ok != tc.ok || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Classify(%s) = %+v, %v; want %+v, %v", tc.eventName, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestFirstRuleWins(t *testing.T) {
+	classifier, err := Load(writeRules(t, "rules.yaml", `rules:
+  - pattern: Key
+    severity: low
+  - match: DisableKey
+    severity: critical
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := classifier.Classify("DisableKey"); got.Severity != SeverityLow {
+		t.Errorf("DisableKey classified %s, want the first rule's low", got.Severity)
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	classifier, err := Load(writeRules(t, "rules.json", `{"rules": [{"match": "PutKeyPolicy", "severity": "medium", "tags": ["policy"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, ok := classifier.Classify("PutKeyPolicy")
+	if !ok || got.Severity != SeverityMedium || !reflect.DeepEqual(got.Tags, []string{"policy"}) {
+		t.Errorf("Classify(PutKeyPolicy) = %+v, %v", got, ok)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	tests := map[string]string{
+		"both":     "rules:\n  - match: A\n    pattern: B\n    severity: low\n",
+		"neither":  "rules:\n  - severity: low\n",
+		"pattern":  "rules:\n  - pattern: \"(\"\n    severity: low\n",
+		"severity": "rules:\n  - match: A\n    severity: severe\n",
+		"syntax":   "rules: [",
+	}
+	for name, content := range tests {
+		if _, err := Load(writeRules(t, name+".yaml", content)); err == nil {
+			t.Errorf("%s: Load accepted %q", name, content)
+		}
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "failed to read") {
+		t.Errorf("Load of a missing file = %v", err)
+	}
+}
+
+func TestNilClassifier(t *testing.T) {
+	var classifier *Classifier
+	if _, ok := classifier.Classify("DisableKey"); ok {
+		t.Error("a nil classifier classified an event")
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	if got, err := ParseSeverity("HIGH"); err != nil || got != SeverityHigh {
+		t.Errorf("ParseSeverity(HIGH) = %v, %v", got, err)
+	}
+	// "none" is the unclassified state, not a usable threshold
+	for _, name := range []string{"none", "urgent", ""} {
+		if _, err := ParseSeverity(name); err == nil {
+			t.Errorf("ParseSeverity(%q) accepted", name)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// internal/config/config.go
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds user settings shared by every command
+type Config struct {
+	// TimeRanges defines named --last-n presets, e.g. "business-hours":
+	// "2024-01-15 09:00 to 2024-01-15 17:00" or "since-deploy": "since 2024-01-15 14:30"
+	TimeRanges map[string]string `yaml:"timeRanges"`
+}
+
+// DefaultPath is the config file read when --config isn't given
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "cloudtrail-logs", "config.yaml")
+}
+
+// Load reads a YAML or JSON config file. A missing file at the default path
+// is not an error; one given explicitly must exist.
+func Load(path string, explicit bool) (*Config, error) {
+	var cfg Config
+	if path == "" {
+		return &cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && !explicit {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	// JSON is a subset of YAML, so one decoder handles both
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,52 @@
+// internal/config/config_test.go
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadTimeRanges(t *testing.T) {
+	want := map[string]string{
+		"business-hours": "2024-01-15 09:00 to 2024-01-15 17:00",
+		"since-deploy":   "since 2024-01-15 14:30",
+	}
+	for name, content := range map[string]string{
+		"config.yaml": "timeRanges:\n  business-hours: 2024-01-15 09:00 to 2024-01-15 17:00\n  since-deploy: since 2024-01-15 14:30\n",
+		"config.json": `{"timeRanges": {"business-hours": "2024-01-15 09:00 to 2024-01-15 17:00", "since-deploy": "since 2024-01-15 14:30"}}`,
+	} {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := Load(path, true)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(cfg.TimeRanges, want) {
+			t.Errorf("%s: TimeRanges = %v, want %v", name, cfg.TimeRanges, want)
+		}
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if cfg, err := Load(path, false); err != nil || cfg.TimeRanges != nil {
+		t.Errorf("Load of a missing default file = %v, %v; want an empty config", cfg, err)
+	}
+	if _, err := Load(path, true); err == nil {
+		t.Error("Load accepted a missing --config file")
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("timeRanges: [unclosed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path, false); err == nil {
+		t.Error("Load accepted invalid YAML")
+	}
+}
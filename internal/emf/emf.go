@@ -0,0 +1,103 @@
+// internal/emf/emf.go
+package emf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// DefaultNamespace is the CloudWatch namespace used when none is configured
+const DefaultNamespace = "CloudTrailLogs"
+
+// Common CloudWatch units
+const (
+	UnitCount        = "Count"
+	UnitMilliseconds = "Milliseconds"
+)
+
+type Metric struct {
+	Name  string
+	Unit  string
+	Value float64
+}
+
+// Document is a single Embedded Metric Format log line. CloudWatch extracts
+// each metric from it, dimensioned by every key in Dimensions.
+type Document struct {
+	Namespace  string
+	Timestamp  time.Time
+	Dimensions map[string]string
+	Metrics    []Metric
+}
+
+type metricDefinition struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit,omitempty"`
+}
+
+type metricDirective struct {
+	Namespace  string             `json:"Namespace"`
+	Dimensions [][]string         `json:"Dimensions"`
+	Metrics    []metricDefinition `json:"Metrics"`
+}
+
+type metadata struct {
+	Timestamp         int64             `json:"Timestamp"`
+	CloudWatchMetrics []metricDirective `json:"CloudWatchMetrics"`
+}
+
+func (d Document) MarshalJSON() ([]byte, error) {
+	namespace := d.Namespace
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+
+	dimensionKeys := make([]string, 0, len(d.Dimensions))
+	for key := range d.Dimensions {
+		dimensionKeys = append(dimensionKeys, key)
+	}
+	sort.Strings(dimensionKeys)
+
+	directive := metricDirective{
+		Namespace:  namespace,
+		Dimensions: [][]string{dimensionKeys},
+	}
+
+	// Metric values and dimension values live at the top level of the document
+	root := make(map[string]interface{}, len(d.Dimensions)+len(d.Metrics)+1)
+	for key, value := range d.Dimensions {
+		root[key] = value
+	}
+	for _, metric := range d.Metrics {
+		if _, clash := root[metric.Name]; clash || metric.Name == "_aws" {
+			return nil, fmt.Errorf("metric name %q collides with another field", metric.Name)
+		}
+		directive.Metrics = append(directive.Metrics, metricDefinition{Name: metric.Name, Unit: metric.Unit})
+		root[metric.Name] = metric.Value
+	}
+
+	root["_aws"] = metadata{
+		Timestamp:         d.Timestamp.UnixMilli(),
+		CloudWatchMetrics: []metricDirective{directive},
+	}
+
+	return json.Marshal(root)
+}
+
+// Write emits each document as one compact JSON line, which is what the
+// CloudWatch agent and Lambda log parsing expect
+func Write(w io.Writer, docs []Document) error {
+	for _, doc := range docs {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("failed to encode EMF document: %v", err)
+		}
+		if _, err := fmt.Fprintf(w, "%s\n", data); err != nil {
+			return fmt.Errorf("failed to write EMF document: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// internal/emf/emf_test.go
+package emf
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteDocument(t *testing.T) {
+	timestamp := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	doc := Document{
+		Namespace:  "Test",
+		Timestamp:  timestamp,
+		Dimensions: map[string]string{"EventSource": "kms.amazonaws.com"},
+		Metrics: []Metric{
+			{Name: "MatchedEvents", Unit: UnitCount, Value: 12},
+			{Name: "ScanDuration", Unit: UnitMilliseconds, Value: 350},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, []Document{doc, doc}); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one per document:\n%s", len(lines), buf.String())
+	}
+
+	var got struct {
+		AWS struct {
+			Timestamp         int64
+			CloudWatchMetrics []struct {
+				Namespace  string
+				Dimensions [][]string
+				Metrics    []struct{ Name, Unit string }
+			}
+		} `json:"_aws"`
+		EventSource   string
+		MatchedEvents float64
+		ScanDuration  float64
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+
+	if got.AWS.Timestamp != timestamp.UnixMilli() {
+		t.Errorf("_aws.Timestamp = %d, want %d", got.AWS.Timestamp, timestamp.UnixMilli())
+	}
+	if len(got.AWS.CloudWatchMetrics) != 1 {
+		t.Fatalf("got %d CloudWatchMetrics directives, want 1", len(got.AWS.CloudWatchMetrics))
+	}
+	directive := got.AWS.CloudWatchMetrics[0]
+	if directive.Namespace != "Test" {
+		t.Errorf("Namespace = %q, want Test", directive.Namespace)
+	}
+	if !reflect.DeepEqual(directive.Dimensions, [][]string{{"EventSource"}}) {
+		t.Errorf("Dimensions = %v, want [[EventSource]]", directive.Dimensions)
+	}
+	wantMetrics := []struct{ Name, Unit string }{{"MatchedEvents", UnitCount}, {"ScanDuration", UnitMilliseconds}}
+	if !reflect.DeepEqual(directive.Metrics, wantMetrics) {
+		t.Errorf("Metrics = %v, want %v", directive.Metrics, wantMetrics)
+	}
+
+	// Dimension and metric values sit at the top level
+	if got.EventSource != "kms.amazonaws.com" || got.MatchedEvents != 12 || got.ScanDuration != 350 {
+		t.Errorf("top-level values = %q, %v, %v", got.EventSource, got.MatchedEvents, got.ScanDuration)
+	}
+}
+
+func TestDefaultNamespace(t *testing.T) {
+	data, err := json.Marshal(Document{Metrics: []Metric{{Name: "MatchedEvents", Value: 1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"Namespace":"`+DefaultNamespace+`"`) {
+		t.Errorf("document without a namespace doesn't use %s: %s", DefaultNamespace, data)
+	}
+}
+
+func TestMetricNameCollision(t *testing.T) {
+	for _, doc := range []Document{
+		{Dimensions: map[string]string{"EventSource": "kms"}, Metrics: []Metric{{Name: "EventSource"}}},
+		{Metrics: []Metric{{Name: "_aws"}}},
+	} {
+		if _, err := json.Marshal(doc); err == nil {
+			t.Errorf("Marshal(%+v) = nil error, want a collision error", doc)
+		}
+	}
+}
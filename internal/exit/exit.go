@@ -0,0 +1,18 @@
+// internal/exit/exit.go
+package exit
+
+import "fmt"
+
+// Interrupted is the conventional exit status for a run stopped by Ctrl-C
+// (128 + SIGINT)
+const Interrupted = 130
+
+// Error asks main to exit with Code. It carries no message of its own, so
+// nothing extra is printed when a command wants to end silently.
+type Error struct {
+	Code int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
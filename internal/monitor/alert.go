@@ -0,0 +1,109 @@
+// internal/monitor/alert.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/arns"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// Supported --alert-by dimensions
+const (
+	AlertByUser = "user"
+	AlertByKey  = "key"
+)
+
+type alertCount struct {
+	principal string
+	count     int
+}
+
+// alertCounter tallies matched events per principal for threshold alerting
+type alertCounter struct {
+	by        string
+	counts    map[string]int
+	normalize arns.Context // zero value leaves keys as recorded
+}
+
+func newAlertCounter(by string) *alertCounter {
+	return &alertCounter{by: by, counts: make(map[string]int)}
+}
+
+func (a *alertCounter) add(event types.Event, details map[string]interface{}) {
+	switch a.by {
+	case AlertByKey:
+		for _, key := range eventKeys(event, details) {
+			if a.normalize != (arns.Context{}) {
+				key = arns.Normalize(key, a.normalize)
+			}
+			a.counts[key]++
+		}
+	default:
+		a.counts[SafeString(event.Username)]++
+	}
+}
+
+// exceeding returns the principals with more than threshold events, highest first
+func (a *alertCounter) exceeding(threshold int) []alertCount {
+	var results []alertCount
+	for principal, count := range a.counts {
+		if count > threshold {
+			results = append(results, alertCount{principal: principal, count: count})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].count != results[j].count {
+			return results[i].count > results[j].count
+		}
+		return results[i].principal < results[j].principal
+	})
+	return results
+}
+
+// eventKeys returns the distinct KMS keys referenced by an event
+func eventKeys(event types.Event, details map[string]interface{}) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, resource := range event.Resources {
+		if resource.ResourceType != nil && resource.ResourceName != nil &&
+			*resource.ResourceType == "AWS::KMS::Key" && !seen[*resource.ResourceName] {
+			seen[*resource.ResourceName] = true
+			keys = append(keys, *resource.ResourceName)
+		}
+	}
+	if len(keys) == 0 && details != nil {
+		if reqParams, ok := details["requestParameters"].(map[string]interface{}); ok {
+			if keyID, ok := reqParams["keyId"].(string); ok && keyID != "" {
+				keys = append(keys, keyID)
+			}
+		}
+	}
+	return keys
+}
+
+// ValidateAlertBy checks the --alert-by dimension
+func ValidateAlertBy(by string) error {
+	if by != AlertByUser && by != AlertByKey {
+		return fmt.Errorf("invalid --alert-by value %q: use %q or %q", by, AlertByUser, AlertByKey)
+	}
+	return nil
+}
+
+// report prints principals above the threshold and reports whether any were found
+func (a *alertCounter) report(out io.Writer, threshold int) bool {
+	exceeded := a.exceeding(threshold)
+	if len(exceeded) == 0 {
+		fmt.Fprintf(out, "\nNo %s exceeded the alert threshold of %d events\n", a.by, threshold)
+		return false
+	}
+
+	fmt.Fprintln(out, theme.Error(fmt.Sprintf("\nAlert: %d %s(s) exceeded %d events:", len(exceeded), a.by, threshold)))
+	for _, result := range exceeded {
+		fmt.Fprintf(out, "  - %s: %d events\n", result.principal, result.count)
+	}
+	return true
+}
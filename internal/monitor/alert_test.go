@@ -0,0 +1,69 @@
+// internal/monitor/alert_test.go
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// alertEvents has alice calling Decrypt four times and bob once
+func alertEvents() []types.Event {
+	var events []types.Event
+	for i, user := range []string{"alice", "bob", "alice", "alice", "alice"} {
+		events = append(events, newEvent(string(rune('a'+i)), "Decrypt", user, i, nil))
+	}
+	return events
+}
+
+func TestAlertCounterExceeding(t *testing.T) {
+	counter := newAlertCounter(AlertByUser)
+	for _, event := range alertEvents() {
+		counter.add(event, nil)
+	}
+
+	exceeded := counter.exceeding(3)
+	if len(exceeded) != 1 || exceeded[0].principal != "alice" || exceeded[0].count != 4 {
+		t.Errorf("exceeding(3) = %+v, want only alice with 4", exceeded)
+	}
+	// The threshold itself isn't an alert
+	if exceeded := counter.exceeding(4); len(exceeded) != 0 {
+		t.Errorf("exceeding(4) = %+v, want none", exceeded)
+	}
+}
+
+func TestAlertCounterByKey(t *testing.T) {
+	counter := newAlertCounter(AlertByKey)
+	keyed := newEvent("1", "Decrypt", "alice", 0, nil)
+	keyed.Resources = []types.Resource{{ResourceType: sdkaws.String("AWS::KMS::Key"), ResourceName: sdkaws.String("key-1")}}
+	counter.add(keyed, nil)
+	counter.add(keyed, nil)
+	counter.add(newEvent("2", "Decrypt", "bob", 1, nil), map[string]interface{}{
+		"requestParameters": map[string]interface{}{"keyId": "key-2"},
+	})
+
+	exceeded := counter.exceeding(1)
+	if len(exceeded) != 1 || exceeded[0].principal != "key-1" {
+		t.Errorf("exceeding(1) = %+v, want only key-1", exceeded)
+	}
+}
+
+func TestScanAlertFail(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{alertEvents()}}
+
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{AlertThreshold: 3, AlertBy: AlertByUser, AlertFail: true}, nil)
+	if err == nil || !strings.Contains(err.Error(), "alert threshold of 3 events exceeded") {
+		t.Errorf("scan error = %v, want the alert threshold error", err)
+	}
+	if !strings.Contains(out, "alice: 4 events") || strings.Contains(out, "bob:") {
+		t.Errorf("alert report should list only alice:\n%s", out)
+	}
+
+	// Below the threshold the scan succeeds
+	trail = &fakeTrail{pages: [][]types.Event{alertEvents()}}
+	if _, err := scan(t, trail, FilterOptions{}, OutputOptions{AlertThreshold: 4, AlertBy: AlertByUser, AlertFail: true}, nil); err != nil {
+		t.Errorf("scan under the threshold = %v, want nil", err)
+	}
+}
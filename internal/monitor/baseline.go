@@ -0,0 +1,141 @@
+// internal/monitor/baseline.go
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// combination is one event name seen from one principal
+type combination struct {
+	EventName string `json:"eventName"`
+	User      string `json:"user"`
+}
+
+// baselineFile is the persisted form of a Baseline
+type baselineFile struct {
+	Combinations []combination `json:"combinations"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+}
+
+// Baseline is a saved set of event+user combinations. Combinations missing
+// from it are flagged as new behavior; with update set, they are added and the
+// file is rewritten after the scan.
+type Baseline struct {
+	path   string
+	update bool
+	seen   map[combination]bool
+}
+
+// LoadBaseline reads a baseline file. A missing file is only allowed when
+// updating, where it starts an empty baseline.
+func LoadBaseline(path string, update bool) (*Baseline, error) {
+	baseline := &Baseline{path: path, update: update, seen: make(map[combination]bool)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && update {
+		return baseline, nil
+	}
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("baseline file %s not found: create it with --update-baseline", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file: %v", err)
+	}
+
+	var file baselineFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid baseline file %s: %v", path, err)
+	}
+	for _, combo := range file.Combinations {
+		baseline.seen[combo] = true
+	}
+	return baseline, nil
+}
+
+func eventCombination(event types.Event) combination {
+	return combination{EventName: SafeString(event.EventName), User: SafeString(event.Username)}
+}
+
+// sortCombinations orders combinations by event name, then user
+func sortCombinations(combos []combination) {
+	sort.Slice(combos, func(i, j int) bool {
+		if combos[i].EventName != combos[j].EventName {
+			return combos[i].EventName < combos[j].EventName
+		}
+		return combos[i].User < combos[j].User
+	})
+}
+
+// save writes the baseline, including combinations added during the scan
+func (b *Baseline) save() error {
+	file := baselineFile{Combinations: make([]combination, 0, len(b.seen)), UpdatedAt: time.Now()}
+	for combo := range b.seen {
+		file.Combinations = append(file.Combinations, combo)
+	}
+	sortCombinations(file.Combinations)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %v", err)
+	}
+	if err := writeAtomic(b.path, data); err != nil {
+		return fmt.Errorf("failed to write baseline file: %v", err)
+	}
+	return nil
+}
+
+// newCombinations counts matched events per combination missing from the baseline
+type newCombinations struct {
+	baseline *Baseline
+	counts   map[combination]int
+}
+
+func newNewCombinations(baseline *Baseline) *newCombinations {
+	return &newCombinations{baseline: baseline, counts: make(map[combination]int)}
+}
+
+// add records an event, reporting whether its combination is new
+func (n *newCombinations) add(event types.Event) bool {
+	combo := eventCombination(event)
+	if n.baseline.seen[combo] {
+		return false
+	}
+	n.counts[combo]++
+	return true
+}
+
+// report prints the new combinations, then saves the baseline when updating
+func (n *newCombinations) report(out io.Writer) {
+	if len(n.counts) > 0 {
+		combos := make([]combination, 0, len(n.counts))
+		for combo := range n.counts {
+			combos = append(combos, combo)
+		}
+		sortCombinations(combos)
+
+		fmt.Fprintln(out, theme.Warning(fmt.Sprintf("\nNew: %d event/user combinations not in the baseline:", len(combos))))
+		for _, combo := range combos {
+			fmt.Fprintf(out, "  - %s by %s: %d events\n", combo.EventName, combo.User, n.counts[combo])
+		}
+	}
+
+	if !n.baseline.update {
+		return
+	}
+	for combo := range n.counts {
+		n.baseline.seen[combo] = true
+	}
+	if err := n.baseline.save(); err != nil {
+		fmt.Fprintf(out, theme.Warning("Warning: %v\n"), err)
+		return
+	}
+	fmt.Fprintf(out, "Baseline %s updated (%d combinations)\n", n.baseline.path, len(n.baseline.seen))
+}
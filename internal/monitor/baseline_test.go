@@ -0,0 +1,73 @@
+// internal/monitor/baseline_test.go
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestBaselineFlagsNewCombination(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	first := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice", 1, nil),
+		newEvent("2", "Encrypt", "alice", 2, nil),
+	}}}
+
+	// The first run builds the baseline
+	baseline, err := LoadBaseline(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := scan(t, first, FilterOptions{}, OutputOptions{Baseline: baseline}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Baseline "+path+" updated (2 combinations)") {
+		t.Fatalf("baseline not saved:\n%s", out)
+	}
+
+	// A later run flags only what the baseline hasn't seen
+	second := &fakeTrail{pages: [][]types.Event{{
+		newEvent("3", "Decrypt", "alice", 3, nil),
+		newEvent("4", "Decrypt", "mallory", 4, nil),
+		newEvent("5", "Decrypt", "mallory", 5, nil),
+	}}}
+	if baseline, err = LoadBaseline(path, false); err != nil {
+		t.Fatal(err)
+	}
+	out, err = scan(t, second, FilterOptions{}, OutputOptions{Baseline: baseline}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "Baseline: new event/user combination"); n != 2 {
+		t.Errorf("flagged %d events, want mallory's 2:\n%s", n, out)
+	}
+	if !strings.Contains(out, "New: 1 event/user combinations not in the baseline:\n  - Decrypt by mallory: 2 events\n") {
+		t.Errorf("new combinations not summarized:\n%s", out)
+	}
+	if strings.Contains(out, "updated") {
+		t.Errorf("baseline rewritten without update:\n%s", out)
+	}
+}
+
+func TestLoadBaselineErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadBaseline(filepath.Join(dir, "missing.json"), false); err == nil || !strings.Contains(err.Error(), "--update-baseline") {
+		t.Errorf("LoadBaseline of a missing file = %v, want a hint to create it", err)
+	}
+	if baseline, err := LoadBaseline(filepath.Join(dir, "missing.json"), true); err != nil || len(baseline.seen) != 0 {
+		t.Errorf("LoadBaseline of a missing file to update = %v, %v; want an empty baseline", baseline, err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadBaseline(invalid, true); err == nil {
+		t.Error("LoadBaseline accepted invalid JSON")
+	}
+}
@@ -0,0 +1,112 @@
+// internal/monitor/cancel_test.go
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+func TestCancelBeforeSecondPage(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trail := &fakeTrail{pages: threePages(), onCall: func(call int) {
+		if call == 2 {
+			cancel()
+		}
+	}}
+
+	out, err := scanContext(ctx, t, trail, FilterOptions{}, OutputOptions{}, nil)
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("scan = %v, want an interrupted context error", err)
+	}
+	if trail.calls > 2 {
+		t.Errorf("made %d LookupEvents calls after cancelling on the second", trail.calls)
+	}
+	if strings.Contains(out, "00:02:00") || strings.Contains(out, "00:03:00") {
+		t.Errorf("printed events from pages after the cancellation:\n%s", out)
+	}
+	if !strings.Contains(out, "Scan interrupted; the results below are partial") {
+		t.Errorf("partial results not noted:\n%s", out)
+	}
+}
+
+// cancelOnWrite cancels once the console is written text containing marker
+type cancelOnWrite struct {
+	bytes.Buffer
+	marker string
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnWrite) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), c.marker) {
+		c.cancel()
+	}
+	return c.Buffer.Write(p)
+}
+
+func TestInterruptLeavesValidLog(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Interrupt while the first page's event is being shown
+	console := &cancelOnWrite{marker: "00:01:00", cancel: cancel}
+	trail := &fakeTrail{pages: threePages()}
+	logFile := filepath.Join(t.TempDir(), "events.json")
+	export := &writer.ExportOptions{Filename: logFile, Format: writer.FormatJSONDocument}
+
+	_, err := scanContext(ctx, t, trail, FilterOptions{}, OutputOptions{Console: console, Summary: true}, export)
+	if !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("scan = %v, want it interrupted", err)
+	}
+	if out := console.String(); !strings.Contains(out, "Summary:") || strings.Contains(out, "00:02:00") {
+		t.Errorf("want a summary of just the first page:\n%s", out)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Metadata struct {
+			EventCount int `json:"eventCount"`
+		} `json:"metadata"`
+		Events []map[string]interface{} `json:"events"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("log isn't valid JSON after the interrupt: %v\n%s", err, data)
+	}
+	if len(doc.Events) != 1 || doc.Metadata.EventCount != 1 {
+		t.Errorf("log has %d events (metadata says %d), want the first page's 1", len(doc.Events), doc.Metadata.EventCount)
+	}
+}
+
+func TestCancelledBeforeScan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := scanContext(ctx, t, &fakeTrail{pages: threePages()}, FilterOptions{}, OutputOptions{}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("scan = %v, want context.Canceled", err)
+	}
+}
+
+func TestScanTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	trail := &fakeTrail{pages: threePages(), onCall: func(call int) {
+		if call == 2 {
+			<-ctx.Done()
+		}
+	}}
+	_, err := scanContext(ctx, t, trail, FilterOptions{}, OutputOptions{}, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("scan = %v, want context.DeadlineExceeded", err)
+	}
+}
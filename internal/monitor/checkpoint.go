@@ -0,0 +1,82 @@
+// internal/monitor/checkpoint.go
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// scanState is persisted after every page so an interrupted scan can resume
+// from the exact page it stopped at
+type scanState struct {
+	StartTime     time.Time  `json:"startTime"`
+	EndTime       time.Time  `json:"endTime"`
+	NextToken     string     `json:"nextToken"`
+	Pages         int        `json:"pages"`
+	Matched       int        `json:"matched"`
+	LastEventTime *time.Time `json:"lastEventTime,omitempty"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// loadScanState reads a state file, returning nil when there is nothing to resume
+func loadScanState(path string) (*scanState, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %v", err)
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var state scanState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %v", path, err)
+	}
+	if state.NextToken == "" {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+// save writes the state atomically so a crash mid-write can't corrupt it
+func (s *scanState) save(path string) error {
+	s.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %v", err)
+	}
+
+	if err := writeAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	return nil
+}
+
+// writeAtomic replaces path with data via a temp file and rename, so a crash
+// mid-write can't leave a truncated file behind
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
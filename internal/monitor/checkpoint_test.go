@@ -0,0 +1,135 @@
+// internal/monitor/checkpoint_test.go
+package monitor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func threePages() [][]types.Event {
+	return [][]types.Event{
+		{newEvent("1", "Decrypt", "alice", 1, nil)},
+		{newEvent("2", "Decrypt", "alice", 2, nil)},
+		{newEvent("3", "Decrypt", "alice", 3, nil)},
+	}
+}
+
+func TestResumeFromSavedToken(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "scan.state")
+	output := OutputOptions{StateFile: stateFile}
+
+	// Interrupt the scan once the first page is checkpointed, while the
+	// second is being fetched
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	first := &fakeTrail{pages: threePages(), onCall: func(call int) {
+		if call != 2 {
+			return
+		}
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+			if state, _ := loadScanState(stateFile); state != nil {
+				break
+			}
+		}
+		cancel()
+	}}
+	if _, err := scanContext(ctx, t, first, FilterOptions{}, output, nil); !errors.Is(err, ErrInterrupted) {
+		t.Fatalf("interrupted scan = %v, want ErrInterrupted", err)
+	}
+
+	state, err := loadScanState(stateFile)
+	if err != nil || state == nil {
+		t.Fatalf("no state saved after the interruption: %v", err)
+	}
+	if state.NextToken != "page-1" || state.Pages != 1 || state.Matched != 1 {
+		t.Errorf("saved state = %+v, want one page done and page-1 next", state)
+	}
+	if !state.StartTime.Equal(testStart) || !state.EndTime.Equal(testStart.Add(24*time.Hour)) {
+		t.Errorf("saved window = %s to %s", state.StartTime, state.EndTime)
+	}
+
+	second := &fakeTrail{pages: threePages()}
+	out, err := scan(t, second, FilterOptions{}, output, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := second.inputs[0].NextToken; got == nil || *got != "page-1" {
+		t.Errorf("resumed lookup started from token %v, want page-1", got)
+	}
+	if second.calls != 2 {
+		t.Errorf("resumed scan made %d calls, want only the 2 remaining pages", second.calls)
+	}
+	if !strings.Contains(out, "Resuming scan from") {
+		t.Errorf("resume wasn't announced:\n%s", out)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("state file kept after the scan completed (stat error %v)", err)
+	}
+}
+
+func TestResumeUsesSavedWindow(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "scan.state")
+	saved := &scanState{StartTime: testStart.Add(-time.Hour), EndTime: testStart.Add(time.Hour), NextToken: "page-1"}
+	if err := saved.save(stateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	trail := &fakeTrail{pages: threePages()}
+	if _, err := scan(t, trail, FilterOptions{}, OutputOptions{StateFile: stateFile}, nil); err != nil {
+		t.Fatal(err)
+	}
+	input := trail.inputs[0]
+	if !input.StartTime.Equal(saved.StartTime) || !input.EndTime.Equal(saved.EndTime) {
+		t.Errorf("resumed lookup window = %s to %s, want the saved one", input.StartTime, input.EndTime)
+	}
+}
+
+func TestExactWindowRejectsMismatchedState(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "scan.state")
+	saved := &scanState{StartTime: testStart.Add(-time.Hour), EndTime: testStart.Add(time.Hour), NextToken: "page-1"}
+	if err := saved.save(stateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	trail := &fakeTrail{pages: threePages()}
+	_, err := scan(t, trail, FilterOptions{}, OutputOptions{StateFile: stateFile, ExactWindow: true}, nil)
+	if err == nil || !strings.Contains(err.Error(), "not the requested") {
+		t.Fatalf("scan = %v, want a window mismatch error", err)
+	}
+	if trail.calls != 0 {
+		t.Errorf("made %d lookups despite the mismatch", trail.calls)
+	}
+}
+
+func TestLoadScanStateNothingToResume(t *testing.T) {
+	dir := t.TempDir()
+	empty := filepath.Join(dir, "empty.state")
+	if err := os.WriteFile(empty, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	finished := filepath.Join(dir, "finished.state")
+	if err := (&scanState{StartTime: testStart, EndTime: testStart}).save(finished); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{filepath.Join(dir, "missing.state"), empty, finished} {
+		if state, err := loadScanState(path); state != nil || err != nil {
+			t.Errorf("loadScanState(%s) = %+v, %v; want nothing to resume", filepath.Base(path), state, err)
+		}
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.state")
+	if err := os.WriteFile(corrupt, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadScanState(corrupt); err == nil {
+		t.Error("loadScanState accepted a corrupt file")
+	}
+}
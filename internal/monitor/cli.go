@@ -0,0 +1,119 @@
+// internal/monitor/cli.go
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// awsCLICommand renders the `aws cloudtrail lookup-events` call equivalent to input
+func awsCLICommand(input *cloudtrail.LookupEventsInput, profile, region string) string {
+	args := []string{"aws", "cloudtrail", "lookup-events"}
+
+	if input.StartTime != nil {
+		args = append(args, "--start-time", input.StartTime.UTC().Format(time.RFC3339))
+	}
+	if input.EndTime != nil {
+		args = append(args, "--end-time", input.EndTime.UTC().Format(time.RFC3339))
+	}
+	if input.EventCategory != "" {
+		args = append(args, "--event-category", string(input.EventCategory))
+	}
+	for _, attribute := range input.LookupAttributes {
+		args = append(args, "--lookup-attributes",
+			shellQuote(fmt.Sprintf("AttributeKey=%s,AttributeValue=%s", attribute.AttributeKey, SafeString(attribute.AttributeValue))))
+	}
+	if profile != "" {
+		args = append(args, "--profile", shellQuote(profile))
+	}
+	if region != "" {
+		args = append(args, "--region", shellQuote(region))
+	}
+
+	return strings.Join(args, " ")
+}
+
+// clientSideFilters lists the filters the CLI command can't express, skipping
+// the one already sent as a lookup attribute
+func clientSideFilters(filters FilterOptions, attributes []types.LookupAttribute) []string {
+	pushed := make(map[types.LookupAttributeKey]bool)
+	for _, attribute := range attributes {
+		pushed[attribute.AttributeKey] = true
+	}
+
+	var remaining []string
+	if filters.EventSource != "" && !pushed[types.LookupAttributeKeyEventSource] {
+		remaining = append(remaining, "(event source "+filters.EventSource+")")
+	}
+	if filters.KeyID != "" && !pushed[types.LookupAttributeKeyResourceName] {
+		remaining = append(remaining, "--key "+shellQuote(filters.KeyID))
+	}
+	if filters.InstanceID != "" {
+		remaining = append(remaining, "--instance-id "+shellQuote(filters.InstanceID))
+	}
+	if filters.TopicARN != "" && !pushed[types.LookupAttributeKeyResourceName] {
+		remaining = append(remaining, "--topic-arn "+shellQuote(filters.TopicARN))
+	}
+	if filters.EventName != "" && !pushed[types.LookupAttributeKeyEventName] {
+		remaining = append(remaining, "--event "+shellQuote(filters.EventName))
+	}
+	if filters.UserName != "" && !pushed[types.LookupAttributeKeyUsername] {
+		remaining = append(remaining, "--user "+shellQuote(filters.UserName))
+	}
+	if filters.Operation != "" {
+		remaining = append(remaining, "--operation "+shellQuote(filters.Operation))
+	}
+	if filters.Role != "" {
+		remaining = append(remaining, "--role "+shellQuote(filters.Role))
+	}
+	if filters.MinTLS != "" {
+		remaining = append(remaining, "--min-tls "+shellQuote(filters.MinTLS))
+	}
+	if filters.HasParam != "" {
+		remaining = append(remaining, "--has-param "+shellQuote(filters.HasParam))
+	}
+	if filters.ResponseParam != "" {
+		remaining = append(remaining, "--response-param "+shellQuote(filters.ResponseParam))
+	}
+	if filters.SourceIP != "" {
+		remaining = append(remaining, "--source-ip "+shellQuote(filters.SourceIP))
+	}
+	if filters.HumansOnly {
+		remaining = append(remaining, "--humans-only")
+	}
+	if filters.ConsoleOnly {
+		remaining = append(remaining, "--console-only")
+	}
+	if filters.MutationsOnly {
+		remaining = append(remaining, "--mutations-only")
+	}
+	for _, name := range filters.ExcludeEvents {
+		remaining = append(remaining, "--exclude-event "+shellQuote(name))
+	}
+	if filters.ErrorMessage != nil {
+		remaining = append(remaining, "--error-message "+shellQuote(errorMessagePattern(filters.ErrorMessage)))
+	}
+	if filters.ErrorCode != "" {
+		remaining = append(remaining, "--error-code "+shellQuote(filters.ErrorCode))
+	} else if filters.ErrorsOnly {
+		remaining = append(remaining, "--errors-only")
+	}
+	if filters.SuccessOnly {
+		remaining = append(remaining, "--success-only")
+	}
+	return remaining
+}
+
+// shellQuote single-quotes a value when it contains characters the shell would interpret
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./:=@,", r))
+	}) < 0 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,71 @@
+// internal/monitor/cli_test.go
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestAWSCLICommand(t *testing.T) {
+	end := testStart.Add(6 * time.Hour)
+	input := &cloudtrail.LookupEventsInput{
+		StartTime:     &testStart,
+		EndTime:       &end,
+		EventCategory: types.EventCategoryInsight,
+		LookupAttributes: []types.LookupAttribute{{
+			AttributeKey:   types.LookupAttributeKeyEventSource,
+			AttributeValue: sdkaws.String("kms.amazonaws.com"),
+		}},
+	}
+
+	want := "aws cloudtrail lookup-events --start-time 2024-01-15T00:00:00Z --end-time 2024-01-15T06:00:00Z" +
+		" --event-category insight --lookup-attributes AttributeKey=EventSource,AttributeValue=kms.amazonaws.com" +
+		" --profile 'my profile' --region eu-west-1"
+	if got := awsCLICommand(input, "my profile", "eu-west-1"); got != want {
+		t.Errorf("awsCLICommand =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestShowCLIMatchesScan(t *testing.T) {
+	keyARN := "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	trail := &fakeTrail{pages: [][]types.Event{{}}}
+	out, err := scan(t, trail, FilterOptions{KeyID: keyARN, UserName: "alice"}, OutputOptions{ShowCLI: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "  aws cloudtrail lookup-events --start-time 2024-01-15T00:00:00Z --end-time 2024-01-16T00:00:00Z" +
+		" --lookup-attributes AttributeKey=ResourceName,AttributeValue=" + keyARN +
+		" --profile test --region us-east-1\n" +
+		"  (applied client-side by this tool: --user alice)\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("printed command doesn't match the scan:\n%s\nwant\n%s", out, want)
+	}
+
+	// The printed attribute is the one actually sent
+	sent := trail.inputs[0].LookupAttributes
+	if len(sent) != 1 || *sent[0].AttributeValue != keyARN {
+		t.Errorf("lookup sent %+v", sent)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"alice":                 "alice",
+		"arn:aws:iam::1:role/x": "arn:aws:iam::1:role/x",
+		"two words":             "'two words'",
+		"it's":                  `'it'\''s'`,
+		"$(rm -rf)":             "'$(rm -rf)'",
+		"":                      "''",
+	}
+	for value, want := range tests {
+		if got := shellQuote(value); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
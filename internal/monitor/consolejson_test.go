@@ -0,0 +1,56 @@
+// internal/monitor/consolejson_test.go
+package monitor
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// jsonBlocks cuts the top-level objects printed at the start of a line, each
+// running to its closing brace
+func jsonBlocks(out string) []string {
+	var blocks []string
+	var current []string
+	for _, line := range strings.Split(out, "\n") {
+		switch {
+		case line == "{":
+			current = []string{line}
+		case current != nil:
+			current = append(current, line)
+			if line == "}" {
+				blocks = append(blocks, strings.Join(current, "\n"))
+				current = nil
+			}
+		}
+	}
+	return blocks
+}
+
+func TestConsoleJSON(t *testing.T) {
+	out, err := scan(t, newestFirst(), FilterOptions{}, OutputOptions{ConsoleJSON: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocks := jsonBlocks(out)
+	if len(blocks) != 3 {
+		t.Fatalf("printed %d JSON events, want 3:\n%s", len(blocks), out)
+	}
+	for i, block := range blocks {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(block), &record); err != nil {
+			t.Errorf("event %d isn't valid JSON: %v\n%s", i, err, block)
+			continue
+		}
+		if record["eventName"] != "Decrypt" || record["user"] != "alice" {
+			t.Errorf("event %d = %v", i, record)
+		}
+		if !strings.HasPrefix(strings.Split(block, "\n")[1], `  "`) {
+			t.Errorf("event %d isn't indented:\n%s", i, block)
+		}
+	}
+	if strings.Contains(out, "User: alice") {
+		t.Errorf("printed the field layout as well as JSON:\n%s", out)
+	}
+}
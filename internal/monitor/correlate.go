@@ -0,0 +1,40 @@
+// internal/monitor/correlate.go
+package monitor
+
+type requestGroup struct {
+	requestID string
+	events    []int // positions of the grouped events
+}
+
+// requestID extracts the CloudTrail requestID from the parsed event body
+func requestID(details map[string]interface{}) string {
+	if details == nil {
+		return ""
+	}
+	id, _ := details["requestID"].(string)
+	return id
+}
+
+// groupByRequestID pairs the events at the given positions that share a
+// requestID, keeping groups in the order their first event was seen. Events
+// without a requestID stay on their own.
+func groupByRequestID(positions []int, requestIDs []string) []requestGroup {
+	var groups []requestGroup
+	index := make(map[string]int)
+
+	for _, position := range positions {
+		id := requestIDs[position]
+		if id == "" {
+			groups = append(groups, requestGroup{events: []int{position}})
+			continue
+		}
+		if i, ok := index[id]; ok {
+			groups[i].events = append(groups[i].events, position)
+			continue
+		}
+		index[id] = len(groups)
+		groups = append(groups, requestGroup{requestID: id, events: []int{position}})
+	}
+
+	return groups
+}
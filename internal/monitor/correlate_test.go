@@ -0,0 +1,47 @@
+// internal/monitor/correlate_test.go
+package monitor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestGroupByRequestID(t *testing.T) {
+	groups := groupByRequestID([]int{0, 1, 2, 3, 4}, []string{"req-a", "", "req-a", "req-b", "req-b"})
+
+	want := []requestGroup{
+		{requestID: "req-a", events: []int{0, 2}},
+		{events: []int{1}},
+		{requestID: "req-b", events: []int{3, 4}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("groupByRequestID() = %+v, want %+v", groups, want)
+	}
+}
+
+func TestScanGroupsEventsSharingARequestID(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "GenerateDataKey", "alice", 1, map[string]interface{}{"requestID": "req-1"}),
+		newEvent("2", "Encrypt", "bob", 2, map[string]interface{}{"requestID": "req-2"}),
+		newEvent("3", "Decrypt", "alice", 3, map[string]interface{}{"requestID": "req-1"}),
+	}}}
+
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{GroupByRequest: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := strings.Index(out, "Request ID: req-1 (2 events)")
+	second := strings.Index(out, "Request ID: req-2 (1 events)")
+	if first < 0 || second < 0 {
+		t.Fatalf("missing request groups in output:\n%s", out)
+	}
+	// Both req-1 events print under its header, before req-2's
+	group := out[first:second]
+	if !strings.Contains(group, "GenerateDataKey") || !strings.Contains(group, "Decrypt") {
+		t.Errorf("req-1 group doesn't hold both of its events:\n%s", group)
+	}
+}
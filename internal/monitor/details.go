@@ -0,0 +1,32 @@
+// internal/monitor/details.go
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// decodeDetails parses a CloudTrailEvent payload into details. Some trail
+// exports wrap the payload as a JSON string holding escaped JSON, so a string
+// result is unmarshalled a second time.
+func decodeDetails(raw string, details *map[string]interface{}) error {
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		return err
+	}
+	if inner, ok := decoded.(string); ok {
+		if err := json.Unmarshal([]byte(inner), &decoded); err != nil {
+			return fmt.Errorf("failed to decode double-encoded event: %v", err)
+		}
+	}
+
+	switch value := decoded.(type) {
+	case map[string]interface{}:
+		*details = value
+	case nil:
+		*details = nil
+	default:
+		return fmt.Errorf("unexpected event payload of type %T", decoded)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// internal/monitor/details_test.go
+package monitor
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+const decryptBody = `{"eventName":"Decrypt","requestParameters":{"keyId":"key-1","encryptionAlgorithm":"SYMMETRIC_DEFAULT"}}`
+
+// doubleEncoded wraps body as a JSON string, as some trail exports do
+func doubleEncoded(body string) string {
+	data, err := json.Marshal(body)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
+func TestDecodeDetails(t *testing.T) {
+	for name, raw := range map[string]string{
+		"plain":          decryptBody,
+		"double-encoded": doubleEncoded(decryptBody),
+	} {
+		var details map[string]interface{}
+		if err := decodeDetails(raw, &details); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got := lookupPath(details, "requestParameters.keyId"); got != "key-1" {
+			t.Errorf("%s: requestParameters.keyId = %v, want key-1", name, got)
+		}
+	}
+}
+
+func TestDecodeDetailsRejects(t *testing.T) {
+	for name, raw := range map[string]string{
+		"malformed":               `{"eventName":`,
+		"malformed inner":         doubleEncoded(`{"eventName":`),
+		"array":                   `[1, 2]`,
+		"double-encoded non-json": `"just a string"`,
+	} {
+		var details map[string]interface{}
+		if err := decodeDetails(raw, &details); err == nil {
+			t.Errorf("%s: decodeDetails(%s) = %v, want an error", name, raw, details)
+		}
+	}
+}
+
+func TestDoubleEncodedParametersRender(t *testing.T) {
+	event := newEvent("1", "Decrypt", "alice", 1, nil)
+	event.CloudTrailEvent = sdkaws.String(doubleEncoded(decryptBody))
+	out, err := scan(t, &fakeTrail{pages: [][]types.Event{{event}}}, FilterOptions{}, OutputOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Request Parameters:") || !strings.Contains(out, "keyId: key-1") {
+		t.Errorf("double-encoded parameters weren't rendered:\n%s", out)
+	}
+}
@@ -0,0 +1,264 @@
+// internal/monitor/digest.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/classify"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+)
+
+// Supported digest report formats
+const (
+	DigestFormatText     = "text"
+	DigestFormatMarkdown = "markdown"
+)
+
+// Sensitive actions reported as notable when no classification file is given
+var defaultNotableEvents = map[string]bool{
+	"ScheduleKeyDeletion":       true,
+	"CancelKeyDeletion":         true,
+	"DisableKey":                true,
+	"DisableKeyRotation":        true,
+	"PutKeyPolicy":              true,
+	"CreateGrant":               true,
+	"RevokeGrant":               true,
+	"DeleteAlias":               true,
+	"UpdateAlias":               true,
+	"ImportKeyMaterial":         true,
+	"DeleteImportedKeyMaterial": true,
+}
+
+// ValidateDigestFormat checks the digest report format option
+func ValidateDigestFormat(format string) error {
+	switch format {
+	case DigestFormatText, DigestFormatMarkdown:
+		return nil
+	}
+	return fmt.Errorf("invalid digest format %q: use %s or %s", format, DigestFormatText, DigestFormatMarkdown)
+}
+
+// DigestOptions selects the events summarized in a digest
+type DigestOptions struct {
+	Start       time.Time
+	End         time.Time
+	EventSource string // e.g. kms.amazonaws.com; empty covers every service
+	Top         int    // entries in each ranking
+	Classifier  *classify.Classifier
+}
+
+// DigestCount is one row of a ranking
+type DigestCount struct {
+	Name  string
+	Count int
+}
+
+// NotableEvent is a sensitive action called out individually in the digest
+type NotableEvent struct {
+	Time      time.Time
+	EventName string
+	User      string
+	Severity  string // empty without a classification file
+	ErrorCode string
+}
+
+// Digest summarizes a window of events for a scheduled report
+type Digest struct {
+	Start       time.Time
+	End         time.Time
+	EventSource string
+	Total       int
+	Errors      int
+	TopEvents   []DigestCount
+	TopUsers    []DigestCount
+	TopErrors   []DigestCount
+	Notable     []NotableEvent
+
+	classifier *classify.Classifier
+	events     map[string]int
+	users      map[string]int
+	errorCodes map[string]int
+}
+
+func newDigest(options DigestOptions) *Digest {
+	return &Digest{
+		Start:       options.Start,
+		End:         options.End,
+		EventSource: options.EventSource,
+		classifier:  options.Classifier,
+		events:      make(map[string]int),
+		users:       make(map[string]int),
+		errorCodes:  make(map[string]int),
+	}
+}
+
+// BuildDigest looks up every event in the window and summarizes it
+func BuildDigest(ctx context.Context, client *aws.AWSClient, options DigestOptions) (*Digest, error) {
+	input := &cloudtrail.LookupEventsInput{
+		StartTime: &options.Start,
+		EndTime:   &options.End,
+	}
+	if options.EventSource != "" {
+		input.LookupAttributes = []types.LookupAttribute{{
+			AttributeKey:   types.LookupAttributeKeyEventSource,
+			AttributeValue: &options.EventSource,
+		}}
+	}
+
+	digest := newDigest(options)
+	paginator := newEventPager(client.CloudTrail, input, "")
+	for paginator.HasMorePages() {
+		output, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, lookupError(err)
+		}
+		for _, event := range output.Events {
+			var details map[string]interface{}
+			if event.CloudTrailEvent != nil {
+				decodeDetails(*event.CloudTrailEvent, &details)
+			}
+			digest.add(event, details)
+		}
+	}
+
+	digest.rank(options.Top)
+	return digest, nil
+}
+
+func (d *Digest) add(event types.Event, details map[string]interface{}) {
+	if missing := missingFields(event); len(missing) > 0 {
+		event = withPlaceholders(event)
+	}
+	eventName := *event.EventName
+	user := SafeString(event.Username)
+
+	d.Total++
+	d.events[eventName]++
+	d.users[user]++
+
+	errorCode, _ := details["errorCode"].(string)
+	if errorCode != "" {
+		d.Errors++
+		d.errorCodes[errorCode]++
+	}
+
+	notable := NotableEvent{
+		Time:      *event.EventTime,
+		EventName: eventName,
+		User:      user,
+		ErrorCode: errorCode,
+	}
+	if d.classifier != nil {
+		classification, ok := d.classifier.Classify(eventName)
+		if !ok || classification.Severity < classify.SeverityHigh {
+			return
+		}
+		notable.Severity = classification.Severity.String()
+	} else if !defaultNotableEvents[eventName] {
+		return
+	}
+	d.Notable = append(d.Notable, notable)
+}
+
+// rank fills in the top-N rankings and orders notable events by time
+func (d *Digest) rank(top int) {
+	d.TopEvents = topCounts(d.events, top)
+	d.TopUsers = topCounts(d.users, top)
+	d.TopErrors = topCounts(d.errorCodes, top)
+	sort.SliceStable(d.Notable, func(i, j int) bool {
+		return d.Notable[i].Time.Before(d.Notable[j].Time)
+	})
+}
+
+// topCounts returns the n highest counts, ties broken by name
+func topCounts(counts map[string]int, n int) []DigestCount {
+	results := make([]DigestCount, 0, len(counts))
+	for name, count := range counts {
+		results = append(results, DigestCount{Name: name, Count: count})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count != results[j].Count {
+			return results[i].Count > results[j].Count
+		}
+		return results[i].Name < results[j].Name
+	})
+	if n > 0 && len(results) > n {
+		results = results[:n]
+	}
+	return results
+}
+
+// Write renders the digest as plain text or markdown
+func (d *Digest) Write(out io.Writer, format string) error {
+	markdown := format == DigestFormatMarkdown
+	source := d.EventSource
+	if source == "" {
+		source = "all services"
+	}
+
+	heading := func(title string) {
+		if markdown {
+			fmt.Fprintf(out, "\n## %s\n\n", title)
+		} else {
+			fmt.Fprintf(out, "\n%s\n%s\n", title, strings.Repeat("-", len(title)))
+		}
+	}
+	bullet := "  - "
+	if markdown {
+		bullet = "- "
+	}
+
+	title := fmt.Sprintf("CloudTrail digest for %s", d.Start.Format("2006-01-02"))
+	if markdown {
+		fmt.Fprintf(out, "# %s\n\n", title)
+	} else {
+		fmt.Fprintf(out, "%s\n%s\n\n", title, strings.Repeat("=", len(title)))
+	}
+	fmt.Fprintf(out, "Window: %s to %s (%s)\n", timeutil.FormatTime(d.Start), timeutil.FormatTime(d.End), timeutil.Location())
+	if markdown {
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "Source: %s\n", source)
+	if markdown {
+		fmt.Fprintln(out)
+	}
+	fmt.Fprintf(out, "Events: %d total, %d errors\n", d.Total, d.Errors)
+
+	writeCounts := func(title string, counts []DigestCount) {
+		heading(title)
+		if len(counts) == 0 {
+			fmt.Fprintf(out, "%sNone\n", bullet)
+			return
+		}
+		for _, count := range counts {
+			fmt.Fprintf(out, "%s%s: %d\n", bullet, count.Name, count.Count)
+		}
+	}
+	writeCounts("Top events", d.TopEvents)
+	writeCounts("Top users", d.TopUsers)
+	writeCounts("Error counts", d.TopErrors)
+
+	heading("Notable actions")
+	if len(d.Notable) == 0 {
+		fmt.Fprintf(out, "%sNone\n", bullet)
+	}
+	for _, notable := range d.Notable {
+		line := fmt.Sprintf("%s %s by %s", notable.Time.In(timeutil.Location()).Format("15:04:05"), notable.EventName, notable.User)
+		if notable.Severity != "" {
+			line += fmt.Sprintf(" [%s]", notable.Severity)
+		}
+		if notable.ErrorCode != "" {
+			line += fmt.Sprintf(" (failed: %s)", notable.ErrorCode)
+		}
+		fmt.Fprintf(out, "%s%s\n", bullet, line)
+	}
+	return nil
+}
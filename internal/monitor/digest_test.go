@@ -0,0 +1,99 @@
+// internal/monitor/digest_test.go
+package monitor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// sampleDay builds a digest over a day of KMS events
+func sampleDay(options DigestOptions) *Digest {
+	options.Start, options.End = testStart, testStart.Add(24*time.Hour)
+	digest := newDigest(options)
+	denied := map[string]interface{}{"errorCode": "AccessDenied"}
+	for _, event := range []struct {
+		event   types.Event
+		details map[string]interface{}
+	}{
+		{newEvent("1", "Decrypt", "alice", 60, nil), nil},
+		{newEvent("2", "Decrypt", "alice", 61, nil), nil},
+		{newEvent("3", "Decrypt", "bob", 62, nil), denied},
+		{newEvent("4", "Encrypt", "bob", 63, nil), nil},
+		{newEvent("5", "ScheduleKeyDeletion", "mallory", 600, nil), denied},
+		{newEvent("6", "DisableKey", "mallory", 120, nil), nil},
+	} {
+		digest.add(event.event, event.details)
+	}
+	digest.rank(options.Top)
+	return digest
+}
+
+func TestDigestText(t *testing.T) {
+	var out bytes.Buffer
+	if err := sampleDay(DigestOptions{EventSource: "kms.amazonaws.com", Top: 2}).Write(&out, DigestFormatText); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `CloudTrail digest for 2024-01-15
+================================
+
+Window: 2024-01-15 00:00:00 to 2024-01-16 00:00:00 (UTC)
+Source: kms.amazonaws.com
+Events: 6 total, 2 errors
+
+Top events
+----------
+  - Decrypt: 3
+  - DisableKey: 1
+
+Top users
+---------
+  - alice: 2
+  - bob: 2
+
+Error counts
+------------
+  - AccessDenied: 2
+
+Notable actions
+---------------
+  - 02:00:00 DisableKey by mallory
+  - 10:00:00 ScheduleKeyDeletion by mallory (failed: AccessDenied)
+`
+	if got := out.String(); got != want {
+		t.Errorf("digest =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDigestMarkdown(t *testing.T) {
+	var out bytes.Buffer
+	if err := sampleDay(DigestOptions{}).Write(&out, DigestFormatMarkdown); err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"# CloudTrail digest for 2024-01-15\n",
+		"Source: all services\n",
+		"\n## Top events\n\n- Decrypt: 3\n",
+		"\n## Top users\n\n- alice: 2\n- bob: 2\n- mallory: 2\n",
+		"\n## Notable actions\n\n- 02:00:00 DisableKey by mallory\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("markdown digest is missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestDigestEmptyDay(t *testing.T) {
+	digest := newDigest(DigestOptions{Start: testStart, End: testStart.Add(24 * time.Hour)})
+	digest.rank(5)
+	var out bytes.Buffer
+	digest.Write(&out, DigestFormatText)
+	if got := strings.Count(out.String(), "  - None\n"); got != 4 {
+		t.Errorf("empty digest has %d empty sections, want 4:\n%s", got, out.String())
+	}
+}
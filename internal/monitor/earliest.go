@@ -0,0 +1,89 @@
+// internal/monitor/earliest.go
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// EventHistoryRetention is how long CloudTrail event history keeps events
+const EventHistoryRetention = 90 * 24 * time.Hour
+
+// EarliestOptions controls the search for the oldest available event
+type EarliestOptions struct {
+	EventSource string        // e.g. kms.amazonaws.com; empty covers every service
+	Precision   time.Duration // stop once the estimate is narrower than this
+}
+
+// Earliest brackets the oldest event in the account's event history: there
+// are no events before After, and at least one at or before Before.
+type Earliest struct {
+	After  time.Time
+	Before time.Time
+	Found  bool // false when the history has no events at all
+	Probes int  // LookupEvents searches made
+}
+
+// FindEarliest binary searches LookupEvents for the oldest available event.
+// Each probe asks whether any event exists between the retention floor and a
+// candidate time, halving the window until it is within options.Precision.
+func FindEarliest(ctx context.Context, client cloudtrail.LookupEventsAPIClient, now time.Time, options EarliestOptions) (Earliest, error) {
+	precision := options.Precision
+	if precision <= 0 {
+		precision = time.Hour
+	}
+	// Leave a day of slack in case retention runs a little long
+	floor := now.Add(-EventHistoryRetention - 24*time.Hour)
+
+	result := Earliest{After: floor, Before: now}
+	hasEvents := func(end time.Time) (bool, error) {
+		result.Probes++
+		input := &cloudtrail.LookupEventsInput{
+			StartTime:  aws.Time(floor),
+			EndTime:    aws.Time(end),
+			MaxResults: aws.Int32(1),
+		}
+		if options.EventSource != "" {
+			input.LookupAttributes = []types.LookupAttribute{{
+				AttributeKey:   types.LookupAttributeKeyEventSource,
+				AttributeValue: aws.String(options.EventSource),
+			}}
+		}
+		// A page can come back empty with a token to continue from
+		paginator := newEventPager(client, input, "")
+		for paginator.HasMorePages() {
+			output, err := paginator.NextPage(ctx)
+			if err != nil {
+				return false, lookupError(err)
+			}
+			if len(output.Events) > 0 {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	found, err := hasEvents(now)
+	if err != nil || !found {
+		return result, err
+	}
+	result.Found = true
+
+	for result.Before.Sub(result.After) > precision {
+		mid := result.After.Add(result.Before.Sub(result.After) / 2)
+		found, err := hasEvents(mid)
+		if err != nil {
+			return result, err
+		}
+		if found {
+			result.Before = mid
+		} else {
+			result.After = mid
+		}
+	}
+	return result, nil
+}
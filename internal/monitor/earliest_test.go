@@ -0,0 +1,80 @@
+// internal/monitor/earliest_test.go
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// historySince answers lookups as if the oldest retained event were at oldest.
+// The first page of each search comes back empty with a token to continue.
+func historySince(oldest time.Time) lookupFunc {
+	return func(ctx context.Context, input *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error) {
+		if input.NextToken == nil {
+			token := "more"
+			return &cloudtrail.LookupEventsOutput{NextToken: &token}, nil
+		}
+		if input.EndTime.Before(oldest) {
+			return &cloudtrail.LookupEventsOutput{}, nil
+		}
+		return &cloudtrail.LookupEventsOutput{Events: []types.Event{newEvent("1", "Decrypt", "alice", 0, nil)}}, nil
+	}
+}
+
+func TestFindEarliest(t *testing.T) {
+	now := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	oldest := now.Add(-30*24*time.Hour - 7*time.Hour - 20*time.Minute)
+
+	earliest, err := FindEarliest(context.Background(), historySince(oldest), now, EarliestOptions{Precision: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !earliest.Found {
+		t.Fatal("no events found")
+	}
+	if earliest.After.After(oldest) || earliest.Before.Before(oldest) {
+		t.Errorf("estimate %s - %s doesn't bracket %s", earliest.After, earliest.Before, oldest)
+	}
+	if width := earliest.Before.Sub(earliest.After); width > time.Minute {
+		t.Errorf("estimate is %s wide, want within the 1m precision", width)
+	}
+	// 91 days narrowed to a minute takes 17 halvings, after the first probe
+	if earliest.Probes != 18 {
+		t.Errorf("made %d probes, want 18", earliest.Probes)
+	}
+}
+
+func TestFindEarliestEmptyHistory(t *testing.T) {
+	now := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	earliest, err := FindEarliest(context.Background(), historySince(now.Add(time.Hour)), now, EarliestOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if earliest.Found || earliest.Probes != 1 {
+		t.Errorf("FindEarliest = %+v, want nothing found after one probe", earliest)
+	}
+}
+
+func TestFindEarliestSource(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{}}}
+	if _, err := FindEarliest(context.Background(), trail, testStart, EarliestOptions{EventSource: "kms.amazonaws.com"}); err != nil {
+		t.Fatal(err)
+	}
+	sent := trail.inputs[0].LookupAttributes
+	if len(sent) != 1 || sent[0].AttributeKey != types.LookupAttributeKeyEventSource || *sent[0].AttributeValue != "kms.amazonaws.com" {
+		t.Errorf("LookupAttributes = %+v, want EventSource=kms.amazonaws.com", sent)
+	}
+}
+
+func TestFindEarliestError(t *testing.T) {
+	denied := errors.New("AccessDenied")
+	trail := &fakeTrail{pages: [][]types.Event{{}}, errs: []error{denied}}
+	if _, err := FindEarliest(context.Background(), trail, testStart, EarliestOptions{}); err == nil {
+		t.Error("FindEarliest ignored a failed lookup")
+	}
+}
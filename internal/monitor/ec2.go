@@ -0,0 +1,51 @@
+// internal/monitor/ec2.go
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+const EC2EventSource = "ec2.amazonaws.com"
+
+func NewEC2Monitor(client *aws.AWSClient, outputDir string, exportOptions *writer.ExportOptions, outputOptions *OutputOptions) *Monitor {
+	return newMonitor(client, outputDir, "ec2", exportOptions, outputOptions)
+}
+
+// MonitorEC2Events reports EC2 API calls matching filters, optionally
+// narrowed to one instance with filters.InstanceID
+func (m *Monitor) MonitorEC2Events(ctx context.Context, filters FilterOptions, start, end time.Time) error {
+	filters.EventSource = EC2EventSource
+	return m.monitorEvents(ctx, filters, start, end)
+}
+
+// hasResource reports whether the event names the resource of the given type
+func hasResource(event types.Event, resourceType, name string) bool {
+	for _, resource := range event.Resources {
+		if SafeString(resource.ResourceType) == resourceType && SafeString(resource.ResourceName) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// requestInstanceID reports whether the request targeted the instance, either
+// as requestParameters.instanceId or in an instancesSet (e.g. StopInstances)
+func requestInstanceID(details map[string]interface{}, instanceID string) bool {
+	if id, _ := lookupPath(details, "requestParameters.instanceId").(string); id == instanceID {
+		return true
+	}
+	items, _ := lookupPath(details, "requestParameters.instancesSet.items").([]interface{})
+	for _, item := range items {
+		if fields, ok := item.(map[string]interface{}); ok {
+			if id, _ := fields["instanceId"].(string); id == instanceID {
+				return true
+			}
+		}
+	}
+	return false
+}
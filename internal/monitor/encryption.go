@@ -0,0 +1,65 @@
+// internal/monitor/encryption.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// Cryptographic operations expected to carry an encryptionContext
+var encryptionContextEvents = map[string]bool{
+	"Encrypt":                             true,
+	"Decrypt":                             true,
+	"GenerateDataKey":                     true,
+	"GenerateDataKeyWithoutPlaintext":     true,
+	"GenerateDataKeyPair":                 true,
+	"GenerateDataKeyPairWithoutPlaintext": true,
+}
+
+// missingEncryptionContext reports whether a KMS cryptographic call was made
+// without an encryptionContext, which may indicate non-compliant usage
+func missingEncryptionContext(event types.Event, details map[string]interface{}) bool {
+	if event.EventName == nil || !encryptionContextEvents[*event.EventName] {
+		return false
+	}
+	context, _ := lookupPath(details, "requestParameters.encryptionContext").(map[string]interface{})
+	return len(context) == 0
+}
+
+// contextViolations counts calls missing an encryption context per principal and event
+type contextViolations struct {
+	counts map[string]int // "user: EventName"
+	total  int
+}
+
+func newContextViolations() *contextViolations {
+	return &contextViolations{counts: make(map[string]int)}
+}
+
+func (c *contextViolations) add(event types.Event) {
+	c.counts[fmt.Sprintf("%s: %s", SafeString(event.Username), SafeString(event.EventName))]++
+	c.total++
+}
+
+// report prints the non-compliant calls and reports whether there were any
+func (c *contextViolations) report(out io.Writer) bool {
+	if c.total == 0 {
+		return false
+	}
+
+	keys := make([]string, 0, len(c.counts))
+	for key := range c.counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintln(out, theme.Error(fmt.Sprintf("\nMissing encryption context: %d calls made without one:", c.total)))
+	for _, key := range keys {
+		fmt.Fprintf(out, "  - %s: %d events\n", key, c.counts[key])
+	}
+	return true
+}
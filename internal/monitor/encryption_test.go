@@ -0,0 +1,61 @@
+// internal/monitor/encryption_test.go
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// cryptoCall is a KMS call with the given request parameters
+func cryptoCall(id, name, user string, params map[string]interface{}) types.Event {
+	return newEvent(id, name, user, 0, map[string]interface{}{"requestParameters": params})
+}
+
+func TestMissingEncryptionContext(t *testing.T) {
+	withContext := map[string]interface{}{"keyId": "key-1", "encryptionContext": map[string]interface{}{"app": "billing"}}
+	emptyContext := map[string]interface{}{"keyId": "key-1", "encryptionContext": map[string]interface{}{}}
+	noContext := map[string]interface{}{"keyId": "key-1"}
+
+	tests := []struct {
+		name   string
+		event  string
+		params map[string]interface{}
+		want   bool
+	}{
+		{"decrypt with context", "Decrypt", withContext, false},
+		{"decrypt without context", "Decrypt", noContext, true},
+		{"data key without context", "GenerateDataKey", noContext, true},
+		{"empty context", "Encrypt", emptyContext, true},
+		{"not a cryptographic call", "DescribeKey", noContext, false},
+	}
+	for _, tc := range tests {
+		event := cryptoCall("1", tc.event, "alice", tc.params)
+		details := map[string]interface{}{"requestParameters": tc.params}
+		if got := missingEncryptionContext(event, details); got != tc.want {
+			t.Errorf("%s: missing = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRequireEncryptionContextFlags(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{
+		cryptoCall("1", "Decrypt", "alice", map[string]interface{}{"encryptionContext": map[string]interface{}{"app": "billing"}}),
+		cryptoCall("2", "Decrypt", "bob", map[string]interface{}{"keyId": "key-1"}),
+		cryptoCall("3", "GenerateDataKey", "bob", map[string]interface{}{"keyId": "key-1"}),
+		cryptoCall("4", "DescribeKey", "carol", map[string]interface{}{"keyId": "key-1"}),
+	}}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{RequireEncryptionContext: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n := strings.Count(out, "Compliance: no encryptionContext in request"); n != 2 {
+		t.Errorf("flagged %d events, want bob's 2:\n%s", n, out)
+	}
+	want := "Missing encryption context: 2 calls made without one:\n  - bob: Decrypt: 1 events\n  - bob: GenerateDataKey: 1 events\n"
+	if !strings.Contains(out, want) {
+		t.Errorf("summary missing:\n%s", out)
+	}
+}
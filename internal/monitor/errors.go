@@ -0,0 +1,50 @@
+// internal/monitor/errors.go
+package monitor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/aws/smithy-go"
+	"github.com/dhairya13703/cloudtrail-logs/internal/retry"
+)
+
+// Minimal policy that lets the tool look up events
+const lookupEventsPolicy = `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Action": "cloudtrail:LookupEvents",
+      "Resource": "*"
+    }
+  ]
+}`
+
+// isAccessDenied reports whether err is an authorization failure from AWS
+func isAccessDenied(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "AccessDenied", "AccessDeniedException", "UnauthorizedOperation", "UnauthorizedException":
+		return true
+	}
+	return false
+}
+
+// lookupError explains a failed LookupEvents call. A permission failure gets an
+// actionable message instead of the raw SDK error.
+func lookupError(err error) error {
+	if isAccessDenied(err) {
+		return fmt.Errorf("access denied looking up events: the credentials in use are missing the "+
+			"cloudtrail:LookupEvents permission.\n\nAttach a policy like the following to the user or role:\n%s\n\nOriginal error: %v",
+			lookupEventsPolicy, err)
+	}
+	if retry.Throttled(err) {
+		return fmt.Errorf("error looking up events: still throttled by CloudTrail after retrying: %v\n\n"+
+			"Raise --max-attempts or --throttle-resumes, or narrow the time range", err)
+	}
+	return fmt.Errorf("error looking up events: %v", err)
+}
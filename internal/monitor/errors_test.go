@@ -0,0 +1,63 @@
+// internal/monitor/errors_test.go
+package monitor
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestAccessDeniedOnFirstPage(t *testing.T) {
+	denied := &smithy.GenericAPIError{
+		Code:    "AccessDeniedException",
+		Message: "User: arn:aws:iam::123456789012:user/alice is not authorized to perform: cloudtrail:LookupEvents",
+	}
+	trail := &fakeTrail{
+		pages: [][]types.Event{{newEvent("1", "Decrypt", "alice", 1, nil)}},
+		errs:  []error{denied},
+	}
+
+	_, err := scan(t, trail, FilterOptions{}, OutputOptions{}, nil)
+	if err == nil {
+		t.Fatal("scan succeeded despite AccessDenied")
+	}
+	for _, want := range []string{
+		"missing the cloudtrail:LookupEvents permission",
+		`"Action": "cloudtrail:LookupEvents"`,
+		"Original error:",
+		"is not authorized to perform",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error doesn't mention %q:\n%v", want, err)
+		}
+	}
+	if trail.calls != 1 {
+		t.Errorf("made %d calls, want AccessDenied to fail without retrying", trail.calls)
+	}
+}
+
+func TestIsAccessDenied(t *testing.T) {
+	for _, code := range []string{"AccessDenied", "AccessDeniedException", "UnauthorizedOperation", "UnauthorizedException"} {
+		if !isAccessDenied(&smithy.GenericAPIError{Code: code}) {
+			t.Errorf("isAccessDenied(%s) = false", code)
+		}
+	}
+	for _, err := range []error{
+		&smithy.GenericAPIError{Code: "InvalidTimeRangeException"},
+		errors.New("AccessDenied"), // not an API error
+	} {
+		if isAccessDenied(err) {
+			t.Errorf("isAccessDenied(%v) = true", err)
+		}
+	}
+}
+
+func TestLookupErrorOtherFailures(t *testing.T) {
+	err := lookupError(&smithy.GenericAPIError{Code: "InvalidTimeRangeException", Message: "bad range"})
+	if !strings.HasPrefix(err.Error(), "error looking up events:") || strings.Contains(err.Error(), "policy") {
+		t.Errorf("lookupError = %v, want the plain error", err)
+	}
+}
@@ -0,0 +1,56 @@
+// internal/monitor/eventnames.go
+package monitor
+
+import "strings"
+
+// eventNames splits an --event filter into its comma-separated names
+func eventNames(filter string) []string {
+	var names []string
+	for _, name := range strings.Split(filter, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// eventExcluded reports whether name is one of the excluded names, ignoring case
+func eventExcluded(name *string, excluded []string) bool {
+	if name == nil {
+		return false
+	}
+	for _, skip := range excluded {
+		if strings.EqualFold(*name, strings.TrimSpace(skip)) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventNameMatches reports whether name contains any of the filter names,
+// ignoring case
+func eventNameMatches(name *string, names []string) bool {
+	if name == nil {
+		return false
+	}
+	lower := strings.ToLower(*name)
+	for _, want := range names {
+		if strings.Contains(lower, strings.ToLower(want)) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventNameEquals reports whether name is exactly one of the filter names
+func eventNameEquals(name *string, names []string) bool {
+	if name == nil {
+		return false
+	}
+	for _, want := range names {
+		if *name == want {
+			return true
+		}
+	}
+	return false
+}
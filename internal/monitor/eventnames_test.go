@@ -0,0 +1,87 @@
+// internal/monitor/eventnames_test.go
+package monitor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestEventNames(t *testing.T) {
+	if got, want := eventNames(" Decrypt, GenerateDataKey,,Encrypt "), []string{"Decrypt", "GenerateDataKey", "Encrypt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("eventNames = %q, want %q", got, want)
+	}
+}
+
+func TestEventNameFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		exact  bool
+		name   string
+		want   bool
+	}{
+		{"Decrypt", false, "Decrypt", true},
+		{"decrypt", false, "Decrypt", true}, // case-insensitive
+		{"Key", false, "GenerateDataKey", true},
+		{"Decrypt", false, "Encrypt", false},
+		{"Decrypt,GenerateDataKey,Encrypt", false, "Encrypt", true},
+		{"Decrypt,GenerateDataKey,Encrypt", false, "GenerateDataKeyWithoutPlaintext", true},
+		{"Decrypt,GenerateDataKey,Encrypt", false, "ScheduleKeyDeletion", false},
+		{"Decrypt, Encrypt", false, "Encrypt", true}, // spaces after commas
+		{"Decrypt,GenerateDataKey", true, "GenerateDataKey", true},
+		{"Decrypt,GenerateDataKey", true, "GenerateDataKeyWithoutPlaintext", false},
+	}
+	for _, tc := range tests {
+		event := newEvent("1", tc.name, "alice", 0, nil)
+		if got := passes(event, FilterOptions{EventName: tc.filter, Exact: tc.exact}); got != tc.want {
+			t.Errorf("--event %s (exact %v) on %s: got %v, want %v", tc.filter, tc.exact, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExcludeEvents(t *testing.T) {
+	tests := []struct {
+		filters FilterOptions
+		name    string
+		want    bool
+	}{
+		{FilterOptions{ExcludeEvents: []string{"Decrypt"}}, "Decrypt", false},
+		{FilterOptions{ExcludeEvents: []string{"decrypt"}}, "Decrypt", false}, // case-insensitive
+		{FilterOptions{ExcludeEvents: []string{"Decrypt"}}, "Encrypt", true},
+		{FilterOptions{ExcludeEvents: []string{"Decrypt"}}, "DecryptSomething", true}, // whole names only
+		{FilterOptions{ExcludeEvents: []string{"Decrypt", "GenerateDataKey"}}, "GenerateDataKey", false},
+		// Matches --event but is excluded
+		{FilterOptions{EventName: "Decrypt", ExcludeEvents: []string{"Decrypt"}}, "Decrypt", false},
+		{FilterOptions{EventName: "crypt", ExcludeEvents: []string{"Decrypt"}}, "Encrypt", true},
+		{FilterOptions{EventName: "Decrypt,Encrypt", Exact: true, ExcludeEvents: []string{"Encrypt"}}, "Encrypt", false},
+	}
+	for _, tc := range tests {
+		event := newEvent("1", tc.name, "alice", 0, nil)
+		if got := passes(event, tc.filters); got != tc.want {
+			t.Errorf("%+v on %s: got %v, want %v", tc.filters, tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestExcludedEventsDroppedFromScan(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice", 1, nil),
+		newEvent("2", "Encrypt", "alice", 2, nil),
+		newEvent("3", "GenerateDataKey", "alice", 3, nil),
+	}}}
+	out, err := scan(t, trail, FilterOptions{EventName: "crypt,DataKey", ExcludeEvents: []string{"Decrypt", "GenerateDataKey"}}, OutputOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Found 1 matching events") || !strings.Contains(out, "] Encrypt") {
+		t.Errorf("want only Encrypt shown:\n%s", out)
+	}
+	if strings.Contains(out, "] Decrypt") || strings.Contains(out, "] GenerateDataKey") {
+		t.Errorf("an excluded event was shown:\n%s", out)
+	}
+	if !strings.Contains(out, "- Excluding events: Decrypt, GenerateDataKey") {
+		t.Errorf("exclusions not listed with the filters:\n%s", out)
+	}
+}
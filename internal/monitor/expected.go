@@ -0,0 +1,83 @@
+// internal/monitor/expected.go
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// ExpectedUsers is an allowlist of principals, matched case-insensitively
+// against the event's username or its userIdentity ARN
+type ExpectedUsers map[string]bool
+
+// LoadExpectedUsers reads one username or ARN per line. Blank lines and lines
+// starting with # are ignored.
+func LoadExpectedUsers(path string) (ExpectedUsers, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read expected users file: %v", err)
+	}
+	defer f.Close()
+
+	users := make(ExpectedUsers)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		users[strings.ToLower(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expected users file: %v", err)
+	}
+	return users, nil
+}
+
+// expected reports whether the event's principal is on the list
+func (u ExpectedUsers) expected(event types.Event, details map[string]interface{}) bool {
+	if event.Username != nil && u[strings.ToLower(*event.Username)] {
+		return true
+	}
+	arn, _ := lookupPath(details, "userIdentity.arn").(string)
+	return arn != "" && u[strings.ToLower(arn)]
+}
+
+// unexpectedUsers counts matched events per principal missing from the allowlist
+type unexpectedUsers struct {
+	counts map[string]int
+}
+
+func newUnexpectedUsers() *unexpectedUsers {
+	return &unexpectedUsers{counts: make(map[string]int)}
+}
+
+func (u *unexpectedUsers) add(event types.Event) {
+	u.counts[SafeString(event.Username)]++
+}
+
+// report prints the unexpected principals and reports whether there were any
+func (u *unexpectedUsers) report(out io.Writer) bool {
+	if len(u.counts) == 0 {
+		return false
+	}
+
+	users := make([]string, 0, len(u.counts))
+	for user := range u.counts {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	fmt.Fprintln(out, theme.Error(fmt.Sprintf("\nUnexpected: %d principals not in the expected users list:", len(users))))
+	for _, user := range users {
+		fmt.Fprintf(out, "  - %s: %d events\n", user, u.counts[user])
+	}
+	return true
+}
@@ -0,0 +1,69 @@
+// internal/monitor/expected_test.go
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestLoadExpectedUsers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expected.txt")
+	content := "# deploy principals\nAlice\n\n  arn:aws:iam::123456789012:role/Deploy  \n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	users, err := LoadExpectedUsers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || !users["alice"] || !users["arn:aws:iam::123456789012:role/deploy"] {
+		t.Errorf("LoadExpectedUsers = %v", users)
+	}
+
+	if _, err := LoadExpectedUsers(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadExpectedUsers accepted a missing file")
+	}
+}
+
+func TestUnexpectedUsersFlagged(t *testing.T) {
+	deployARN := "arn:aws:iam::123456789012:role/Deploy"
+	trail := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice", 1, nil),
+		newEvent("2", "Decrypt", "mallory", 2, nil),
+		newEvent("3", "Decrypt", "deploy-session", 3, map[string]interface{}{
+			"userIdentity": map[string]interface{}{"arn": deployARN},
+		}),
+		newEvent("4", "Decrypt", "mallory", 4, nil),
+		newEvent("5", "Decrypt", "ALICE", 5, nil), // case-insensitive
+	}}}
+	expected := ExpectedUsers{"alice": true, strings.ToLower(deployARN): true}
+
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{ExpectedUsers: expected}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(out, "(Unexpected)"); n != 2 {
+		t.Errorf("flagged %d events, want mallory's 2:\n%s", n, out)
+	}
+	if !strings.Contains(out, "User: mallory") || strings.Contains(out, "User: alice (Unexpected)") {
+		t.Errorf("flagged the wrong users:\n%s", out)
+	}
+	if !strings.Contains(out, "Unexpected: 1 principals not in the expected users list:\n  - mallory: 2 events\n") {
+		t.Errorf("missing the unexpected users summary:\n%s", out)
+	}
+}
+
+func TestAllExpectedUsersNoSummary(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{newEvent("1", "Decrypt", "alice", 1, nil)}}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{ExpectedUsers: ExpectedUsers{"alice": true}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "Unexpected") {
+		t.Errorf("flagged an expected user:\n%s", out)
+	}
+}
@@ -0,0 +1,53 @@
+// internal/monitor/explain_test.go
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func deniedDecrypt(id, user string) types.Event {
+	return newEvent(id, "Decrypt", user, 1, map[string]interface{}{
+		"errorCode":         "AccessDenied",
+		"requestParameters": map[string]interface{}{"keyId": "arn:aws:kms:us-east-1:123456789012:key/key-1"},
+	})
+}
+
+func TestExplainMultiplePredicates(t *testing.T) {
+	filters := FilterOptions{KeyID: "key-1", UserName: "ali", EventName: "Decrypt,Encrypt", ErrorsOnly: true}
+	results := evaluateFilters(deniedDecrypt("1", "alice"), filters)
+
+	want := "matched: key in request parameters, event name substring, user substring, errors-only"
+	if got := explainMatch(results); got != want {
+		t.Errorf("explainMatch = %q, want %q", got, want)
+	}
+}
+
+func TestExplainStopsAtFailingFilter(t *testing.T) {
+	filters := FilterOptions{KeyID: "key-1", UserName: "bob", ErrorsOnly: true}
+	results := evaluateFilters(deniedDecrypt("1", "alice"), filters)
+
+	// The user filter fails, so errors-only is never evaluated
+	if len(results) != 2 || !results[0].matched || results[1].name != "user substring" || results[1].matched {
+		t.Errorf("results = %+v, want the key matched and the user failed", results)
+	}
+}
+
+func TestExplainWithoutFilters(t *testing.T) {
+	if got := explainMatch(evaluateFilters(deniedDecrypt("1", "alice"), FilterOptions{})); got != "no filters applied" {
+		t.Errorf("explainMatch = %q", got)
+	}
+}
+
+func TestExplainOutput(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{deniedDecrypt("1", "alice")}}}
+	out, err := scan(t, trail, FilterOptions{KeyID: "key-1", ErrorsOnly: true}, OutputOptions{Explain: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "  Explain: matched: key in request parameters, errors-only\n") {
+		t.Errorf("explanation not printed:\n%s", out)
+	}
+}
@@ -0,0 +1,73 @@
+// internal/monitor/failures.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+)
+
+// Failing events listed individually before the summary is truncated
+const maxListedFailures = 20
+
+// failedEvent is a matched event that carried an errorCode
+type failedEvent struct {
+	time      string
+	eventName string
+	user      string
+	errorCode string
+}
+
+// errorEvents collects matched events with an errorCode for CI gating
+type errorEvents struct {
+	events []failedEvent
+	codes  map[string]int
+}
+
+func newErrorEvents() *errorEvents {
+	return &errorEvents{codes: make(map[string]int)}
+}
+
+func (e *errorEvents) add(event types.Event, details map[string]interface{}) {
+	errorCode, _ := details["errorCode"].(string)
+	if errorCode == "" {
+		return
+	}
+	e.codes[errorCode]++
+	e.events = append(e.events, failedEvent{
+		time:      timeutil.FormatTime(*event.EventTime),
+		eventName: SafeString(event.EventName),
+		user:      SafeString(event.Username),
+		errorCode: errorCode,
+	})
+}
+
+// report prints a summary of the failing events and reports whether there were any
+func (e *errorEvents) report(out io.Writer) bool {
+	if len(e.events) == 0 {
+		return false
+	}
+
+	codes := make([]string, 0, len(e.codes))
+	for code := range e.codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	fmt.Fprintln(out, theme.Error(fmt.Sprintf("\nFailing: %d matched events have an errorCode:", len(e.events))))
+	for _, code := range codes {
+		fmt.Fprintf(out, "  - %s: %d events\n", code, e.codes[code])
+	}
+	for i, failed := range e.events {
+		if i == maxListedFailures {
+			fmt.Fprintf(out, "  ... and %d more\n", len(e.events)-maxListedFailures)
+			break
+		}
+		fmt.Fprintf(out, "  [%s] %s by %s: %s\n", failed.time, failed.eventName, failed.user, failed.errorCode)
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+// internal/monitor/failures_test.go
+package monitor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestFailOnErrorEvents(t *testing.T) {
+	denied := map[string]interface{}{"errorCode": "AccessDenied"}
+	failing := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice", 2, nil),
+		newEvent("2", "Decrypt", "bob", 1, denied),
+	}}}
+	out, err := scan(t, failing, FilterOptions{}, OutputOptions{FailOnErrorEvents: true}, nil)
+	if err == nil || !strings.Contains(err.Error(), "1 matched events have an errorCode") {
+		t.Errorf("scan with an errored event = %v, want a failure", err)
+	}
+	for _, want := range []string{"Failing: 1 matched events have an errorCode:", "  - AccessDenied: 1 events", "Decrypt by bob: AccessDenied"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary is missing %q:\n%s", want, out)
+		}
+	}
+
+	passing := &fakeTrail{pages: [][]types.Event{{newEvent("1", "Decrypt", "alice", 2, nil)}}}
+	out, err = scan(t, passing, FilterOptions{}, OutputOptions{FailOnErrorEvents: true}, nil)
+	if err != nil {
+		t.Errorf("scan without errored events = %v, want nil", err)
+	}
+	if strings.Contains(out, "Failing:") {
+		t.Errorf("failure summary printed without errored events:\n%s", out)
+	}
+}
+
+func TestErroredEventsPassWithoutFlag(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{newEvent("1", "Decrypt", "bob", 1, map[string]interface{}{"errorCode": "AccessDenied"})}}}
+	if _, err := scan(t, trail, FilterOptions{}, OutputOptions{}, nil); err != nil {
+		t.Errorf("scan = %v, want errored events to be reported only with FailOnErrorEvents", err)
+	}
+}
+
+func TestErrorEventsReportTruncates(t *testing.T) {
+	failures := newErrorEvents()
+	for i := 0; i < maxListedFailures+5; i++ {
+		failures.add(newEvent("1", "Decrypt", "bob", i, nil), map[string]interface{}{"errorCode": "AccessDenied"})
+	}
+	failures.add(newEvent("2", "Decrypt", "bob", 0, nil), map[string]interface{}{})
+
+	var out bytes.Buffer
+	if !failures.report(&out) {
+		t.Fatal("report found no failures")
+	}
+	if got := strings.Count(out.String(), ": AccessDenied\n"); got != maxListedFailures {
+		t.Errorf("listed %d events, want %d", got, maxListedFailures)
+	}
+	if !strings.Contains(out.String(), "... and 5 more") {
+		t.Errorf("truncation not noted:\n%s", out.String())
+	}
+}
@@ -0,0 +1,136 @@
+// internal/monitor/fakes_test.go
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+// testStart is where every test scan's window begins
+var testStart = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+
+func TestMain(m *testing.M) {
+	theme.SetColorEnabled(false)
+	os.Exit(m.Run())
+}
+
+// newEvent builds a KMS event minute minutes into the test window, with body
+// marshaled as its CloudTrailEvent (none when nil)
+func newEvent(id, name, user string, minute int, body map[string]interface{}) types.Event {
+	when := testStart.Add(time.Duration(minute) * time.Minute)
+	event := types.Event{
+		EventId:     sdkaws.String(id),
+		EventName:   sdkaws.String(name),
+		Username:    sdkaws.String(user),
+		EventSource: sdkaws.String("kms.amazonaws.com"),
+		EventTime:   &when,
+	}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			panic(err)
+		}
+		event.CloudTrailEvent = sdkaws.String(string(data))
+	}
+	return event
+}
+
+// fakeTrail serves LookupEvents from fixed pages, linked by "page-N" tokens.
+// Each call first takes the next entry of errs, failing with it unless nil.
+type fakeTrail struct {
+	mu     sync.Mutex
+	pages  [][]types.Event
+	errs   []error
+	inputs []cloudtrail.LookupEventsInput
+	calls  int
+
+	// onCall runs (without the lock) before each call is answered
+	onCall func(call int)
+}
+
+func (f *fakeTrail) LookupEvents(ctx context.Context, input *cloudtrail.LookupEventsInput, _ ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	f.mu.Lock()
+	f.calls++
+	call := f.calls
+	f.inputs = append(f.inputs, *input)
+	var err error
+	if len(f.errs) > 0 {
+		err, f.errs = f.errs[0], f.errs[1:]
+	}
+	f.mu.Unlock()
+
+	if f.onCall != nil {
+		f.onCall(call)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page := 0
+	if input.NextToken != nil {
+		fmt.Sscanf(*input.NextToken, "page-%d", &page)
+	}
+	output := &cloudtrail.LookupEventsOutput{}
+	if page < len(f.pages) {
+		output.Events = f.pages[page]
+	}
+	if page+1 < len(f.pages) {
+		output.NextToken = sdkaws.String(fmt.Sprintf("page-%d", page+1))
+	}
+	return output, nil
+}
+
+// scan runs a KMS scan of the test window over trail, writing log files
+// under a temporary directory, and returns the console output
+func scan(t *testing.T, trail cloudtrail.LookupEventsAPIClient, filters FilterOptions, output OutputOptions, export *writer.ExportOptions) (string, error) {
+	t.Helper()
+	return scanContext(context.Background(), t, trail, filters, output, export)
+}
+
+func scanContext(ctx context.Context, t *testing.T, trail cloudtrail.LookupEventsAPIClient, filters FilterOptions, output OutputOptions, export *writer.ExportOptions) (string, error) {
+	t.Helper()
+	var console bytes.Buffer
+	if output.Console == nil {
+		output.Console = &console
+	}
+	m := NewKMSMonitor(&aws.AWSClient{Region: "us-east-1", Profile: "test"}, t.TempDir(), export, &output)
+	m.lookupClient = func(string) cloudtrail.LookupEventsAPIClient { return trail }
+	err := m.MonitorKMSEvents(ctx, filters, testStart, testStart.Add(24*time.Hour))
+	return console.String(), err
+}
+
+// captureStdout returns what fn writes to os.Stdout, where the machine-readable
+// modes print regardless of Console
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = saved }()
+
+	captured := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- string(data)
+	}()
+	fn()
+	w.Close()
+	return <-captured
+}
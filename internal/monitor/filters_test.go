@@ -0,0 +1,171 @@
+// internal/monitor/filters_test.go
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/classify"
+)
+
+// passes reports whether event gets through every active filter
+func passes(event types.Event, filters FilterOptions) bool {
+	results := evaluateFilters(event, filters)
+	return len(results) == 0 || results[len(results)-1].matched
+}
+
+// assumedRoleEvent is a call made from a session of the named role
+func assumedRoleEvent(id, role string) types.Event {
+	return newEvent(id, "Decrypt", role+"/session", 0, map[string]interface{}{
+		"userIdentity": map[string]interface{}{
+			"type": "AssumedRole",
+			"sessionContext": map[string]interface{}{
+				"sessionIssuer": map[string]interface{}{"type": "Role", "userName": role},
+			},
+		},
+	})
+}
+
+func TestRoleFilter(t *testing.T) {
+	deploy := assumedRoleEvent("1", "DeployRole")
+	audit := assumedRoleEvent("2", "AuditRole")
+	iamUser := newEvent("3", "Decrypt", "alice", 0, map[string]interface{}{
+		"userIdentity": map[string]interface{}{"type": "IAMUser", "userName": "alice"},
+	})
+
+	tests := []struct {
+		role  string
+		event types.Event
+		want  bool
+	}{
+		{"DeployRole", deploy, true},
+		{"deploy", deploy, true}, // case-insensitive substring
+		{"DeployRole", audit, false},
+		{"Audit", audit, true},
+		{"alice", iamUser, false}, // not an assumed-role session
+	}
+	for _, tc := range tests {
+		if got := passes(tc.event, FilterOptions{Role: tc.role}); got != tc.want {
+			t.Errorf("role %q on %s: got %v, want %v", tc.role, *tc.event.Username, got, tc.want)
+		}
+	}
+}
+
+func TestMinSeverityUsesClassificationFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	rules := "rules:\n  - match: ScheduleKeyDeletion\n    severity: critical\n  - pattern: ^Disable\n    severity: medium\n"
+	if err := os.WriteFile(path, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	classifier, err := classify.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters := FilterOptions{Classifier: classifier, MinSeverity: classify.SeverityHigh}
+	tests := map[string]bool{
+		"ScheduleKeyDeletion": true,
+		"DisableKey":          false, // classified, but below high
+		"Decrypt":             false, // unclassified
+	}
+	for name, want := range tests {
+		if got := passes(newEvent("1", name, "alice", 0, nil), filters); got != want {
+			t.Errorf("%s at severity high: got %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestHasParamFilter(t *testing.T) {
+	withContext := newEvent("1", "Decrypt", "alice", 0, map[string]interface{}{
+		"requestParameters": map[string]interface{}{
+			"keyId":             "key-1",
+			"encryptionContext": map[string]interface{}{"aws:s3:arn": "arn:aws:s3:::bucket"},
+			"grantTokens":       nil, // present, if empty
+		},
+	})
+	noParams := newEvent("2", "Decrypt", "alice", 0, nil)
+
+	tests := []struct {
+		key   string
+		event types.Event
+		want  bool
+	}{
+		{"encryptionContext", withContext, true},
+		{"encryptionContext.aws:s3:arn", withContext, true},
+		{"grantTokens", withContext, true},
+		{"encryptionContext.missing", withContext, false},
+		{"keyId.nested", withContext, false}, // keyId is a scalar
+		{"EncryptionContext", withContext, false},
+		{"encryptionContext", noParams, false},
+	}
+	for _, tc := range tests {
+		if got := passes(tc.event, FilterOptions{HasParam: tc.key}); got != tc.want {
+			t.Errorf("has-param %q on event %s: got %v, want %v", tc.key, *tc.event.EventId, got, tc.want)
+		}
+	}
+}
+
+func TestStatusFiltersNeedAParsedBody(t *testing.T) {
+	succeeded := newEvent("1", "Decrypt", "alice", 0, map[string]interface{}{"eventName": "Decrypt"})
+	failed := newEvent("2", "Decrypt", "alice", 0, map[string]interface{}{"errorCode": "AccessDenied"})
+	bodiless := newEvent("3", "Decrypt", "alice", 0, nil)
+	malformed := newEvent("4", "Decrypt", "alice", 0, nil)
+	malformed.CloudTrailEvent = sdkaws.String(`{"errorCode": "AccessDenied"`)
+
+	tests := []struct {
+		event       types.Event
+		errorsOnly  bool
+		successOnly bool
+	}{
+		{succeeded, false, true},
+		{failed, true, false},
+		{bodiless, false, false},
+		{malformed, false, false},
+	}
+	for _, tc := range tests {
+		id := *tc.event.EventId
+		if got := passes(tc.event, FilterOptions{ErrorsOnly: true}); got != tc.errorsOnly {
+			t.Errorf("event %s under errors-only: got %v, want %v", id, got, tc.errorsOnly)
+		}
+		if got := passes(tc.event, FilterOptions{SuccessOnly: true}); got != tc.successOnly {
+			t.Errorf("event %s under success-only: got %v, want %v", id, got, tc.successOnly)
+		}
+		if !passes(tc.event, FilterOptions{}) {
+			t.Errorf("event %s filtered out with no status filter", id)
+		}
+	}
+}
+
+func TestErrorCodeFilter(t *testing.T) {
+	denied := newEvent("1", "Decrypt", "alice", 0, map[string]interface{}{"errorCode": "AccessDenied"})
+	invalidState := newEvent("2", "Decrypt", "alice", 0, map[string]interface{}{"errorCode": "KMSInvalidStateException"})
+	succeeded := newEvent("3", "Decrypt", "alice", 0, map[string]interface{}{"eventName": "Decrypt"})
+	bodiless := newEvent("4", "Decrypt", "alice", 0, nil)
+
+	tests := []struct {
+		code  string
+		event types.Event
+		want  bool
+	}{
+		{"AccessDenied", denied, true},
+		{"accessdenied", denied, true}, // case-insensitive
+		{"Access", denied, false},      // whole codes only
+		{"AccessDenied", invalidState, false},
+		{"KMSInvalidStateException", invalidState, true},
+		{"AccessDenied", succeeded, false},
+		{"AccessDenied", bodiless, false},
+	}
+	for _, tc := range tests {
+		if got := passes(tc.event, FilterOptions{ErrorCode: tc.code}); got != tc.want {
+			t.Errorf("--error-code %s on event %s: got %v, want %v", tc.code, *tc.event.EventId, got, tc.want)
+		}
+	}
+
+	// An error code narrows --errors-only rather than widening it
+	if !passes(denied, FilterOptions{ErrorCode: "AccessDenied", ErrorsOnly: true}) || passes(invalidState, FilterOptions{ErrorCode: "AccessDenied", ErrorsOnly: true}) {
+		t.Error("--error-code with --errors-only didn't match just that code")
+	}
+}
@@ -0,0 +1,56 @@
+// internal/monitor/histogram.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// Width of the longest bar in the chart
+const histogramWidth = 50
+
+// histogram counts matched events per time bucket across the scanned window
+type histogram struct {
+	bucket time.Duration
+	start  time.Time
+	end    time.Time
+	counts map[time.Time]int
+}
+
+func newHistogram(bucket time.Duration, start, end time.Time) *histogram {
+	return &histogram{bucket: bucket, start: start, end: end, counts: make(map[time.Time]int)}
+}
+
+func (h *histogram) add(eventTime time.Time) {
+	h.counts[eventTime.Truncate(h.bucket)]++
+}
+
+// render prints one bar per bucket, including empty ones, scaled to the busiest
+func (h *histogram) render(out io.Writer) {
+	highest := 0
+	for _, count := range h.counts {
+		highest = max(highest, count)
+	}
+
+	layout := "2006-01-02 15:04"
+	if h.bucket%(24*time.Hour) == 0 {
+		layout = "2006-01-02"
+	}
+
+	fmt.Fprintln(out, theme.Info(fmt.Sprintf("\nEvents per %s:", h.bucket)))
+	for bucket := h.start.Truncate(h.bucket); !bucket.After(h.end); bucket = bucket.Add(h.bucket) {
+		count := h.counts[bucket]
+		bar := ""
+		if highest > 0 {
+			bar = strings.Repeat("#", count*histogramWidth/highest)
+			if count > 0 && bar == "" {
+				bar = "#"
+			}
+		}
+		fmt.Fprintf(out, "  %s | %-*s %d\n", bucket.In(h.start.Location()).Format(layout), histogramWidth, bar, count)
+	}
+}
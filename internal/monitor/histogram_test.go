@@ -0,0 +1,86 @@
+// internal/monitor/histogram_test.go
+package monitor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	chart := newHistogram(time.Hour, testStart, testStart.Add(3*time.Hour+30*time.Minute))
+	for _, minute := range []int{10, 50, 59, 65, 3*60 + 20} {
+		chart.add(testStart.Add(time.Duration(minute) * time.Minute))
+	}
+
+	want := map[time.Time]int{
+		testStart:                    3,
+		testStart.Add(time.Hour):     1,
+		testStart.Add(3 * time.Hour): 1,
+	}
+	if len(chart.counts) != len(want) {
+		t.Errorf("counts = %v, want %v", chart.counts, want)
+	}
+	for bucket, count := range want {
+		if chart.counts[bucket] != count {
+			t.Errorf("bucket %s = %d, want %d", bucket.Format("15:04"), chart.counts[bucket], count)
+		}
+	}
+
+	var out bytes.Buffer
+	chart.render(&out)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	wantLines := []string{
+		"",
+		"Events per 1h0m0s:",
+		"  2024-01-15 00:00 | " + strings.Repeat("#", 50) + " 3",
+		"  2024-01-15 01:00 | " + strings.Repeat("#", 16) + strings.Repeat(" ", 34) + " 1",
+		"  2024-01-15 02:00 | " + strings.Repeat(" ", 50) + " 0", // empty buckets are still shown
+		"  2024-01-15 03:00 | " + strings.Repeat("#", 16) + strings.Repeat(" ", 34) + " 1",
+	}
+	if strings.Join(lines, "\n") != strings.Join(wantLines, "\n") {
+		t.Errorf("chart =\n%s\nwant\n%s", out.String(), strings.Join(wantLines, "\n"))
+	}
+}
+
+func TestHistogramDailyBuckets(t *testing.T) {
+	chart := newHistogram(24*time.Hour, testStart, testStart.Add(36*time.Hour))
+	chart.add(testStart.Add(30 * time.Hour))
+
+	var out bytes.Buffer
+	chart.render(&out)
+	if !strings.Contains(out.String(), "  2024-01-15 | ") || !strings.Contains(out.String(), "  2024-01-16 | #") {
+		t.Errorf("daily chart should label buckets by date:\n%s", out.String())
+	}
+}
+
+func TestHistogramSmallCountStillShown(t *testing.T) {
+	chart := newHistogram(time.Hour, testStart, testStart.Add(time.Hour))
+	for i := 0; i < 100; i++ {
+		chart.add(testStart)
+	}
+	chart.add(testStart.Add(time.Hour))
+
+	var out bytes.Buffer
+	chart.render(&out)
+	if !strings.Contains(out.String(), "  2024-01-15 01:00 | #"+strings.Repeat(" ", 49)+" 1") {
+		t.Errorf("a single event next to a busy bucket lost its bar:\n%s", out.String())
+	}
+}
+
+func TestHistogramOutput(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice", 125, nil),
+		newEvent("2", "Decrypt", "alice", 5, nil),
+	}}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{Histogram: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Events per 1h0m0s:") || strings.Count(out, " | ") != 25 {
+		t.Errorf("want an hourly chart over the 24 hour window:\n%s", out)
+	}
+}
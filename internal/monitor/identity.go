@@ -0,0 +1,42 @@
+// internal/monitor/identity.go
+package monitor
+
+import "strings"
+
+// userIdentity.type values that represent a person rather than AWS itself
+var humanIdentityTypes = map[string]bool{
+	"IAMUser":       true,
+	"AssumedRole":   true,
+	"Root":          true,
+	"FederatedUser": true,
+}
+
+// humanPrincipal reports whether an event was made by a person. AWSService
+// callers, calls AWS made on a principal's behalf (invokedBy), and sessions of
+// service-linked roles are all treated as AWS-internal.
+func humanPrincipal(details map[string]interface{}) bool {
+	identityType, _ := lookupPath(details, "userIdentity.type").(string)
+	if !humanIdentityTypes[identityType] {
+		return false
+	}
+	if invokedBy, _ := lookupPath(details, "userIdentity.invokedBy").(string); invokedBy != "" {
+		return false
+	}
+	issuer, _ := lookupPath(details, "userIdentity.sessionContext.sessionIssuer.arn").(string)
+	return !strings.Contains(issuer, ":role/aws-service-role/")
+}
+
+// consoleRequest reports whether an event came from the AWS Management
+// Console: console sign-ins and actions, console-issued session credentials,
+// or a console/sign-in user agent.
+func consoleRequest(details map[string]interface{}) bool {
+	switch eventType, _ := details["eventType"].(string); eventType {
+	case "AwsConsoleSignIn", "AwsConsoleAction":
+		return true
+	}
+	if fromConsole, _ := details["sessionCredentialFromConsole"].(string); fromConsole == "true" {
+		return true
+	}
+	userAgent, _ := details["userAgent"].(string)
+	return strings.Contains(userAgent, "console.amazonaws.com") || strings.Contains(userAgent, "signin.amazonaws.com")
+}
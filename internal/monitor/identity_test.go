@@ -0,0 +1,82 @@
+// internal/monitor/identity_test.go
+package monitor
+
+import "testing"
+
+func identity(fields map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"userIdentity": fields}
+}
+
+func TestHumanPrincipal(t *testing.T) {
+	tests := []struct {
+		name    string
+		details map[string]interface{}
+		want    bool
+	}{
+		{"IAM user", identity(map[string]interface{}{"type": "IAMUser"}), true},
+		{"root", identity(map[string]interface{}{"type": "Root"}), true},
+		{"federated user", identity(map[string]interface{}{"type": "FederatedUser"}), true},
+		{"assumed role", identity(map[string]interface{}{
+			"type":           "AssumedRole",
+			"sessionContext": map[string]interface{}{"sessionIssuer": map[string]interface{}{"arn": "arn:aws:iam::123456789012:role/Deploy"}},
+		}), true},
+		{"AWS service", identity(map[string]interface{}{"type": "AWSService", "invokedBy": "ec2.amazonaws.com"}), false},
+		{"AWS account", identity(map[string]interface{}{"type": "AWSAccount"}), false},
+		{"invoked by a service", identity(map[string]interface{}{"type": "AssumedRole", "invokedBy": "cloudformation.amazonaws.com"}), false},
+		{"service-linked role", identity(map[string]interface{}{
+			"type": "AssumedRole",
+			"sessionContext": map[string]interface{}{"sessionIssuer": map[string]interface{}{
+				"arn": "arn:aws:iam::123456789012:role/aws-service-role/autoscaling.amazonaws.com/AWSServiceRoleForAutoScaling",
+			}},
+		}), false},
+		{"no identity", map[string]interface{}{}, false},
+		{"no details", nil, false},
+	}
+	for _, tc := range tests {
+		if got := humanPrincipal(tc.details); got != tc.want {
+			t.Errorf("%s: humanPrincipal = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHumansOnlyFilter(t *testing.T) {
+	person := newEvent("1", "Decrypt", "alice", 0, identity(map[string]interface{}{"type": "IAMUser"}))
+	service := newEvent("2", "Decrypt", "AWS Internal", 0, identity(map[string]interface{}{"type": "AWSService"}))
+	unparsed := newEvent("3", "Decrypt", "alice", 0, nil)
+
+	if !passes(person, FilterOptions{HumansOnly: true}) {
+		t.Error("an IAM user's event was dropped")
+	}
+	if passes(service, FilterOptions{HumansOnly: true}) {
+		t.Error("an AWS service's event was kept")
+	}
+	if passes(unparsed, FilterOptions{HumansOnly: true}) {
+		t.Error("an event without details was kept")
+	}
+	if !passes(service, FilterOptions{}) {
+		t.Error("an AWS service's event was dropped without HumansOnly")
+	}
+}
+
+func TestConsoleOnlyFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		details map[string]interface{}
+		want    bool
+	}{
+		{"console action", map[string]interface{}{"eventType": "AwsConsoleAction"}, true},
+		{"console sign-in", map[string]interface{}{"eventType": "AwsConsoleSignIn"}, true},
+		{"console session", map[string]interface{}{"eventType": "AwsApiCall", "sessionCredentialFromConsole": "true"}, true},
+		{"console user agent", map[string]interface{}{"eventType": "AwsApiCall", "userAgent": "AWS Internal console.amazonaws.com"}, true},
+		{"sign-in user agent", map[string]interface{}{"userAgent": "signin.amazonaws.com"}, true},
+		{"CLI", map[string]interface{}{"eventType": "AwsApiCall", "userAgent": "aws-cli/2.15.0 Python/3.11"}, false},
+		{"SDK", map[string]interface{}{"eventType": "AwsApiCall", "userAgent": "aws-sdk-go-v2/1.24.0", "sessionCredentialFromConsole": "false"}, false},
+		{"no details", nil, false},
+	}
+	for _, tc := range tests {
+		event := newEvent("1", "Decrypt", "alice", 0, tc.details)
+		if got := passes(event, FilterOptions{ConsoleOnly: true}); got != tc.want {
+			t.Errorf("%s: kept = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// internal/monitor/ids_test.go
+package monitor
+
+import (
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestIDsOnly(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{
+		{newEvent("id-1", "Decrypt", "alice", 3, nil), newEvent("id-2", "Encrypt", "alice", 2, nil)},
+		{newEvent("id-3", "Decrypt", "bob", 1, nil)},
+	}}
+
+	stdout := captureStdout(t, func() {
+		if _, err := scan(t, trail, FilterOptions{EventName: "Decrypt"}, OutputOptions{IDsOnly: true, Console: io.Discard}, nil); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// No banner, summary, or event details; just the matched ids in order
+	if stdout != "id-1\nid-3\n" {
+		t.Errorf("stdout = %q, want only the matched ids", stdout)
+	}
+}
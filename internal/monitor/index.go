@@ -0,0 +1,46 @@
+// internal/monitor/index.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/elastic"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+// indexDocument shapes a matched event for Elasticsearch/OpenSearch. The
+// EventId doubles as the document _id so re-runs don't duplicate events.
+func indexDocument(entry writer.Entry) elastic.Document {
+	event := entry.Event
+	source := map[string]interface{}{
+		"@timestamp":  event.EventTime.UTC().Format(time.RFC3339),
+		"eventId":     SafeString(event.EventId),
+		"eventName":   SafeString(event.EventName),
+		"eventSource": SafeString(event.EventSource),
+		"user":        SafeString(event.Username),
+		"resources":   event.Resources,
+		"details":     entry.Details,
+	}
+	if entry.Index > 0 {
+		source["index"] = entry.Index
+	}
+
+	doc := elastic.Document{Source: source}
+	if event.EventId != nil {
+		doc.ID = *event.EventId
+	}
+	return doc
+}
+
+// flushIndex sends queued documents to the search cluster
+func (m *Monitor) flushIndex(ctx context.Context) {
+	if m.indexer == nil {
+		return
+	}
+	if err := m.indexer.Flush(ctx); err != nil {
+		fmt.Fprintf(m.out, theme.Warning("Warning: Failed to index events: %v\n"), err)
+	}
+}
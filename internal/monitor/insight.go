@@ -0,0 +1,63 @@
+// internal/monitor/insight.go
+package monitor
+
+import "fmt"
+
+// Event type CloudTrail Insights uses for anomaly events
+const insightEventType = "AwsCloudTrailInsight"
+
+// insightSummary holds the Insights-specific fields of an anomaly event
+type insightSummary struct {
+	state       string // Start or End
+	insightType string // ApiCallRateInsight or ApiErrorRateInsight
+	eventSource string
+	eventName   string
+	errorCode   string
+	baseline    float64 // average rate during the baseline period
+	observed    float64 // average rate during the insight period
+	hasRates    bool
+}
+
+func isInsightEvent(details map[string]interface{}) bool {
+	eventType, _ := details["eventType"].(string)
+	return eventType == insightEventType
+}
+
+// parseInsight extracts the insightDetails block of an Insights event
+func parseInsight(details map[string]interface{}) (insightSummary, bool) {
+	if details == nil || !isInsightEvent(details) {
+		return insightSummary{}, false
+	}
+
+	var summary insightSummary
+	summary.state, _ = lookupPath(details, "insightDetails.state").(string)
+	summary.insightType, _ = lookupPath(details, "insightDetails.insightType").(string)
+	summary.eventSource, _ = lookupPath(details, "insightDetails.eventSource").(string)
+	summary.eventName, _ = lookupPath(details, "insightDetails.eventName").(string)
+	summary.errorCode, _ = lookupPath(details, "insightDetails.errorCode").(string)
+
+	baseline, baselineOK := lookupPath(details, "insightDetails.insightContext.statistics.baseline.average").(float64)
+	observed, observedOK := lookupPath(details, "insightDetails.insightContext.statistics.insight.average").(float64)
+	if baselineOK && observedOK {
+		summary.baseline = baseline
+		summary.observed = observed
+		summary.hasRates = true
+	}
+
+	return summary, true
+}
+
+// describe renders the insight for console and log output
+func (s insightSummary) describe() string {
+	text := fmt.Sprintf("%s (%s) on %s %s", s.insightType, s.state, s.eventSource, s.eventName)
+	if s.errorCode != "" {
+		text += fmt.Sprintf(" [%s]", s.errorCode)
+	}
+	if s.hasRates {
+		text += fmt.Sprintf(": baseline %.2f/min, observed %.2f/min", s.baseline, s.observed)
+		if s.baseline > 0 {
+			text += fmt.Sprintf(" (%.1fx)", s.observed/s.baseline)
+		}
+	}
+	return text
+}
@@ -0,0 +1,119 @@
+// internal/monitor/insight_test.go
+package monitor
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// sampleInsight is an ApiCallRateInsight start event as CloudTrail records it
+const sampleInsight = `{
+	"eventVersion": "1.08",
+	"eventTime": "2024-01-15T00:05:00Z",
+	"awsRegion": "us-east-1",
+	"eventID": "insight-1",
+	"eventType": "AwsCloudTrailInsight",
+	"eventCategory": "Insight",
+	"recipientAccountId": "123456789012",
+	"sharedEventID": "shared-1",
+	"insightDetails": {
+		"state": "Start",
+		"eventSource": "kms.amazonaws.com",
+		"eventName": "Decrypt",
+		"insightType": "ApiCallRateInsight",
+		"insightContext": {
+			"statistics": {
+				"baseline": {"average": 0.5},
+				"insight": {"average": 12.25},
+				"insightDuration": 1
+			}
+		}
+	}
+}`
+
+func insightDetails(t *testing.T) map[string]interface{} {
+	t.Helper()
+	var details map[string]interface{}
+	if err := json.Unmarshal([]byte(sampleInsight), &details); err != nil {
+		t.Fatal(err)
+	}
+	return details
+}
+
+func TestParseInsight(t *testing.T) {
+	summary, ok := parseInsight(insightDetails(t))
+	if !ok {
+		t.Fatal("sample insight wasn't recognised")
+	}
+	want := insightSummary{
+		state:       "Start",
+		insightType: "ApiCallRateInsight",
+		eventSource: "kms.amazonaws.com",
+		eventName:   "Decrypt",
+		baseline:    0.5,
+		observed:    12.25,
+		hasRates:    true,
+	}
+	if summary != want {
+		t.Errorf("parseInsight = %+v, want %+v", summary, want)
+	}
+
+	wantText := "ApiCallRateInsight (Start) on kms.amazonaws.com Decrypt: baseline 0.50/min, observed 12.25/min (24.5x)"
+	if got := summary.describe(); got != wantText {
+		t.Errorf("describe = %q, want %q", got, wantText)
+	}
+}
+
+func TestParseInsightWithoutRates(t *testing.T) {
+	details := insightDetails(t)
+	insight := details["insightDetails"].(map[string]interface{})
+	delete(insight, "insightContext")
+	insight["insightType"] = "ApiErrorRateInsight"
+	insight["errorCode"] = "AccessDeniedException"
+
+	summary, ok := parseInsight(details)
+	if !ok || summary.hasRates {
+		t.Fatalf("parseInsight = %+v, %v; want an insight without rates", summary, ok)
+	}
+	if got := summary.describe(); got != "ApiErrorRateInsight (Start) on kms.amazonaws.com Decrypt [AccessDeniedException]" {
+		t.Errorf("describe = %q", got)
+	}
+}
+
+func TestParseInsightRejectsManagementEvents(t *testing.T) {
+	for _, details := range []map[string]interface{}{
+		nil,
+		{"eventType": "AwsApiCall", "eventName": "Decrypt"},
+	} {
+		if _, ok := parseInsight(details); ok {
+			t.Errorf("parseInsight(%v) treated a non-insight event as an insight", details)
+		}
+	}
+}
+
+func TestInsightsOnly(t *testing.T) {
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(sampleInsight), &body); err != nil {
+		t.Fatal(err)
+	}
+	insight := newEvent("insight-1", "Decrypt", "", 5, body)
+	call := newEvent("call-1", "Decrypt", "alice", 6, map[string]interface{}{"eventType": "AwsApiCall"})
+	trail := &fakeTrail{pages: [][]types.Event{{insight, call}}}
+
+	out, err := scan(t, trail, FilterOptions{InsightsOnly: true}, OutputOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trail.inputs[0].EventCategory != types.EventCategoryInsight {
+		t.Errorf("lookup category = %q, want insight", trail.inputs[0].EventCategory)
+	}
+	if !strings.Contains(out, "Found 1 matching events") {
+		t.Errorf("the API call wasn't filtered out:\n%s", out)
+	}
+	if !strings.Contains(out, "Insight: ApiCallRateInsight (Start)") || !strings.Contains(out, "(24.5x)") {
+		t.Errorf("the insight's rates weren't shown:\n%s", out)
+	}
+}
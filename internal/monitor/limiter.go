@@ -0,0 +1,32 @@
+// internal/monitor/limiter.go
+package monitor
+
+import "context"
+
+// scanLimiter bounds how many scans (e.g. one per region) run at once. Each
+// scan holds a slot from acquire until release.
+type scanLimiter chan struct{}
+
+// newScanLimiter allows up to n scans at once, and at least one
+func newScanLimiter(n int) scanLimiter {
+	if n < 1 {
+		n = 1
+	}
+	return make(scanLimiter, n)
+}
+
+// acquire waits for a free slot. It reports false, without taking a slot, if
+// ctx is done first.
+func (l scanLimiter) acquire(ctx context.Context) bool {
+	select {
+	case l <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release frees a slot taken by acquire
+func (l scanLimiter) release() {
+	<-l
+}
@@ -0,0 +1,79 @@
+// internal/monitor/limiter_test.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingScans runs n fake scans through limiter and returns the most that
+// were in flight at once
+func countingScans(limiter scanLimiter, n int) int {
+	var mu sync.Mutex
+	active, peak := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !limiter.acquire(context.Background()) {
+				return
+			}
+			defer limiter.release()
+
+			mu.Lock()
+			active++
+			peak = max(peak, active)
+			mu.Unlock()
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return peak
+}
+
+func TestScanLimiterBoundsConcurrency(t *testing.T) {
+	for _, tc := range []struct {
+		limit, want int
+	}{
+		{limit: 3, want: 3},
+		{limit: 1, want: 1},
+		{limit: 0, want: 1}, // at least one scan runs
+	} {
+		t.Run(fmt.Sprintf("limit %d", tc.limit), func(t *testing.T) {
+			peak := countingScans(newScanLimiter(tc.limit), 10)
+			if peak > tc.want {
+				t.Errorf("%d scans ran at once, want at most %d", peak, tc.want)
+			}
+			if tc.want > 1 && peak < 2 {
+				t.Errorf("scans ran one at a time with a limit of %d", tc.limit)
+			}
+		})
+	}
+}
+
+func TestScanLimiterAcquireCancelled(t *testing.T) {
+	limiter := newScanLimiter(1)
+	if !limiter.acquire(context.Background()) {
+		t.Fatal("acquire failed with a free slot")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if limiter.acquire(ctx) {
+		t.Fatal("acquire took a second slot of one")
+	}
+
+	limiter.release()
+	if !limiter.acquire(context.Background()) {
+		t.Error("the released slot wasn't reusable")
+	}
+}
@@ -0,0 +1,47 @@
+// internal/monitor/lookup.go
+package monitor
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// lookupAttribute picks the filter to push down to LookupEvents. CloudTrail
+// accepts a single attribute and matches it exactly, so only filters that are
+// exact anyway are sent: full key ARNs, topic ARNs, and event sources, plus
+// event and user names under --exact. The most selective one is used and
+// every filter is still re-checked client-side. Instance IDs aren't sent: a
+// ResourceName lookup would drop calls that only name the instance in their
+// request parameters, so the ec2 command pushes down its event source instead.
+func lookupAttribute(filters FilterOptions) *types.LookupAttribute {
+	switch {
+	case filters.KeyID != "" && arn.IsARN(filters.KeyID):
+		return &types.LookupAttribute{
+			AttributeKey:   types.LookupAttributeKeyResourceName,
+			AttributeValue: aws.String(filters.KeyID),
+		}
+	case filters.TopicARN != "":
+		return &types.LookupAttribute{
+			AttributeKey:   types.LookupAttributeKeyResourceName,
+			AttributeValue: aws.String(filters.TopicARN),
+		}
+	// Only a single name can be pushed down
+	case filters.Exact && filters.EventName != "" && len(eventNames(filters.EventName)) == 1:
+		return &types.LookupAttribute{
+			AttributeKey:   types.LookupAttributeKeyEventName,
+			AttributeValue: aws.String(eventNames(filters.EventName)[0]),
+		}
+	case filters.Exact && filters.UserName != "":
+		return &types.LookupAttribute{
+			AttributeKey:   types.LookupAttributeKeyUsername,
+			AttributeValue: aws.String(filters.UserName),
+		}
+	case filters.EventSource != "":
+		return &types.LookupAttribute{
+			AttributeKey:   types.LookupAttributeKeyEventSource,
+			AttributeValue: aws.String(filters.EventSource),
+		}
+	}
+	return nil
+}
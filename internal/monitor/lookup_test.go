@@ -0,0 +1,125 @@
+// internal/monitor/lookup_test.go
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestLookupAttribute(t *testing.T) {
+	keyARN := "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	topicARN := "arn:aws:sns:us-east-1:123456789012:alerts"
+
+	tests := []struct {
+		name    string
+		filters FilterOptions
+		key     types.LookupAttributeKey // empty when nothing is pushed down
+		value   string
+	}{
+		{"no filters", FilterOptions{}, "", ""},
+		{"key ARN", FilterOptions{KeyID: keyARN}, types.LookupAttributeKeyResourceName, keyARN},
+		// A bare key id or alias doesn't match the ARN CloudTrail records
+		{"bare key id", FilterOptions{KeyID: "1234abcd-12ab-34cd-56ef-1234567890ab"}, "", ""},
+		// Calls may name an instance only in requestParameters, which a
+		// ResourceName lookup wouldn't return
+		{"instance", FilterOptions{InstanceID: "i-0abc"}, "", ""},
+		{"instance falls back to source", FilterOptions{InstanceID: "i-0abc", EventSource: EC2EventSource}, types.LookupAttributeKeyEventSource, EC2EventSource},
+		{"topic", FilterOptions{TopicARN: topicARN}, types.LookupAttributeKeyResourceName, topicARN},
+		{"event source", FilterOptions{EventSource: "ec2.amazonaws.com"}, types.LookupAttributeKeyEventSource, "ec2.amazonaws.com"},
+
+		// Names are substring matches client-side, so they need --exact
+		{"event name", FilterOptions{EventName: "Decrypt"}, "", ""},
+		{"user", FilterOptions{UserName: "alice"}, "", ""},
+		{"exact event name", FilterOptions{EventName: "Decrypt", Exact: true}, types.LookupAttributeKeyEventName, "Decrypt"},
+		{"exact event names", FilterOptions{EventName: "Decrypt,Encrypt", Exact: true}, "", ""},
+		{"exact user", FilterOptions{UserName: "alice", Exact: true}, types.LookupAttributeKeyUsername, "alice"},
+
+		// The most selective filter wins
+		{"key over name", FilterOptions{KeyID: keyARN, EventName: "Decrypt", Exact: true}, types.LookupAttributeKeyResourceName, keyARN},
+		{"name over user", FilterOptions{EventName: "Decrypt", UserName: "alice", Exact: true}, types.LookupAttributeKeyEventName, "Decrypt"},
+		{"user over source", FilterOptions{UserName: "alice", EventSource: "ec2.amazonaws.com", Exact: true}, types.LookupAttributeKeyUsername, "alice"},
+		{"name falls back to source", FilterOptions{EventName: "Run", EventSource: "ec2.amazonaws.com"}, types.LookupAttributeKeyEventSource, "ec2.amazonaws.com"},
+	}
+	for _, tc := range tests {
+		got := lookupAttribute(tc.filters)
+		if tc.key == "" {
+			if got != nil {
+				t.Errorf("%s: pushed down %s=%s, want nothing", tc.name, got.AttributeKey, *got.AttributeValue)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("%s: nothing pushed down, want %s=%s", tc.name, tc.key, tc.value)
+			continue
+		}
+		if got.AttributeKey != tc.key || *got.AttributeValue != tc.value {
+			t.Errorf("%s: pushed down %s=%s, want %s=%s", tc.name, got.AttributeKey, *got.AttributeValue, tc.key, tc.value)
+		}
+	}
+}
+
+func TestLookupAttributesSent(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{}}}
+	if _, err := scan(t, trail, FilterOptions{UserName: "alice", Exact: true}, OutputOptions{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	sent := trail.inputs[0].LookupAttributes
+	if len(sent) != 1 || sent[0].AttributeKey != types.LookupAttributeKeyUsername || *sent[0].AttributeValue != "alice" {
+		t.Errorf("LookupAttributes = %+v, want Username=alice", sent)
+	}
+
+	trail = &fakeTrail{pages: [][]types.Event{{}}}
+	if _, err := scan(t, trail, FilterOptions{UserName: "alice", Exact: true, ClientSideOnly: true}, OutputOptions{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if sent := trail.inputs[0].LookupAttributes; len(sent) != 0 {
+		t.Errorf("LookupAttributes = %+v with ClientSideOnly, want none", sent)
+	}
+}
+
+func TestSubstringFiltersStillMatch(t *testing.T) {
+	// Not pushed down, so a partial name reaches the client-side filter
+	trail := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice@example.com", 1, nil),
+		newEvent("2", "ReEncrypt", "bob", 2, nil),
+	}}}
+	out, err := scan(t, trail, FilterOptions{UserName: "alice"}, OutputOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trail.inputs[0].LookupAttributes) != 0 {
+		t.Errorf("substring user filter was pushed down: %+v", trail.inputs[0].LookupAttributes)
+	}
+	if !strings.Contains(out, "Found 1 matching events") {
+		t.Errorf("partial user name didn't match:\n%s", out)
+	}
+}
+
+func TestInstanceInRequestParametersOnly(t *testing.T) {
+	// StopInstances names the instance only in instancesSet, not in resources
+	stop := newEvent("1", "StopInstances", "alice", 1, map[string]interface{}{
+		"requestParameters": map[string]interface{}{
+			"instancesSet": map[string]interface{}{"items": []interface{}{map[string]interface{}{"instanceId": "i-0abc"}}},
+		},
+	})
+	other := newEvent("2", "StopInstances", "alice", 2, map[string]interface{}{
+		"requestParameters": map[string]interface{}{"instanceId": "i-0def"},
+	})
+	stop.EventSource, other.EventSource = sdkaws.String(EC2EventSource), sdkaws.String(EC2EventSource)
+
+	trail := &fakeTrail{pages: [][]types.Event{{stop, other}}}
+	out, err := scan(t, trail, FilterOptions{InstanceID: "i-0abc", EventSource: EC2EventSource}, OutputOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := trail.inputs[0].LookupAttributes
+	if len(sent) != 1 || sent[0].AttributeKey != types.LookupAttributeKeyEventSource {
+		t.Errorf("LookupAttributes = %+v, want EventSource", sent)
+	}
+	if !strings.Contains(out, "Found 1 matching events") {
+		t.Errorf("instance named only in request parameters didn't match:\n%s", out)
+	}
+}
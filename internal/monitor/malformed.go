@@ -0,0 +1,37 @@
+// internal/monitor/malformed.go
+package monitor
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// Placeholder used for an event name CloudTrail didn't return
+const missingEventName = "<missing>"
+
+// missingFields lists the required fields absent from an event
+func missingFields(event types.Event) []string {
+	var missing []string
+	if event.EventName == nil {
+		missing = append(missing, "EventName")
+	}
+	if event.EventTime == nil {
+		missing = append(missing, "EventTime")
+	}
+	return missing
+}
+
+// withPlaceholders fills in missing required fields so a malformed event can be
+// printed and logged. A missing EventTime becomes the zero time.
+func withPlaceholders(event types.Event) types.Event {
+	if event.EventName == nil {
+		name := missingEventName
+		event.EventName = &name
+	}
+	if event.EventTime == nil {
+		var zero time.Time
+		event.EventTime = &zero
+	}
+	return event
+}
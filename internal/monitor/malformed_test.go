@@ -0,0 +1,47 @@
+// internal/monitor/malformed_test.go
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+func malformedTrail() *fakeTrail {
+	nameless := newEvent("2", "", "bob", 2, nil)
+	nameless.EventName = nil
+	return &fakeTrail{pages: [][]types.Event{{newEvent("1", "Decrypt", "alice", 1, nil), nameless}}}
+}
+
+func TestMalformedEventsSkippedByDefault(t *testing.T) {
+	out, err := scan(t, malformedTrail(), FilterOptions{}, OutputOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Found 1 matching events") || !strings.Contains(out, "Skipped 1 malformed events") {
+		t.Errorf("the malformed event wasn't skipped and counted:\n%s", out)
+	}
+}
+
+func TestIncludeMalformed(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "events.log")
+	out, err := scan(t, malformedTrail(), FilterOptions{IncludeMalformed: true}, OutputOptions{}, &writer.ExportOptions{Filename: logFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Found 2 matching events") || !strings.Contains(out, "Incomplete: missing EventName") {
+		t.Errorf("the malformed event wasn't shown as incomplete:\n%s", out)
+	}
+
+	logged, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(logged), missingEventName) {
+		t.Errorf("log file doesn't hold the placeholder name:\n%s", logged)
+	}
+}
@@ -0,0 +1,26 @@
+// internal/monitor/message.go
+package monitor
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Prefix that makes an --error-message pattern case-insensitive
+const caseInsensitive = "(?i)"
+
+// CompileErrorMessage builds the --error-message filter. The pattern is a
+// case-insensitive regular expression, so plain text matches as a substring.
+func CompileErrorMessage(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(caseInsensitive + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --error-message pattern %q: %v", pattern, err)
+	}
+	return re, nil
+}
+
+// errorMessagePattern returns the pattern as the user gave it
+func errorMessagePattern(re *regexp.Regexp) string {
+	return strings.TrimPrefix(re.String(), caseInsensitive)
+}
@@ -0,0 +1,56 @@
+// internal/monitor/message_test.go
+package monitor
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestErrorMessageFilter(t *testing.T) {
+	pending := newEvent("1", "Decrypt", "alice", 0, map[string]interface{}{
+		"errorCode":    "KMSInvalidStateException",
+		"errorMessage": "arn:aws:kms:us-east-1:123456789012:key/key-1 is pending deletion.",
+	})
+	disabled := newEvent("2", "Decrypt", "alice", 0, map[string]interface{}{
+		"errorCode":    "DisabledException",
+		"errorMessage": "arn:aws:kms:us-east-1:123456789012:key/key-1 is disabled.",
+	})
+	succeeded := newEvent("3", "Decrypt", "alice", 0, map[string]interface{}{})
+	events := []types.Event{pending, disabled, succeeded}
+
+	tests := []struct {
+		pattern string
+		want    []bool // pending, disabled, succeeded
+	}{
+		{"key is pending deletion", []bool{false, false, false}}, // the ARN sits between "key" and "is"
+		{"pending deletion", []bool{true, false, false}},
+		{"PENDING DELETION", []bool{true, false, false}}, // case-insensitive
+		{`is (pending deletion|disabled)\.$`, []bool{true, true, false}},
+		{"", []bool{true, true, false}}, // any message, but there must be one
+	}
+	for _, tc := range tests {
+		re, err := CompileErrorMessage(tc.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, event := range events {
+			if got := passes(event, FilterOptions{ErrorMessage: re}); got != tc.want[i] {
+				t.Errorf("pattern %q on event %s: got %v, want %v", tc.pattern, *event.EventId, got, tc.want[i])
+			}
+		}
+	}
+}
+
+func TestCompileErrorMessage(t *testing.T) {
+	re, err := CompileErrorMessage("pending deletion")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := errorMessagePattern(re); got != "pending deletion" {
+		t.Errorf("errorMessagePattern = %q, want the pattern as given", got)
+	}
+	if _, err := CompileErrorMessage("(unclosed"); err == nil {
+		t.Error("CompileErrorMessage accepted an invalid pattern")
+	}
+}
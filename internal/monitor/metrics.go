@@ -0,0 +1,90 @@
+// internal/monitor/metrics.go
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/emf"
+)
+
+type sourceStats struct {
+	matched int
+	errors  int
+}
+
+// scanMetrics accumulates per-event-source counts for CloudWatch EMF output
+type scanMetrics struct {
+	started time.Time
+	sources map[string]*sourceStats
+}
+
+func newScanMetrics() *scanMetrics {
+	return &scanMetrics{started: time.Now(), sources: make(map[string]*sourceStats)}
+}
+
+func (s *scanMetrics) add(event types.Event, details map[string]interface{}) {
+	source := SafeString(event.EventSource)
+	stats, ok := s.sources[source]
+	if !ok {
+		stats = &sourceStats{}
+		s.sources[source] = stats
+	}
+	stats.matched++
+	if details != nil {
+		if _, isError := details["errorCode"].(string); isError {
+			stats.errors++
+		}
+	}
+}
+
+// documents builds one EMF document per event source. A scan without matches
+// still reports zero so CloudWatch alarms don't treat it as missing data.
+func (s *scanMetrics) documents(namespace string) []emf.Document {
+	now := time.Now()
+	duration := float64(now.Sub(s.started).Milliseconds())
+
+	sources := make([]string, 0, len(s.sources))
+	for source := range s.sources {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	if len(sources) == 0 {
+		sources = append(sources, "none")
+		s.sources["none"] = &sourceStats{}
+	}
+
+	docs := make([]emf.Document, 0, len(sources))
+	for _, source := range sources {
+		stats := s.sources[source]
+		docs = append(docs, emf.Document{
+			Namespace:  namespace,
+			Timestamp:  now,
+			Dimensions: map[string]string{"EventSource": source},
+			Metrics: []emf.Metric{
+				{Name: "MatchedEvents", Unit: emf.UnitCount, Value: float64(stats.matched)},
+				{Name: "ErrorEvents", Unit: emf.UnitCount, Value: float64(stats.errors)},
+				{Name: "ScanDuration", Unit: emf.UnitMilliseconds, Value: duration},
+			},
+		})
+	}
+	return docs
+}
+
+// writeEMF emits the metrics to stdout ("-") or appends them to a file
+func (s *scanMetrics) writeEMF(target, namespace string) error {
+	docs := s.documents(namespace)
+	if target == "-" {
+		return emf.Write(os.Stdout, docs)
+	}
+
+	f, err := os.OpenFile(target, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open EMF output: %v", err)
+	}
+	defer f.Close()
+	return emf.Write(f, docs)
+}
@@ -0,0 +1,48 @@
+// internal/monitor/metrics_test.go
+package monitor
+
+import (
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestScanMetricsDocuments(t *testing.T) {
+	metrics := newScanMetrics()
+	failed := newEvent("1", "Decrypt", "alice", 0, nil)
+	metrics.add(failed, map[string]interface{}{"errorCode": "AccessDenied"})
+	metrics.add(newEvent("2", "Decrypt", "alice", 1, nil), map[string]interface{}{})
+	sts := newEvent("3", "AssumeRole", "alice", 2, nil)
+	sts.EventSource = sdkaws.String("sts.amazonaws.com")
+	metrics.add(sts, nil)
+
+	docs := metrics.documents("Test")
+	if len(docs) != 2 {
+		t.Fatalf("got %d documents, want one per event source", len(docs))
+	}
+
+	want := map[string][2]float64{
+		"kms.amazonaws.com": {2, 1},
+		"sts.amazonaws.com": {1, 0},
+	}
+	for _, doc := range docs {
+		source := doc.Dimensions["EventSource"]
+		values := make(map[string]float64)
+		for _, metric := range doc.Metrics {
+			values[metric.Name] = metric.Value
+		}
+		if got := [2]float64{values["MatchedEvents"], values["ErrorEvents"]}; got != want[source] {
+			t.Errorf("%s: matched/errors = %v, want %v", source, got, want[source])
+		}
+		if doc.Namespace != "Test" {
+			t.Errorf("%s: namespace %q, want Test", source, doc.Namespace)
+		}
+	}
+}
+
+func TestScanMetricsReportZero(t *testing.T) {
+	docs := newScanMetrics().documents("Test")
+	if len(docs) != 1 || docs[0].Metrics[0].Value != 0 {
+		t.Errorf("empty scan documents = %+v, want a single zero count", docs)
+	}
+}
@@ -3,8 +3,11 @@ package monitor
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"regexp"
 	"runtime"
 	"strings"
 	"sync"
@@ -12,22 +15,215 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/arns"
 	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/classify"
+	"github.com/dhairya13703/cloudtrail-logs/internal/elastic"
+	"github.com/dhairya13703/cloudtrail-logs/internal/syslog"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
 	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
-	"github.com/fatih/color"
 )
 
 type Monitor struct {
-	client    *aws.AWSClient
-	logWriter *writer.LogWriter
-	mu        sync.Mutex
+	client       *aws.AWSClient
+	logWriter    *writer.LogWriter
+	output       OutputOptions
+	out          io.Writer      // console output
+	pending      []writer.Entry // events awaiting a batch write
+	rows         [][]string     // console rows held for --table
+	indexer      *elastic.Indexer
+	syslog       *syslog.Sender
+	syslogFailed bool
+	progress     *progress // nil unless OutputOptions.Progress
+
+	// mu serializes console output and writes while region fetchers run
+	mu sync.Mutex
+
+	// lookupClient returns the LookupEvents client for a region; nil uses
+	// the AWS client's
+	lookupClient func(region string) cloudtrail.LookupEventsAPIClient
+}
+
+// OutputOptions controls how a scan runs and how matched events are presented and reported
+type OutputOptions struct {
+	// Console receives human-readable output; defaults to stdout
+	Console io.Writer
+
+	// QuietNoResults prints nothing at all when no events match. Console must
+	// be a *DeferredWriter so output can be held until the first match.
+	QuietNoResults bool
+
+	GroupByRequest bool   // pair events sharing a requestID
+	ShowIndex      bool   // prefix each event with its sequence number
+	ShowCLI        bool   // print the equivalent `aws cloudtrail lookup-events` command
+	Order          string // newest (as returned) or oldest first; empty means newest
+
+	// TimelineBy "resource" prints each resource's events oldest first under
+	// a header; an event naming several resources appears under each
+	TimelineBy string
+
+	// MaxMemory caps the estimated size of events held back for grouping or
+	// reordering; past it they are spooled to a temp file. 0 means no cap.
+	MaxMemory int64
+
+	// Table renders matched events as one bordered table once the scan
+	// finishes. TableWidth caps its width; 0 uses the terminal width.
+	Table      bool
+	TableWidth int
+
+	// Histogram charts matched events per HistogramBucket (default one hour)
+	Histogram       bool
+	HistogramBucket time.Duration
+
+	// TruncateValues shortens any printed or logged string value longer than
+	// this many characters; 0 keeps values whole
+	TruncateValues int
+
+	// ConsoleJSON prints each event as the indented record of the json export
+	// instead of the field-by-field layout
+	ConsoleJSON bool
+
+	// Explain prints which filters each matched event satisfied
+	Explain bool
+
+	// Quiet skips the per-event console output, keeping the header and the
+	// end-of-run summary
+	Quiet bool
+
+	// Progress shows pages fetched and events scanned and matched on stderr
+	// while the scan runs
+	Progress bool
+
+	// IDsOnly prints just the matched EventIds to stdout, one per line. Callers
+	// should point Console at io.Discard to suppress everything else.
+	IDsOnly bool
+
+	// StdoutJSON prints each matched event to stdout as one compact JSON
+	// object (NDJSON). Like IDsOnly, Console should be io.Discard.
+	StdoutJSON bool
+
+	// Volume alerting: flag principals with more than AlertThreshold events
+	AlertThreshold int
+	AlertBy        string // user or key
+	AlertFail      bool   // return an error when the threshold is exceeded
+
+	// ExpectedUsers flags events from principals not on the allowlist
+	ExpectedUsers ExpectedUsers
+
+	// Baseline flags event+user combinations it hasn't seen before
+	Baseline *Baseline
+
+	// RequireEncryptionContext flags Encrypt/Decrypt/GenerateDataKey* calls
+	// made without an encryptionContext
+	RequireEncryptionContext bool
+
+	// SizeStats reports the total, average, median, and largest raw event size
+	SizeStats bool
+
+	// Summary prints matched event counts per event name, per user, and by outcome
+	Summary bool
+
+	// FailOnErrorEvents returns an error when any matched event has an errorCode
+	FailOnErrorEvents bool
+
+	// AlertSeverity flags classified events at or above this severity
+	AlertSeverity classify.Severity
+
+	// CloudWatch Embedded Metric Format output: "-" for stdout or a file path
+	EMFOutput    string
+	EMFNamespace string
+
+	// NormalizeARNs canonicalizes resource identifiers in output and grouping
+	NormalizeARNs bool
+
+	// ExplodeARNs prints each resource ARN's components on separate lines
+	ExplodeARNs bool
+
+	// BatchWrites hands events to the writer once per page instead of per event
+	BatchWrites bool
+
+	// ESURL bulk-indexes matched events into Elasticsearch/OpenSearch
+	ESURL       string
+	ESIndex     string
+	ESBatchSize int
+
+	// Syslog sends each matched event as an RFC5424 message to a
+	// [udp://|tcp://]host:port collector
+	Syslog string
+
+	// StateFile checkpoints the pagination position so an interrupted scan can resume
+	StateFile string
+
+	// ExactWindow rejects a saved state whose window differs from the one
+	// requested, instead of resuming the saved window. Set it when the window
+	// was given explicitly rather than relative to now.
+	ExactWindow bool
+
+	// MaxAttempts caps how often each LookupEvents call is tried when it is
+	// throttled or fails transiently, backing off with jitter in between;
+	// 0 uses retry.Default
+	MaxAttempts int
+
+	// ThrottleCooldown is how long to pause when throttling outlasts the
+	// per-call retries; the scan then resumes from the same page, at most
+	// ThrottleResumes times. Zero fails on the first exhausted retry.
+	ThrottleCooldown time.Duration
+	ThrottleResumes  int
+
+	// RegionConcurrency is how many regions are paged through at once in a
+	// multi-region scan; 0 uses DefaultRegionConcurrency
+	RegionConcurrency int
+
+	// Manifest writes a JSON index of the files the run produced
+	Manifest string
+
+	// Concurrency is how many workers filter and parse each page's events;
+	// 0 uses GOMAXPROCS. Pages themselves are still fetched one at a time.
+	Concurrency int
 }
 
-func NewKMSMonitor(client *aws.AWSClient, outputDir string, exportOptions *writer.ExportOptions) *Monitor {
-	return &Monitor{
+// ErrNoResults is returned in QuietNoResults mode when nothing matched
+var ErrNoResults = errors.New("no events found matching the specified filters")
+
+// ErrInterrupted wraps the context error when a scan stops early. What matched
+// by then has been written and reported, and the state file is kept.
+var ErrInterrupted = errors.New("scan interrupted")
+
+type matchedEvent struct {
+	event      types.Event
+	details    map[string]interface{}
+	missing    []string // required fields that were replaced with placeholders
+	unexpected bool     // principal is missing from ExpectedUsers
+	noContext  bool     // cryptographic call without an encryptionContext
+	newCombo   bool     // event+user combination missing from the Baseline
+	index      int      // sequence number in match order
+	explain    []filterResult
+	repeat     bool   // already written; print to the console only
+	region     string // set when several regions are scanned
+}
+
+func NewKMSMonitor(client *aws.AWSClient, outputDir string, exportOptions *writer.ExportOptions, outputOptions *OutputOptions) *Monitor {
+	return newMonitor(client, outputDir, "kms", exportOptions, outputOptions)
+}
+
+// newMonitor creates a monitor whose log files are tagged with serviceTag
+func newMonitor(client *aws.AWSClient, outputDir, serviceTag string, exportOptions *writer.ExportOptions, outputOptions *OutputOptions) *Monitor {
+	m := &Monitor{
 		client:    client,
-		logWriter: writer.NewLogWriter(outputDir, "kms", exportOptions),
+		logWriter: writer.NewLogWriter(outputDir, serviceTag, exportOptions),
 	}
+	if outputOptions != nil {
+		m.output = *outputOptions
+	}
+	m.out = m.output.Console
+	if m.out == nil {
+		m.out = os.Stdout
+	}
+	if m.output.ESURL != "" {
+		m.indexer = elastic.NewIndexer(m.output.ESURL, m.output.ESIndex, m.output.ESBatchSize)
+	}
+	return m
 }
 
 func isKMSEvent(event types.Event, keyID string) bool {
@@ -46,7 +242,7 @@ func isKMSEvent(event types.Event, keyID string) bool {
 		// Check CloudTrail event details
 		if event.CloudTrailEvent != nil {
 			var eventDetails map[string]interface{}
-			if err := json.Unmarshal([]byte(*event.CloudTrailEvent), &eventDetails); err == nil {
+			if err := decodeDetails(*event.CloudTrailEvent, &eventDetails); err == nil {
 				if reqParams, ok := eventDetails["requestParameters"].(map[string]interface{}); ok {
 					if keyArn, exists := reqParams["keyId"].(string); exists && strings.Contains(keyArn, keyID) {
 						return true
@@ -83,225 +279,837 @@ func bToMb(b uint64) uint64 {
 	return b / 1024 / 1024
 }
 
-func (m *Monitor) processEvent(event types.Event, keyID string, eventColor, warningColor func(a ...interface{}) string) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
+func (m *Monitor) MonitorKMSEvents(ctx context.Context, filters FilterOptions, start, end time.Time) error {
+	return m.monitorEvents(ctx, filters, start, end)
+}
 
-	// Basic validation
-	if event.EventName == nil || event.EventTime == nil {
-		return fmt.Errorf("invalid event: missing required fields")
+// monitorEvents scans the window and reports the events matching filters. The
+// per-service entry points set the service-specific filters before calling it.
+func (m *Monitor) monitorEvents(ctx context.Context, filters FilterOptions, start, end time.Time) error {
+	// Resume a previously interrupted scan. The NextToken is only valid for the
+	// window it was issued for, so the saved window replaces the requested one.
+	var state *scanState
+	if m.output.StateFile != "" {
+		saved, err := loadScanState(m.output.StateFile)
+		if err != nil {
+			return err
+		}
+		if saved != nil && m.output.ExactWindow && (!saved.StartTime.Equal(start) || !saved.EndTime.Equal(end)) {
+			return fmt.Errorf("%s was saved for %s to %s, not the requested %s to %s; rerun with that range or delete the file to start over",
+				m.output.StateFile,
+				timeutil.FormatTime(saved.StartTime), timeutil.FormatTime(saved.EndTime),
+				timeutil.FormatTime(start), timeutil.FormatTime(end))
+		}
+		if saved != nil {
+			fmt.Fprintf(m.out, "Resuming scan from %s (%d pages already processed)\n", m.output.StateFile, saved.Pages)
+			start, end = saved.StartTime, saved.EndTime
+			state = saved
+		} else {
+			state = &scanState{StartTime: start, EndTime: end}
+		}
 	}
 
-	var eventDetails map[string]interface{}
-	if event.CloudTrailEvent != nil {
-		if err := json.Unmarshal([]byte(*event.CloudTrailEvent), &eventDetails); err != nil {
-			fmt.Printf(warningColor("Warning: Failed to parse event details: %v\n"), err)
+	if m.output.Syslog != "" {
+		sender, err := syslog.Dial(m.output.Syslog)
+		if err != nil {
+			return err
 		}
+		defer sender.Close()
+		m.syslog = sender
 	}
 
-	// Write to log file
-	if err := m.logWriter.WriteEvent(event, eventDetails); err != nil {
-		fmt.Printf(warningColor("Warning: Failed to write to log file: %v\n"), err)
+	// Print active filters
+	fmt.Fprintln(m.out, theme.Info("Active Filters:"))
+	if m.multiRegion() {
+		fmt.Fprintf(m.out, "- Regions: %s\n", strings.Join(m.client.Regions, ", "))
+	}
+	if filters.EventSource != "" {
+		fmt.Fprintf(m.out, "- Event Source: %s\n", filters.EventSource)
+	}
+	if filters.KeyID != "" {
+		fmt.Fprintf(m.out, "- KMS Key: %s\n", filters.KeyID)
+	}
+	if filters.InstanceID != "" {
+		fmt.Fprintf(m.out, "- EC2 Instance: %s\n", filters.InstanceID)
+	}
+	if filters.TopicARN != "" {
+		fmt.Fprintf(m.out, "- SNS Topic: %s\n", filters.TopicARN)
+	}
+	if filters.EventName != "" {
+		fmt.Fprintf(m.out, "- Event Name: %s\n", filters.EventName)
+	}
+	if filters.UserName != "" {
+		fmt.Fprintf(m.out, "- User: %s\n", filters.UserName)
+	}
+	if filters.Operation != "" {
+		fmt.Fprintf(m.out, "- Operation: %s\n", filters.Operation)
+	}
+	if filters.Role != "" {
+		fmt.Fprintf(m.out, "- Role: %s\n", filters.Role)
+	}
+	if filters.MinTLS != "" {
+		fmt.Fprintf(m.out, "- TLS older than: %s\n", filters.MinTLS)
+	}
+	if filters.HasParam != "" {
+		fmt.Fprintf(m.out, "- Has request parameter: %s\n", filters.HasParam)
+	}
+	if filters.ResponseParam != "" {
+		fmt.Fprintf(m.out, "- Response element: %s\n", filters.ResponseParam)
+	}
+	if filters.SourceIP != "" {
+		fmt.Fprintf(m.out, "- Source IP: %s\n", filters.SourceIP)
+	}
+	if filters.ErrorMessage != nil {
+		fmt.Fprintf(m.out, "- Error message matching: %s\n", errorMessagePattern(filters.ErrorMessage))
+	}
+	if filters.HumansOnly {
+		fmt.Fprintln(m.out, "- Showing only human principals")
+	}
+	if filters.ConsoleOnly {
+		fmt.Fprintln(m.out, "- Showing only console requests")
+	}
+	if filters.MutationsOnly {
+		fmt.Fprintln(m.out, "- Showing only mutating events")
+	}
+	if len(filters.ExcludeEvents) > 0 {
+		fmt.Fprintf(m.out, "- Excluding events: %s\n", strings.Join(filters.ExcludeEvents, ", "))
+	}
+	if filters.MinSeverity > classify.SeverityNone {
+		fmt.Fprintf(m.out, "- Minimum severity: %s\n", filters.MinSeverity)
+	}
+	if filters.ErrorCode != "" {
+		fmt.Fprintf(m.out, "- Error code: %s\n", filters.ErrorCode)
+	} else if filters.ErrorsOnly {
+		fmt.Fprintln(m.out, "- Showing only errors")
+	}
+	if filters.SuccessOnly {
+		fmt.Fprintln(m.out, "- Showing only successful operations")
+	}
+	if filters.InsightsOnly {
+		fmt.Fprintln(m.out, "- Showing only CloudTrail Insights events")
+	}
+	if filters.SampleRate > 0 {
+		fmt.Fprintf(m.out, "- Sampling %.4g of matched events (seed %d)\n", filters.SampleRate, filters.Seed)
+	}
+	if m.output.GroupByRequest {
+		fmt.Fprintln(m.out, "- Grouping events by request ID")
+	}
+	if m.output.TimelineBy != "" {
+		fmt.Fprintf(m.out, "- Showing a timeline per %s\n", m.output.TimelineBy)
+	}
+	if m.output.AlertThreshold > 0 {
+		fmt.Fprintf(m.out, "- Alerting when a %s exceeds %d events\n", m.output.AlertBy, m.output.AlertThreshold)
 	}
 
-	// Console output
-	timeStr := event.EventTime.Format("2006-01-02 15:04:05")
-	fmt.Printf("[%s] %s\n", timeStr, eventColor(*event.EventName))
-	fmt.Printf("  User: %s\n", SafeString(event.Username))
+	fmt.Fprintf(m.out, "\nTime range: %s to %s (%s)\n",
+		timeutil.FormatTime(start),
+		timeutil.FormatTime(end),
+		timeutil.Location())
 
-	if len(event.Resources) > 0 {
-		fmt.Println("  Resources:")
-		for _, resource := range event.Resources {
-			resourceInfo := getResourceInfo(resource)
-			if resource.ResourceName != nil && strings.Contains(*resource.ResourceName, keyID) {
-				fmt.Printf("    - %s (Target Key)\n", resourceInfo)
+	if err := m.logWriter.Lock(); err != nil {
+		return err
+	}
+	defer m.logWriter.Unlock()
+	// Wait for a named pipe's reader here, where Ctrl-C still ends the wait,
+	// rather than on the first write with the monitor locked
+	if err := m.logWriter.OpenStream(ctx); err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.logWriter.Close(); err != nil {
+			fmt.Fprintf(m.out, theme.Warning("Warning: Failed to finish log file: %v\n"), err)
+		}
+		// After Close, so a buffered json-document is included
+		if m.output.Manifest != "" {
+			if err := writer.WriteManifest(m.output.Manifest, m.logWriter.Files()); err != nil {
+				fmt.Fprintf(m.out, theme.Warning("Warning: %v\n"), err)
 			} else {
-				fmt.Printf("    - %s\n", resourceInfo)
+				fmt.Fprintf(m.out, "Manifest written to %s\n", m.output.Manifest)
 			}
 		}
-	}
+	}()
+	m.logWriter.SetTimeRange(start, end)
 
-	// Print event details
-	if eventDetails != nil {
-		// Print request parameters
-		if reqParams, ok := eventDetails["requestParameters"].(map[string]interface{}); ok && len(reqParams) > 0 {
-			fmt.Println("  Request Parameters:")
-			for key, value := range reqParams {
-				if value != nil {
-					fmt.Printf("    %s: %v\n", key, value)
-				}
-			}
+	logFile := m.logWriter.GetCurrentFile()
+	fmt.Fprintf(m.out, "Output file: %s\n", logFile)
+	fmt.Fprintln(m.out, strings.Repeat("-", 80))
+
+	input := &cloudtrail.LookupEventsInput{
+		StartTime: &start,
+		EndTime:   &end,
+	}
+	if filters.InsightsOnly {
+		input.EventCategory = types.EventCategoryInsight
+	}
+	if !filters.ClientSideOnly {
+		if attribute := lookupAttribute(filters); attribute != nil {
+			input.LookupAttributes = []types.LookupAttribute{*attribute}
 		}
+	}
 
-		// Print response elements
-		if respElements, ok := eventDetails["responseElements"].(map[string]interface{}); ok && len(respElements) > 0 {
-			fmt.Println("  Response Elements:")
-			for key, value := range respElements {
-				if value != nil {
-					fmt.Printf("    %s: %v\n", key, value)
-				}
-			}
+	if m.output.ShowCLI && m.client != nil {
+		fmt.Fprintln(m.out, theme.Info("Equivalent AWS CLI command:"))
+		for _, region := range m.scanRegions() {
+			fmt.Fprintf(m.out, "  %s\n", awsCLICommand(input, m.client.Profile, region))
 		}
+		if remaining := clientSideFilters(filters, input.LookupAttributes); len(remaining) > 0 {
+			fmt.Fprintf(m.out, "  (applied client-side by this tool: %s)\n", strings.Join(remaining, " "))
+		}
+		fmt.Fprintln(m.out, strings.Repeat("-", 80))
 	}
 
-	fmt.Println(strings.Repeat("-", 80))
-	return nil
-}
+	resumeToken, matchedBefore := "", 0
+	if state != nil {
+		resumeToken, matchedBefore = state.NextToken, state.Matched
+	}
+	eventCount := 0
+	malformedCount := 0
 
-func (m *Monitor) MonitorKMSEvents(ctx context.Context, filters FilterOptions, start, end time.Time) error {
-	eventColor := color.New(color.FgGreen).SprintFunc()
-	warningColor := color.New(color.FgYellow).SprintFunc()
-	errorColor := color.New(color.FgRed).SprintFunc()
+	// Matched events are held back when they need to be paired or reordered before printing
+	buffered := newSpool(m.output.MaxMemory)
+	defer buffered.close()
+	holdBack := m.output.GroupByRequest || m.output.Order == OrderOldest || m.output.TimelineBy != ""
 
-	// Print active filters
-	fmt.Println("Active Filters:")
-	if filters.KeyID != "" {
-		fmt.Printf("- KMS Key: %s\n", filters.KeyID)
+	var metrics *scanMetrics
+	if m.output.EMFOutput != "" {
+		metrics = newScanMetrics()
 	}
-	if filters.EventName != "" {
-		fmt.Printf("- Event Name: %s\n", filters.EventName)
+
+	var sample *sampler
+	if filters.SampleRate > 0 {
+		sample = newSampler(filters.SampleRate, filters.Seed)
 	}
-	if filters.UserName != "" {
-		fmt.Printf("- User: %s\n", filters.UserName)
+
+	var severityAlert *severityAlerts
+	if m.output.AlertSeverity > classify.SeverityNone && filters.Classifier != nil {
+		severityAlert = newSeverityAlerts(m.output.AlertSeverity)
 	}
-	if filters.Operation != "" {
-		fmt.Printf("- Operation: %s\n", filters.Operation)
+
+	var chart *histogram
+	if m.output.Histogram {
+		bucket := m.output.HistogramBucket
+		if bucket <= 0 {
+			bucket = time.Hour
+		}
+		chart = newHistogram(bucket, start, end)
 	}
-	if filters.ErrorsOnly {
-		fmt.Println("- Showing only errors")
+
+	var novel *newCombinations
+	if m.output.Baseline != nil {
+		novel = newNewCombinations(m.output.Baseline)
 	}
-	if filters.SuccessOnly {
-		fmt.Println("- Showing only successful operations")
+
+	var violations *contextViolations
+	if m.output.RequireEncryptionContext {
+		violations = newContextViolations()
 	}
 
-	fmt.Printf("\nTime range: %s to %s\n",
-		start.Format("2006-01-02 15:04:05"),
-		end.Format("2006-01-02 15:04:05"))
+	var unexpected *unexpectedUsers
+	if m.output.ExpectedUsers != nil {
+		unexpected = newUnexpectedUsers()
+	}
 
-	logFile := m.logWriter.GetCurrentFile()
-	fmt.Printf("Output file: %s\n", logFile)
-	fmt.Println(strings.Repeat("-", 80))
+	var sizes *sizeStats
+	if m.output.SizeStats {
+		sizes = newSizeStats()
+	}
 
-	input := &cloudtrail.LookupEventsInput{
-		StartTime: &start,
-		EndTime:   &end,
+	var summary *eventSummary
+	if m.output.Summary {
+		summary = newEventSummary()
 	}
 
-	paginator := cloudtrail.NewLookupEventsPaginator(m.client.CloudTrail, input)
-	eventCount := 0
+	var failures *errorEvents
+	if m.output.FailOnErrorEvents {
+		failures = newErrorEvents()
+	}
 
-	for paginator.HasMorePages() {
-		output, err := paginator.NextPage(ctx)
-		if err != nil {
-			return fmt.Errorf("error looking up events: %v", err)
+	var alerts *alertCounter
+	if m.output.AlertThreshold > 0 {
+		alerts = newAlertCounter(m.output.AlertBy)
+		if m.output.NormalizeARNs {
+			alerts.normalize = m.arnContext()
+		}
+	}
+
+	if m.output.Progress {
+		m.progress = newProgress()
+		defer func() {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.progress.clear()
+			m.progress = nil
+		}()
+	}
+
+	// Pages are fetched per region in the background but processed here one
+	// at a time, so the trackers and writers below never run concurrently
+	fetchCtx, stopFetching := context.WithCancel(ctx)
+	defer stopFetching()
+	for page := range m.fetchPages(fetchCtx, input, m.scanRegions(), resumeToken) {
+		// Stop promptly on cancellation or timeout, even between pages
+		if ctx.Err() != nil {
+			break
 		}
 
-		for _, event := range output.Events {
-			if !matchesFilter(event, filters) {
+		if page.err != nil {
+			if m.multiRegion() {
+				return fmt.Errorf("%s: %v", page.region, lookupError(page.err))
+			}
+			return lookupError(page.err)
+		}
+		output := page.output
+		region := ""
+		if m.multiRegion() {
+			region = page.region
+		}
+
+		// Workers filter and parse the page's events; the results are merged
+		// in page order under mu before anything is tallied or written
+		evaluated := evaluatePage(output.Events, filters, sample, m.concurrency())
+
+		m.mu.Lock()
+		if m.progress != nil {
+			m.progress.clear()
+		}
+		for i, event := range output.Events {
+			if !evaluated[i].matched {
 				continue
 			}
 
-			eventCount++
+			var explanation []filterResult
+			if m.output.Explain {
+				explanation = evaluated[i].results
+			}
 
-			var eventDetails map[string]interface{}
-			if event.CloudTrailEvent != nil {
-				if err := json.Unmarshal([]byte(*event.CloudTrailEvent), &eventDetails); err != nil {
-					fmt.Printf(warningColor("Warning: Failed to parse event details: %v\n"), err)
+			missing := missingFields(event)
+			if len(missing) > 0 {
+				if !filters.IncludeMalformed {
+					malformedCount++
+					continue
 				}
+				event = withPlaceholders(event)
 			}
 
-			// Write to log file
-			if err := m.logWriter.WriteEvent(event, eventDetails); err != nil {
-				fmt.Printf(warningColor("Warning: Failed to write to log file: %v\n"), err)
+			eventCount++
+			if eventCount == 1 {
+				m.releaseOutput()
 			}
 
-			// Console output
-			timeStr := event.EventTime.Format("2006-01-02 15:04:05")
-			eventName := SafeString(event.EventName)
-			username := SafeString(event.Username)
-
-			// Determine if event had an error
-			isError := false
-			if eventDetails != nil {
-				_, isError = eventDetails["errorCode"].(string)
+			eventDetails := evaluated[i].details
+			if err := evaluated[i].parseErr; err != nil {
+				fmt.Fprintf(m.out, theme.Warning("Warning: Failed to parse event details: %v\n"), err)
 			}
 
-			// Color the event name based on status
-			coloredEventName := eventName
-			if isError {
-				coloredEventName = errorColor(eventName)
-			} else {
-				coloredEventName = eventColor(eventName)
+			if m.output.NormalizeARNs {
+				event = m.normalizeResources(event)
 			}
 
-			fmt.Printf("[%s] %s\n", timeStr, coloredEventName)
-			fmt.Printf("  User: %s\n", username)
+			if alerts != nil {
+				alerts.add(event, eventDetails)
+			}
+			if severityAlert != nil {
+				classification, _ := filters.Classifier.Classify(*event.EventName)
+				severityAlert.add(*event.EventName, classification)
+			}
+			if metrics != nil {
+				metrics.add(event, eventDetails)
+			}
+			if sizes != nil {
+				sizes.add(event)
+			}
+			if summary != nil {
+				summary.add(event, eventDetails, region)
+			}
+			if failures != nil {
+				failures.add(event, eventDetails)
+			}
+			if chart != nil && len(missing) == 0 {
+				chart.add(*event.EventTime)
+			}
 
-			if len(event.Resources) > 0 {
-				fmt.Println("  Resources:")
-				for _, resource := range event.Resources {
-					resourceInfo := getResourceInfo(resource)
-					if resource.ResourceName != nil && filters.KeyID != "" &&
-						strings.Contains(*resource.ResourceName, filters.KeyID) {
-						fmt.Printf("    - %s (Target Key)\n", resourceInfo)
-					} else {
-						fmt.Printf("    - %s\n", resourceInfo)
-					}
+			match := matchedEvent{event: event, details: eventDetails, missing: missing, explain: explanation, region: region}
+			if unexpected != nil && !m.output.ExpectedUsers.expected(event, eventDetails) {
+				match.unexpected = true
+				unexpected.add(event)
+			}
+			if novel != nil && novel.add(event) {
+				match.newCombo = true
+			}
+			if violations != nil && missingEncryptionContext(event, eventDetails) {
+				match.noContext = true
+				violations.add(event)
+			}
+			if m.output.ShowIndex {
+				match.index = eventCount
+			}
+			if holdBack {
+				if err := buffered.add(match); err != nil {
+					fmt.Fprintf(m.out, theme.Warning("Warning: Failed to spool held events: %v\n"), err)
 				}
+				continue
 			}
+			m.emitEvent(match, filters)
+		}
 
-			// Print event details
-			if eventDetails != nil {
-				// Print request parameters
-				if reqParams, ok := eventDetails["requestParameters"].(map[string]interface{}); ok && len(reqParams) > 0 {
-					fmt.Println("  Request Parameters:")
-					for key, value := range reqParams {
-						if value != nil {
-							fmt.Printf("    %s: %v\n", key, value)
-						}
-					}
-				}
+		// Flush before checkpointing so a resume never skips unwritten events
+		m.flushBatch()
+		if m.indexer != nil && m.indexer.Full() {
+			m.flushIndex(ctx)
+		}
+		if m.progress != nil {
+			m.progress.page(len(output.Events), eventCount)
+		}
+		m.mu.Unlock()
 
-				// Print errors if present
-				if errorCode, ok := eventDetails["errorCode"].(string); ok {
-					errorMessage, _ := eventDetails["errorMessage"].(string)
-					fmt.Printf(errorColor("  Error: %s - %s\n"), errorCode, errorMessage)
-				}
+		if state != nil {
+			state.Pages++
+			state.Matched = matchedBefore + eventCount
+			state.NextToken = page.nextToken
+			if n := len(output.Events); n > 0 && output.Events[n-1].EventTime != nil {
+				state.LastEventTime = output.Events[n-1].EventTime
 			}
+			if err := state.save(m.output.StateFile); err != nil {
+				fmt.Fprintf(m.out, theme.Warning("Warning: Failed to save scan state: %v\n"), err)
+			}
+		}
+	}
+
+	if m.progress != nil {
+		m.progress.clear()
+	}
+
+	// On cancellation (Ctrl-C or a timeout) the pages processed so far are
+	// still written out and summarized; the state file is kept for a resume
+	var scanErr error
+	if err := ctx.Err(); err != nil {
+		scanErr = fmt.Errorf("%w: %w", ErrInterrupted, err)
+		fmt.Fprintln(m.out, theme.Warning("\nScan interrupted; the results below are partial"))
+	} else if state != nil {
+		// The scan completed, so there is nothing left to resume
+		if err := os.Remove(m.output.StateFile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(m.out, theme.Warning("Warning: Failed to remove scan state: %v\n"), err)
+		}
+	}
+	finishCtx := context.WithoutCancel(ctx)
 
-			fmt.Println(strings.Repeat("-", 80))
+	if buffered.spilled() {
+		fmt.Fprintf(m.out, theme.Info("Reading %d held events back from disk (over --max-memory)\n"), buffered.len())
+	}
+	chronological := m.output.Order == OrderOldest || m.output.TimelineBy != ""
+	emitted := make(map[int]bool)
+	emitHeld := func(position int) {
+		match, err := buffered.at(position)
+		if err != nil {
+			fmt.Fprintf(m.out, theme.Warning("Warning: %v\n"), err)
+			return
+		}
+		// Number events in chronological order when printing oldest first
+		if m.output.ShowIndex && chronological {
+			match.index = buffered.len() - position
+		}
+		// Events listed under several resources are only written out once
+		match.repeat = emitted[position]
+		emitted[position] = true
+		m.emitEvent(match, filters)
+	}
+	positions := emitOrder(buffered.len(), m.output.Order)
+	if m.output.TimelineBy == TimelineResource {
+		for _, timeline := range timelineByResource(emitOrder(buffered.len(), OrderOldest), buffered.resources) {
+			resource := timeline.resource
+			if resource == "" {
+				resource = "(no resource)"
+			}
+			if !m.output.Quiet {
+				fmt.Fprintf(m.out, "Resource: %s (%d events)\n", resource, len(timeline.events))
+			}
+			for _, position := range timeline.events {
+				emitHeld(position)
+			}
+		}
+	} else if m.output.GroupByRequest {
+		for _, group := range groupByRequestID(positions, buffered.requestIDs) {
+			if group.requestID != "" && !m.output.Quiet {
+				fmt.Fprintf(m.out, "Request ID: %s (%d events)\n", group.requestID, len(group.events))
+			}
+			for _, position := range group.events {
+				emitHeld(position)
+			}
+		}
+	} else {
+		for _, position := range positions {
+			emitHeld(position)
 		}
 	}
+	m.flushBatch()
+	m.flushIndex(finishCtx)
+
+	m.renderTable()
+
+	if eventCount == 0 && m.output.QuietNoResults && scanErr == nil {
+		if deferred, ok := m.out.(*DeferredWriter); ok {
+			deferred.Discard()
+		}
+		return ErrNoResults
+	}
 
 	if eventCount == 0 {
-		fmt.Println(warningColor("\nNo events found matching the specified filters"))
+		fmt.Fprintln(m.out, theme.Warning("\nNo events found matching the specified filters"))
 	} else {
-		fmt.Printf("\nFound %d matching events\n", eventCount)
+		fmt.Fprintf(m.out, "\nFound %d matching events\n", eventCount)
+	}
+
+	if chart != nil && eventCount > 0 {
+		chart.render(m.out)
+	}
+
+	if malformedCount > 0 {
+		fmt.Fprintf(m.out, theme.Warning("Skipped %d malformed events missing EventName or EventTime (use --include-malformed to show them)\n"), malformedCount)
+	}
+
+	if sizes != nil {
+		sizes.report(m.out)
+	}
+
+	if summary != nil {
+		summary.report(m.out)
+	}
+
+	if metrics != nil {
+		if err := metrics.writeEMF(m.output.EMFOutput, m.output.EMFNamespace); err != nil {
+			fmt.Fprintf(m.out, theme.Warning("Warning: Failed to write EMF metrics: %v\n"), err)
+		}
+	}
+
+	// Every report is printed (and the baseline saved) before any of them
+	// fails the run
+	var failed []error
+	if alerts != nil && alerts.report(m.out, m.output.AlertThreshold) && m.output.AlertFail {
+		failed = append(failed, fmt.Errorf("alert threshold of %d events exceeded", m.output.AlertThreshold))
+	}
+	if severityAlert != nil && severityAlert.report(m.out) && m.output.AlertFail {
+		failed = append(failed, fmt.Errorf("events at or above %s severity found", m.output.AlertSeverity))
+	}
+	if unexpected != nil {
+		unexpected.report(m.out)
+	}
+	if violations != nil {
+		violations.report(m.out)
+	}
+	if novel != nil {
+		novel.report(m.out)
+	}
+	if failures != nil && failures.report(m.out) {
+		failed = append(failed, fmt.Errorf("%d matched events have an errorCode", len(failures.events)))
+	}
+
+	// An interrupted scan's results are partial, so the interruption is what
+	// the run ends with
+	if scanErr != nil {
+		return scanErr
+	}
+	return errors.Join(failed...)
+}
+
+// releaseOutput starts printing console output held back by QuietNoResults
+func (m *Monitor) releaseOutput() {
+	if deferred, ok := m.out.(*DeferredWriter); ok {
+		deferred.Release()
+	}
+}
+
+// arnContext supplies the scan's region and account for expanding bare identifiers
+func (m *Monitor) arnContext() arns.Context {
+	if m.client == nil {
+		return arns.Context{}
+	}
+	return arns.Context{Region: m.client.Region, Account: m.client.Account}
+}
+
+// normalizeResources returns the event with canonical resource names. The
+// Resources slice is copied so the SDK's page data is left untouched.
+func (m *Monitor) normalizeResources(event types.Event) types.Event {
+	if len(event.Resources) == 0 {
+		return event
+	}
+	ctx := m.arnContext()
+	resources := make([]types.Resource, len(event.Resources))
+	for i, resource := range event.Resources {
+		resources[i] = resource
+		if resource.ResourceName != nil {
+			name := arns.Normalize(*resource.ResourceName, ctx)
+			resources[i].ResourceName = &name
+		}
+	}
+	event.Resources = resources
+	return event
+}
+
+// flushBatch writes any events queued by batch mode
+func (m *Monitor) flushBatch() {
+	if len(m.pending) == 0 {
+		return
+	}
+	if err := m.logWriter.WriteBatch(m.pending); err != nil {
+		fmt.Fprintf(m.out, theme.Warning("Warning: Failed to write to log file: %v\n"), err)
+	}
+	m.pending = m.pending[:0]
+}
+
+// emitEvent writes a matched event to the log file and prints it to the console
+func (m *Monitor) emitEvent(match matchedEvent, filters FilterOptions) {
+	event, eventDetails := match.event, match.details
+
+	// Long values are shortened for the log file and console, but indexed in full
+	fullDetails := eventDetails
+	if m.output.TruncateValues > 0 && eventDetails != nil {
+		eventDetails = truncateValues(eventDetails, m.output.TruncateValues).(map[string]interface{})
+	}
+
+	// Write to log file. Only the console shows an event more than once.
+	entry := writer.Entry{Event: event, Details: eventDetails, Index: match.index, Region: match.region}
+	if !match.repeat {
+		if m.output.BatchWrites {
+			m.pending = append(m.pending, entry)
+		} else if err := m.logWriter.WriteEntry(entry); err != nil {
+			fmt.Fprintf(m.out, theme.Warning("Warning: Failed to write to log file: %v\n"), err)
+		}
+		if m.indexer != nil {
+			m.indexer.Add(indexDocument(writer.Entry{Event: event, Details: fullDetails, Index: match.index, Region: match.region}))
+		}
+		m.sendSyslog(writer.Entry{Event: event, Details: fullDetails, Index: match.index, Region: match.region})
+	}
+
+	// Machine-readable output goes to stdout even though Console is discarded
+	if m.output.IDsOnly {
+		if match.repeat {
+			return
+		}
+		fmt.Fprintln(os.Stdout, SafeString(event.EventId))
+		return
+	}
+	if m.output.StdoutJSON {
+		if match.repeat {
+			return
+		}
+		if err := writeStdoutJSON(os.Stdout, match, fullDetails); err != nil {
+			fmt.Fprintf(os.Stderr, theme.Warning("Warning: %v\n"), err)
+		}
+		return
+	}
+
+	// Quiet runs only write the event out; the end-of-run report still prints
+	if m.output.Quiet {
+		return
+	}
+
+	if m.output.Table {
+		m.rows = append(m.rows, tableRow(match))
+		return
+	}
+
+	if m.output.ConsoleJSON {
+		content, err := writer.FormatJSONEvent(entry)
+		if err != nil {
+			fmt.Fprintf(m.out, theme.Warning("Warning: %v\n"), err)
+			return
+		}
+		fmt.Fprint(m.out, content)
+		return
+	}
+
+	// Console output
+	timeStr := timeutil.FormatTime(*event.EventTime)
+	eventName := SafeString(event.EventName)
+	username := SafeString(event.Username)
+
+	// Determine if event had an error
+	isError := false
+	if eventDetails != nil {
+		_, isError = eventDetails["errorCode"].(string)
+	}
+
+	classification, classified := filters.Classifier.Classify(eventName)
+
+	// Color the event name based on status, then on its classified severity
+	coloredEventName := eventName
+	if isError {
+		coloredEventName = theme.Error(eventName)
+	} else if classified {
+		coloredEventName = severityColor(classification.Severity)(eventName)
+	} else {
+		coloredEventName = theme.Success(eventName)
+	}
+
+	if match.index > 0 {
+		fmt.Fprintf(m.out, "#%d ", match.index)
+	}
+	fmt.Fprintf(m.out, "[%s] %s\n", timeStr, coloredEventName)
+	if len(match.missing) > 0 {
+		fmt.Fprintf(m.out, theme.Warning("  Incomplete: missing %s\n"), strings.Join(match.missing, ", "))
+	}
+	if m.output.Explain {
+		fmt.Fprintf(m.out, theme.Info("  Explain: %s\n"), explainMatch(match.explain))
+	}
+	if match.unexpected {
+		fmt.Fprintf(m.out, "  User: %s %s\n", username, theme.Error("(Unexpected)"))
+	} else {
+		fmt.Fprintf(m.out, "  User: %s\n", username)
+	}
+	if match.region != "" {
+		fmt.Fprintf(m.out, "  Region: %s\n", match.region)
+	}
+	if match.newCombo {
+		fmt.Fprintln(m.out, theme.Warning("  Baseline: new event/user combination"))
+	}
+	if match.noContext {
+		fmt.Fprintln(m.out, theme.Error("  Compliance: no encryptionContext in request"))
+	}
+	if classified {
+		fmt.Fprintf(m.out, "  Severity: %s", classification.Severity)
+		if len(classification.Tags) > 0 {
+			fmt.Fprintf(m.out, " [%s]", strings.Join(classification.Tags, ", "))
+		}
+		fmt.Fprintln(m.out)
+	}
+
+	if len(event.Resources) > 0 {
+		fmt.Fprintln(m.out, "  Resources:")
+		for _, resource := range event.Resources {
+			resourceInfo := getResourceInfo(resource)
+			if resource.ResourceName != nil && filters.KeyID != "" &&
+				strings.Contains(*resource.ResourceName, filters.KeyID) {
+				fmt.Fprintf(m.out, "    - %s %s\n", resourceInfo, theme.Highlight("(Target Key)"))
+			} else {
+				fmt.Fprintf(m.out, "    - %s\n", resourceInfo)
+			}
+			if m.output.ExplodeARNs && resource.ResourceName != nil {
+				if fields, ok := arns.Explode(*resource.ResourceName); ok {
+					for _, field := range fields {
+						fmt.Fprintf(m.out, "        %-9s %s\n", field.Name+":", field.Value)
+					}
+				}
+			}
+		}
+	}
+
+	// Print event details
+	if eventDetails != nil {
+		if insight, ok := parseInsight(eventDetails); ok {
+			fmt.Fprintf(m.out, theme.Warning("  Insight: %s\n"), insight.describe())
+		}
+
+		if tlsVersion, ok := lookupPath(eventDetails, "tlsDetails.tlsVersion").(string); ok {
+			cipherSuite, _ := lookupPath(eventDetails, "tlsDetails.cipherSuite").(string)
+			fmt.Fprintf(m.out, "  TLS: %s %s\n", tlsVersion, cipherSuite)
+		}
+
+		// Print request parameters
+		if reqParams, ok := eventDetails["requestParameters"].(map[string]interface{}); ok && len(reqParams) > 0 {
+			fmt.Fprintln(m.out, "  Request Parameters:")
+			for key, value := range reqParams {
+				if value != nil {
+					fmt.Fprintf(m.out, "    %s: %v\n", key, value)
+				}
+			}
+		}
+
+		// Print errors if present
+		if errorCode, ok := eventDetails["errorCode"].(string); ok {
+			errorMessage, _ := eventDetails["errorMessage"].(string)
+			fmt.Fprintf(m.out, theme.Error("  Error: %s - %s\n"), errorCode, errorMessage)
+		}
 	}
-	return nil
+
+	fmt.Fprintln(m.out, strings.Repeat("-", 80))
 }
 
 // internal/monitor/monitor.go
 
 type FilterOptions struct {
+	EventSource string // e.g. ec2.amazonaws.com; set by the per-service monitors
 	KeyID       string
+	InstanceID  string // EC2 instance in resources or requestParameters
+	TopicARN    string // SNS topic in resources or requestParameters
 	EventName   string
 	UserName    string
 	Operation   string
 	ErrorsOnly  bool
+	ErrorCode   string // exact errorCode, ignoring case; implies ErrorsOnly
 	SuccessOnly bool
+	Role        string // assumed-role session issuer name
+	MinTLS      string // keep only calls made over TLS older than this version
+	HumansOnly  bool   // drop AWS service and service-linked role activity
+	ConsoleOnly bool   // keep only AWS Management Console requests
+
+	// MutationsOnly keeps events whose names start with a state-changing verb
+	MutationsOnly bool
+	ExcludeEvents []string // event names to drop after the other filters, ignoring case
+	MutationVerbs []string // defaults to DefaultMutationVerbs
+	HasParam      string   // dotted key that must be present in requestParameters
+
+	ResponseParam string // key=value that must hold in responseElements (dotted key)
+	SourceIP      string // sourceIPAddress equal to this address or inside this CIDR
+
+	// ErrorMessage matches the errorMessage text; see CompileErrorMessage
+	ErrorMessage *regexp.Regexp
+
+	// Sampling keeps a reproducible fraction of matched events
+	SampleRate float64 // 0 < rate <= 1; 0 disables sampling
+	Seed       int64
+
+	// Classification from a user-supplied rules file
+	Classifier  *classify.Classifier
+	MinSeverity classify.Severity // keep only classified events at or above this
+
+	// Exact matches the event and user names whole (case-sensitively, as
+	// CloudTrail does) instead of as case-insensitive substrings, which lets
+	// them be pushed down as LookupAttributes
+	Exact bool
+
+	// ClientSideOnly skips LookupAttributes pushdown entirely, at the cost of
+	// fetching every event
+	ClientSideOnly bool
+
+	IncludeMalformed bool // keep events missing EventName/EventTime
+	InsightsOnly     bool // look up CloudTrail Insights anomaly events instead of API calls
 }
 
-func matchesFilter(event types.Event, filters FilterOptions) bool {
+// filterResult records how one active filter judged an event
+type filterResult struct {
+	name    string
+	matched bool
+}
+
+// evaluateFilters applies each active filter in turn, stopping at the first
+// one the event fails, so the results also explain why an event matched
+func evaluateFilters(event types.Event, filters FilterOptions) []filterResult {
+	var results []filterResult
+	check := func(name string, matched bool) bool {
+		results = append(results, filterResult{name: name, matched: matched})
+		return matched
+	}
+
+	// Event details are only parsed once, and only if a filter needs them
+	var eventDetails map[string]interface{}
+	parsed, parseOK := false, false
+	details := func() bool {
+		if !parsed {
+			parsed = true
+			parseOK = event.CloudTrailEvent != nil && decodeDetails(*event.CloudTrailEvent, &eventDetails) == nil
+		}
+		return parseOK
+	}
+
 	// Always check KMS key if provided
 	if filters.KeyID != "" {
-		isKMSMatch := false
+		name, isKMSMatch := "key substring", false
 		// Check in resources
 		if event.Resources != nil {
 			for _, resource := range event.Resources {
 				if resource.ResourceType != nil && resource.ResourceName != nil {
 					if *resource.ResourceType == "AWS::KMS::Key" && strings.Contains(*resource.ResourceName, filters.KeyID) {
-						isKMSMatch = true
+						name, isKMSMatch = "key in resources", true
 						break
 					}
 				}
@@ -309,58 +1117,242 @@ func matchesFilter(event types.Event, filters FilterOptions) bool {
 		}
 
 		// Check in event details
-		if !isKMSMatch && event.CloudTrailEvent != nil {
-			var eventDetails map[string]interface{}
-			if err := json.Unmarshal([]byte(*event.CloudTrailEvent), &eventDetails); err == nil {
-				if reqParams, ok := eventDetails["requestParameters"].(map[string]interface{}); ok {
-					if keyArn, exists := reqParams["keyId"].(string); exists && strings.Contains(keyArn, filters.KeyID) {
-						isKMSMatch = true
-					}
+		if !isKMSMatch && details() {
+			if reqParams, ok := eventDetails["requestParameters"].(map[string]interface{}); ok {
+				if keyArn, exists := reqParams["keyId"].(string); exists && strings.Contains(keyArn, filters.KeyID) {
+					name, isKMSMatch = "key in request parameters", true
 				}
 			}
 		}
 
-		if !isKMSMatch {
-			return false
+		if !check(name, isKMSMatch) {
+			return results
+		}
+	}
+
+	if filters.EventSource != "" {
+		if !check("event source", SafeString(event.EventSource) == filters.EventSource) {
+			return results
+		}
+	}
+
+	if filters.InstanceID != "" {
+		name, matched := "instance in resources", hasResource(event, "AWS::EC2::Instance", filters.InstanceID)
+		if !matched && details() {
+			name, matched = "instance in request parameters", requestInstanceID(eventDetails, filters.InstanceID)
+		}
+		if !check(name, matched) {
+			return results
+		}
+	}
+
+	if filters.TopicARN != "" {
+		name, matched := "topic in resources", hasResource(event, "AWS::SNS::Topic", filters.TopicARN)
+		if !matched && details() {
+			topicArn, _ := lookupPath(eventDetails, "requestParameters.topicArn").(string)
+			name, matched = "topic in request parameters", topicArn == filters.TopicARN
+		}
+		if !check(name, matched) {
+			return results
 		}
 	}
 
-	// Check event name if provided
+	// Check event name if provided; a comma-separated list matches any of its names
 	if filters.EventName != "" {
-		if event.EventName == nil || !strings.Contains(strings.ToLower(*event.EventName), strings.ToLower(filters.EventName)) {
-			return false
+		if filters.Exact {
+			if !check("event name", eventNameEquals(event.EventName, eventNames(filters.EventName))) {
+				return results
+			}
+		} else if !check("event name substring", eventNameMatches(event.EventName, eventNames(filters.EventName))) {
+			return results
 		}
 	}
 
 	// Check username if provided
 	if filters.UserName != "" {
-		if event.Username == nil || !strings.Contains(strings.ToLower(*event.Username), strings.ToLower(filters.UserName)) {
-			return false
+		if filters.Exact {
+			if !check("user", SafeString(event.Username) == filters.UserName) {
+				return results
+			}
+		} else {
+			matched := event.Username != nil && strings.Contains(strings.ToLower(*event.Username), strings.ToLower(filters.UserName))
+			if !check("user substring", matched) {
+				return results
+			}
 		}
 	}
 
 	// Check operation if provided
 	if filters.Operation != "" {
-		if event.EventName == nil || !strings.Contains(*event.EventName, filters.Operation) {
-			return false
+		matched := event.EventName != nil && strings.Contains(*event.EventName, filters.Operation)
+		if !check("operation substring", matched) {
+			return results
 		}
 	}
 
-	// Check for errors/success if requested
-	if filters.ErrorsOnly || filters.SuccessOnly {
-		var eventDetails map[string]interface{}
-		if event.CloudTrailEvent != nil {
-			if err := json.Unmarshal([]byte(*event.CloudTrailEvent), &eventDetails); err == nil {
-				errorCode, hasError := eventDetails["errorCode"].(string)
-				if filters.ErrorsOnly && !hasError {
-					return false
-				}
-				if filters.SuccessOnly && hasError && errorCode != "" {
-					return false
-				}
+	// Check the role behind assumed-role sessions if provided
+	if filters.Role != "" {
+		matched := false
+		if details() {
+			roleName, _ := lookupPath(eventDetails, "userIdentity.sessionContext.sessionIssuer.userName").(string)
+			matched = roleName != "" && strings.Contains(strings.ToLower(roleName), strings.ToLower(filters.Role))
+		}
+		if !check("session issuer role", matched) {
+			return results
+		}
+	}
+
+	// Check request parameter presence if provided
+	if filters.HasParam != "" {
+		matched := false
+		if details() {
+			_, matched = walkPath(eventDetails, "requestParameters."+filters.HasParam)
+		}
+		if !check("has request parameter "+filters.HasParam, matched) {
+			return results
+		}
+	}
+
+	// Check a response element value if provided
+	if filters.ResponseParam != "" {
+		if !check("response element "+filters.ResponseParam, details() && responseParamMatches(eventDetails, filters.ResponseParam)) {
+			return results
+		}
+	}
+
+	// Check the caller's address if provided
+	if filters.SourceIP != "" {
+		if !check("source IP "+filters.SourceIP, details() && sourceIPMatches(eventDetails, filters.SourceIP)) {
+			return results
+		}
+	}
+
+	// Drop AWS-internal callers if requested
+	if filters.HumansOnly {
+		if !check("human principal", details() && humanPrincipal(eventDetails)) {
+			return results
+		}
+	}
+
+	// Keep only state-changing calls if requested
+	if filters.MutationsOnly {
+		verbs := filters.MutationVerbs
+		if len(verbs) == 0 {
+			verbs = DefaultMutationVerbs
+		}
+		if !check("mutating event", event.EventName != nil && isMutation(*event.EventName, verbs)) {
+			return results
+		}
+	}
+
+	// Keep only console activity if requested
+	if filters.ConsoleOnly {
+		if !check("console request", details() && consoleRequest(eventDetails)) {
+			return results
+		}
+	}
+
+	// Check classified severity if requested
+	if filters.MinSeverity > classify.SeverityNone {
+		matched := false
+		if event.EventName != nil {
+			classification, ok := filters.Classifier.Classify(*event.EventName)
+			matched = ok && classification.Severity >= filters.MinSeverity
+		}
+		if !check("severity at least "+filters.MinSeverity.String(), matched) {
+			return results
+		}
+	}
+
+	// Check for weak TLS if requested
+	if filters.MinTLS != "" {
+		if !check("TLS older than "+filters.MinTLS, details() && weakTLS(eventDetails, filters.MinTLS)) {
+			return results
+		}
+	}
+
+	// Keep only Insights anomaly events if requested
+	if filters.InsightsOnly {
+		if !check("insight event", details() && isInsightEvent(eventDetails)) {
+			return results
+		}
+	}
+
+	// Check the error message text if requested
+	if filters.ErrorMessage != nil {
+		matched := false
+		if details() {
+			message, _ := eventDetails["errorMessage"].(string)
+			matched = message != "" && filters.ErrorMessage.MatchString(message)
+		}
+		if !check("error message matching "+errorMessagePattern(filters.ErrorMessage), matched) {
+			return results
+		}
+	}
+
+	// Check for errors/success if requested. An event whose body is missing or
+	// can't be parsed can't be shown to have succeeded or failed, so it fails both.
+	if filters.ErrorsOnly || filters.ErrorCode != "" || filters.SuccessOnly {
+		parsedOK := details()
+		errorCode, hasError := "", false
+		if parsedOK {
+			errorCode, hasError = eventDetails["errorCode"].(string)
+		}
+		if filters.ErrorCode != "" {
+			if !check("error code "+filters.ErrorCode, parsedOK && strings.EqualFold(errorCode, filters.ErrorCode)) {
+				return results
 			}
+		} else if filters.ErrorsOnly && !check("errors-only", parsedOK && hasError) {
+			return results
+		}
+		if filters.SuccessOnly && !check("success-only", parsedOK && (!hasError || errorCode == "")) {
+			return results
+		}
+	}
+
+	// Exclusions apply last, so they can carve noisy events out of an --event list
+	if len(filters.ExcludeEvents) > 0 {
+		if !check("not excluded", !eventExcluded(event.EventName, filters.ExcludeEvents)) {
+			return results
 		}
 	}
 
-	return true
+	return results
+}
+
+// lookupPath walks a dotted path (e.g. "userIdentity.type") through nested event
+// details, returning nil when any segment is missing
+func lookupPath(details map[string]interface{}, path string) interface{} {
+	value, _ := walkPath(details, path)
+	return value
+}
+
+// walkPath is lookupPath that also reports whether the final key is present,
+// so a key explicitly set to null still counts as present
+func walkPath(details map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = details
+	for _, segment := range strings.Split(path, ".") {
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = node[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// explainMatch describes the filters a matched event satisfied
+func explainMatch(results []filterResult) string {
+	if len(results) == 0 {
+		return "no filters applied"
+	}
+
+	var matched []string
+	for _, result := range results {
+		matched = append(matched, result.name)
+	}
+	return "matched: " + strings.Join(matched, ", ")
 }
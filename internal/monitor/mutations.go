@@ -0,0 +1,22 @@
+// internal/monitor/mutations.go
+package monitor
+
+import "strings"
+
+// DefaultMutationVerbs are the event name prefixes of calls that change state
+var DefaultMutationVerbs = []string{
+	"Create", "Delete", "Put", "Update", "Attach", "Detach",
+	"Disable", "Enable", "Schedule", "Modify", "Set",
+}
+
+// isMutation reports whether eventName starts with one of verbs. A verb only
+// matches a whole word, so "Set" matches SetAlias but not Settings.
+func isMutation(eventName string, verbs []string) bool {
+	for _, verb := range verbs {
+		rest, ok := strings.CutPrefix(eventName, verb)
+		if ok && (rest == "" || rest[0] >= 'A' && rest[0] <= 'Z') {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+// internal/monitor/mutations_test.go
+package monitor
+
+import "testing"
+
+func TestIsMutation(t *testing.T) {
+	tests := map[string]bool{
+		"CreateKey":           true,
+		"ScheduleKeyDeletion": true,
+		"PutKeyPolicy":        true,
+		"DisableKey":          true,
+		"RunInstances":        false, // not a default verb
+		"SetAlias":            true,
+		"Settings":            false, // the verb must be a whole word
+		"Decrypt":             false,
+		"DescribeKey":         false,
+		"ListAliases":         false,
+		"GetKeyPolicy":        false,
+		"Create":              true,
+	}
+	for name, want := range tests {
+		if got := isMutation(name, DefaultMutationVerbs); got != want {
+			t.Errorf("isMutation(%s) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestMutationsOnlyFilter(t *testing.T) {
+	createKey := newEvent("1", "CreateKey", "alice", 0, nil)
+	decrypt := newEvent("2", "Decrypt", "alice", 0, nil)
+	runInstances := newEvent("3", "RunInstances", "alice", 0, nil)
+
+	if !passes(createKey, FilterOptions{MutationsOnly: true}) || passes(decrypt, FilterOptions{MutationsOnly: true}) {
+		t.Error("default verbs didn't keep CreateKey and drop Decrypt")
+	}
+
+	// A configured list replaces the defaults
+	custom := FilterOptions{MutationsOnly: true, MutationVerbs: []string{"Run", "Terminate"}}
+	if !passes(runInstances, custom) || passes(createKey, custom) {
+		t.Error("custom verbs didn't keep RunInstances and drop CreateKey")
+	}
+	if !passes(decrypt, FilterOptions{MutationVerbs: []string{"Run"}}) {
+		t.Error("verbs filtered events without MutationsOnly")
+	}
+}
@@ -0,0 +1,32 @@
+// internal/monitor/order.go
+package monitor
+
+import "fmt"
+
+// Output orders. LookupEvents returns events most recent first, so newest
+// streams as-is while oldest holds matches until the scan finishes.
+const (
+	OrderNewest = "newest"
+	OrderOldest = "oldest"
+)
+
+// ValidateOrder checks the --order option
+func ValidateOrder(order string) error {
+	if order != OrderNewest && order != OrderOldest {
+		return fmt.Errorf("invalid --order value %q: use %q or %q", order, OrderNewest, OrderOldest)
+	}
+	return nil
+}
+
+// emitOrder lists the positions of n held-back events in output order
+func emitOrder(n int, order string) []int {
+	positions := make([]int, n)
+	for i := range positions {
+		if order == OrderOldest {
+			positions[i] = n - 1 - i
+		} else {
+			positions[i] = i
+		}
+	}
+	return positions
+}
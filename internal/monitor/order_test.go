@@ -0,0 +1,48 @@
+// internal/monitor/order_test.go
+package monitor
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+// Matches the console header of an event, capturing its time
+var consoleTimePattern = regexp.MustCompile(`\[2024-01-15 (\d{2}:\d{2}:\d{2})\]`)
+
+func TestOutputOrder(t *testing.T) {
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"", []string{"00:03:00", "00:02:00", "00:01:00"}}, // as LookupEvents returns them
+		{OrderNewest, []string{"00:03:00", "00:02:00", "00:01:00"}},
+		{OrderOldest, []string{"00:01:00", "00:02:00", "00:03:00"}},
+	}
+	for _, tc := range tests {
+		out, err := scan(t, newestFirst(), FilterOptions{}, OutputOptions{Order: tc.order}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, match := range consoleTimePattern.FindAllStringSubmatch(out, -1) {
+			got = append(got, match[1])
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("order %q printed %v, want %v", tc.order, got, tc.want)
+		}
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	for _, order := range []string{OrderNewest, OrderOldest} {
+		if err := ValidateOrder(order); err != nil {
+			t.Errorf("ValidateOrder(%s) = %v", order, err)
+		}
+	}
+	for _, order := range []string{"", "Oldest", "asc"} {
+		if err := ValidateOrder(order); err == nil {
+			t.Errorf("ValidateOrder(%q) accepted", order)
+		}
+	}
+}
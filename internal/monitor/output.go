@@ -0,0 +1,41 @@
+// internal/monitor/output.go
+package monitor
+
+import (
+	"bytes"
+	"io"
+)
+
+// DeferredWriter holds console output back until Release is called, so a
+// run can decide to print nothing at all (e.g. when there are no matches)
+type DeferredWriter struct {
+	dst      io.Writer
+	buf      bytes.Buffer
+	released bool
+}
+
+func NewDeferredWriter(dst io.Writer) *DeferredWriter {
+	return &DeferredWriter{dst: dst}
+}
+
+func (d *DeferredWriter) Write(p []byte) (int, error) {
+	if d.released {
+		return d.dst.Write(p)
+	}
+	return d.buf.Write(p)
+}
+
+// Release flushes everything held so far and passes later writes straight through
+func (d *DeferredWriter) Release() error {
+	if d.released {
+		return nil
+	}
+	d.released = true
+	_, err := d.buf.WriteTo(d.dst)
+	return err
+}
+
+// Discard drops everything held so far
+func (d *DeferredWriter) Discard() {
+	d.buf.Reset()
+}
@@ -0,0 +1,60 @@
+// internal/monitor/output_test.go
+package monitor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestQuietNoResultsPrintsNothing(t *testing.T) {
+	var console bytes.Buffer
+	output := OutputOptions{QuietNoResults: true, Console: NewDeferredWriter(&console)}
+	trail := &fakeTrail{pages: [][]types.Event{{newEvent("1", "Encrypt", "alice", 1, nil)}}}
+
+	_, err := scan(t, trail, FilterOptions{EventName: "Decrypt"}, output, nil)
+	if !errors.Is(err, ErrNoResults) {
+		t.Errorf("scan = %v, want ErrNoResults", err)
+	}
+	if console.Len() != 0 {
+		t.Errorf("printed output for a zero-match scan:\n%s", console.String())
+	}
+}
+
+func TestQuietNoResultsReleasesOnMatch(t *testing.T) {
+	var console bytes.Buffer
+	output := OutputOptions{QuietNoResults: true, Console: NewDeferredWriter(&console)}
+	trail := &fakeTrail{pages: [][]types.Event{{newEvent("1", "Decrypt", "alice", 1, nil)}}}
+
+	if _, err := scan(t, trail, FilterOptions{EventName: "Decrypt"}, output, nil); err != nil {
+		t.Fatal(err)
+	}
+	// The header printed before the match is kept, ahead of the match itself
+	out := console.String()
+	header, found := strings.Index(out, "- Event Name: Decrypt"), strings.Index(out, "Found 1 matching events")
+	if header < 0 || found < 0 || header > found {
+		t.Errorf("held output wasn't released in order:\n%s", out)
+	}
+}
+
+func TestDeferredWriter(t *testing.T) {
+	var dst bytes.Buffer
+	d := NewDeferredWriter(&dst)
+	d.Write([]byte("dropped\n"))
+	d.Discard()
+	d.Write([]byte("held\n"))
+	if dst.Len() != 0 {
+		t.Fatalf("wrote %q before Release", dst.String())
+	}
+
+	if err := d.Release(); err != nil {
+		t.Fatal(err)
+	}
+	d.Write([]byte("direct\n"))
+	if got := dst.String(); got != "held\ndirect\n" {
+		t.Errorf("output = %q, want held then direct", got)
+	}
+}
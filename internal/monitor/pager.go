@@ -0,0 +1,93 @@
+// internal/monitor/pager.go
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/dhairya13703/cloudtrail-logs/internal/retry"
+)
+
+// eventPager pages through LookupEvents like cloudtrail.LookupEventsPaginator,
+// but exposes the NextToken so a scan can be checkpointed and resumed
+type eventPager struct {
+	client    cloudtrail.LookupEventsAPIClient
+	input     cloudtrail.LookupEventsInput
+	nextToken *string
+	firstPage bool
+
+	// policy retries each call on throttling and other transient errors
+	policy retry.Policy
+
+	// When throttling outlasts the retry policy, wait cooldown and retry the
+	// same page, up to maxResumes times over the whole scan
+	cooldown   time.Duration
+	maxResumes int
+	resumes    int
+	onCooldown func(wait time.Duration, resume, maxResumes int)
+}
+
+// newEventPager starts paging at the given token, or at the beginning when it is empty
+func newEventPager(client cloudtrail.LookupEventsAPIClient, input *cloudtrail.LookupEventsInput, token string) *eventPager {
+	p := &eventPager{client: client, input: *input, firstPage: true, policy: retry.Default}
+	if token != "" {
+		p.nextToken = &token
+	}
+	return p
+}
+
+func (p *eventPager) HasMorePages() bool {
+	return p.firstPage || (p.nextToken != nil && *p.nextToken != "")
+}
+
+func (p *eventPager) NextPage(ctx context.Context) (*cloudtrail.LookupEventsOutput, error) {
+	params := p.input
+	params.NextToken = p.nextToken
+
+	var output *cloudtrail.LookupEventsOutput
+	for {
+		err := retry.Do(ctx, p.policy, func(ctx context.Context) error {
+			var err error
+			output, err = p.client.LookupEvents(ctx, &params)
+			return err
+		})
+		if err == nil {
+			break
+		}
+		if !retry.Throttled(err) || p.cooldown <= 0 || p.resumes >= p.maxResumes {
+			return nil, err
+		}
+
+		// The token hasn't advanced, so the retry resumes at the failed page
+		p.resumes++
+		if p.onCooldown != nil {
+			p.onCooldown(p.cooldown, p.resumes, p.maxResumes)
+		}
+		timer := time.NewTimer(p.cooldown)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	p.firstPage = false
+	p.nextToken = output.NextToken
+	return output, nil
+}
+
+// throttleCooldown lets the pager sit out sustained throttling instead of
+// failing the scan
+func (p *eventPager) throttleCooldown(wait time.Duration, maxResumes int, notify func(wait time.Duration, resume, maxResumes int)) {
+	p.cooldown, p.maxResumes, p.onCooldown = wait, maxResumes, notify
+}
+
+// NextToken returns the token for the page that will be fetched next
+func (p *eventPager) NextToken() string {
+	if p.nextToken == nil {
+		return ""
+	}
+	return *p.nextToken
+}
@@ -0,0 +1,140 @@
+// internal/monitor/pager_test.go
+package monitor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/smithy-go"
+	"github.com/dhairya13703/cloudtrail-logs/internal/retry"
+)
+
+var errThrottled = &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+
+// singleAttemptPager pages through trail without per-call retries, so every
+// throttle reaches the cooldown
+func singleAttemptPager(trail *fakeTrail) *eventPager {
+	p := newEventPager(trail, &cloudtrail.LookupEventsInput{}, "")
+	p.policy = retry.Policy{MaxAttempts: 1}
+	return p
+}
+
+// quickPager pages through trail retrying each call up to attempts times
+// with millisecond backoffs
+func quickPager(trail *fakeTrail, attempts int) *eventPager {
+	p := newEventPager(trail, &cloudtrail.LookupEventsInput{}, "")
+	p.policy = retry.Policy{MaxAttempts: attempts, BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond}
+	return p
+}
+
+func TestNextPageRetriesThrottling(t *testing.T) {
+	trail := &fakeTrail{pages: threePages(), errs: []error{errThrottled, errThrottled}}
+	p := quickPager(trail, 3)
+	output, err := p.NextPage(context.Background())
+	if err != nil {
+		t.Fatalf("NextPage = %v, want success on the third attempt", err)
+	}
+	if len(output.Events) != 1 || *output.Events[0].EventId != "1" || trail.calls != 3 {
+		t.Errorf("got %d events after %d calls, want the first page after 3", len(output.Events), trail.calls)
+	}
+	for i, input := range trail.inputs {
+		if input.NextToken != nil {
+			t.Errorf("attempt %d asked for %s, want the first page each time", i+1, *input.NextToken)
+		}
+	}
+
+	// Paging continues normally after the retried call
+	if output, err = p.NextPage(context.Background()); err != nil || *output.Events[0].EventId != "2" {
+		t.Errorf("next page = %v, %v; want page 2", output, err)
+	}
+
+	// A third throttle exhausts the attempts
+	trail = &fakeTrail{pages: threePages(), errs: []error{errThrottled, errThrottled, errThrottled}}
+	if _, err := quickPager(trail, 3).NextPage(context.Background()); !errors.Is(err, errThrottled) || trail.calls != 3 {
+		t.Errorf("NextPage = %v after %d calls, want the throttle after 3", err, trail.calls)
+	}
+}
+
+func TestThrottleCooldownResumesSamePage(t *testing.T) {
+	trail := &fakeTrail{pages: threePages(), errs: []error{nil, errThrottled, errThrottled}}
+	p := singleAttemptPager(trail)
+	var cooldowns []int
+	p.throttleCooldown(time.Millisecond, 3, func(wait time.Duration, resume, maxResumes int) {
+		cooldowns = append(cooldowns, resume)
+	})
+
+	var ids []string
+	for p.HasMorePages() {
+		output, err := p.NextPage(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, event := range output.Events {
+			ids = append(ids, *event.EventId)
+		}
+	}
+	if strings.Join(ids, " ") != "1 2 3" {
+		t.Errorf("events = %v, want each page once", ids)
+	}
+	if len(cooldowns) != 2 || cooldowns[1] != 2 {
+		t.Errorf("cooldowns = %v, want 2", cooldowns)
+	}
+	// Both retries of the throttled page asked for it by its token
+	for _, call := range []int{1, 2, 3} {
+		if token := trail.inputs[call].NextToken; token == nil || *token != "page-1" {
+			t.Errorf("call %d resumed at %v, want page-1", call+1, token)
+		}
+	}
+}
+
+func TestThrottleCooldownGivesUp(t *testing.T) {
+	trail := &fakeTrail{pages: threePages(), errs: []error{errThrottled, errThrottled}}
+	p := singleAttemptPager(trail)
+	p.throttleCooldown(time.Millisecond, 1, nil)
+	if _, err := p.NextPage(context.Background()); !errors.Is(err, errThrottled) {
+		t.Errorf("NextPage = %v, want the throttle once resumes run out", err)
+	}
+
+	// Without a cooldown, the first exhausted retry fails
+	trail = &fakeTrail{pages: threePages(), errs: []error{errThrottled}}
+	if _, err := singleAttemptPager(trail).NextPage(context.Background()); !errors.Is(err, errThrottled) {
+		t.Errorf("NextPage = %v, want the throttle", err)
+	}
+
+	// Only throttling is waited out
+	denied := &smithy.GenericAPIError{Code: "AccessDeniedException"}
+	trail = &fakeTrail{pages: threePages(), errs: []error{denied}}
+	p = singleAttemptPager(trail)
+	p.throttleCooldown(time.Millisecond, 3, nil)
+	if _, err := p.NextPage(context.Background()); !errors.Is(err, denied) || trail.calls != 1 {
+		t.Errorf("NextPage = %v after %d calls, want AccessDenied at once", err, trail.calls)
+	}
+}
+
+func TestThrottleCooldownCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	trail := &fakeTrail{pages: threePages(), errs: []error{errThrottled}}
+	p := singleAttemptPager(trail)
+	p.throttleCooldown(time.Hour, 3, func(time.Duration, int, int) { cancel() })
+	if _, err := p.NextPage(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("NextPage = %v, want the cooldown cut short", err)
+	}
+}
+
+func TestScanWaitsOutThrottling(t *testing.T) {
+	trail := &fakeTrail{pages: threePages(), errs: []error{nil, errThrottled}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{MaxAttempts: 1, ThrottleCooldown: time.Millisecond, ThrottleResumes: 2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Throttled by CloudTrail: pausing 1ms before resuming (1/2)") {
+		t.Errorf("cooldown not reported:\n%s", out)
+	}
+	if !strings.Contains(out, "Found 3 matching events") {
+		t.Errorf("events lost across the cooldown:\n%s", out)
+	}
+}
@@ -0,0 +1,67 @@
+// internal/monitor/progress.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/terminal"
+)
+
+// How often a plain progress line is printed when stderr isn't a terminal
+const plainProgressInterval = 30 * time.Second
+
+// progress reports how far a scan has got on stderr. On a terminal a single
+// line is redrawn after every page; elsewhere a plain line is printed at most
+// every plainProgressInterval so logs aren't flooded.
+type progress struct {
+	out         io.Writer
+	interactive bool
+	started     time.Time
+	lastPrinted time.Time
+	drawn       bool // a redrawable line is on screen
+
+	pages, scanned, matched int
+}
+
+func newProgress() *progress {
+	now := time.Now()
+	return &progress{
+		out:         os.Stderr,
+		interactive: terminal.Interactive(os.Stderr),
+		started:     now,
+		lastPrinted: now,
+	}
+}
+
+// page records a fetched page and its counts so far, then shows them
+func (p *progress) page(scanned, matched int) {
+	p.pages++
+	p.scanned += scanned
+	p.matched = matched
+
+	if p.interactive {
+		fmt.Fprintf(p.out, "\r\033[K%s", p.line())
+		p.drawn = true
+		return
+	}
+	if time.Since(p.lastPrinted) >= plainProgressInterval {
+		fmt.Fprintln(p.out, p.line())
+		p.lastPrinted = time.Now()
+	}
+}
+
+// clear removes the redrawable line so other output starts on a clean line
+func (p *progress) clear() {
+	if p.drawn {
+		fmt.Fprint(p.out, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+func (p *progress) line() string {
+	return fmt.Sprintf("Scanning: %d pages, %d events scanned, %d matched (%s)",
+		p.pages, p.scanned, p.matched, time.Since(p.started).Round(time.Second))
+}
@@ -0,0 +1,53 @@
+// internal/monitor/progress_test.go
+package monitor
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgressPlainWhenPiped(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	stderr := os.Stderr
+	os.Stderr = w
+	p := newProgress()
+	os.Stderr = stderr
+
+	if p.interactive {
+		t.Fatal("progress on a piped stderr is interactive")
+	}
+	var out bytes.Buffer
+	p.out = &out
+
+	// No redrawn line per page, and nothing at all until the interval passes
+	p.page(50, 2)
+	p.clear()
+	if out.Len() != 0 {
+		t.Errorf("printed %q before the plain interval", out.String())
+	}
+
+	p.lastPrinted = time.Now().Add(-plainProgressInterval)
+	p.page(50, 3)
+	got := out.String()
+	if strings.ContainsAny(got, "\r\033") || !strings.HasPrefix(got, "Scanning: 2 pages, 100 events scanned, 3 matched") || !strings.HasSuffix(got, "\n") {
+		t.Errorf("plain progress = %q", got)
+	}
+}
+
+func TestProgressRedrawsOnTerminal(t *testing.T) {
+	var out bytes.Buffer
+	p := &progress{out: &out, interactive: true, started: time.Now()}
+	p.page(10, 1)
+	p.clear()
+	if got := out.String(); got != "\r\033[KScanning: 1 pages, 10 events scanned, 1 matched (0s)\r\033[K" {
+		t.Errorf("redrawn progress = %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+// internal/monitor/regions.go
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// DefaultRegionConcurrency bounds how many regions are paged through at once
+// unless OutputOptions.RegionConcurrency says otherwise
+const DefaultRegionConcurrency = 4
+
+// regionPage is one LookupEvents page from one region's scan, or the error
+// that ended that region's scan
+type regionPage struct {
+	region    string
+	output    *cloudtrail.LookupEventsOutput
+	nextToken string // where the region's scan continues after this page
+	err       error
+}
+
+// scanRegions returns the regions the monitor looks up events in
+func (m *Monitor) scanRegions() []string {
+	if len(m.client.Regions) > 0 {
+		return m.client.Regions
+	}
+	return []string{m.client.Region}
+}
+
+// regionConcurrency returns how many regions are paged through at once
+func (m *Monitor) regionConcurrency() int {
+	if m.output.RegionConcurrency > 0 {
+		return m.output.RegionConcurrency
+	}
+	return DefaultRegionConcurrency
+}
+
+// cloudTrailIn returns the client LookupEvents is called on in region
+func (m *Monitor) cloudTrailIn(region string) cloudtrail.LookupEventsAPIClient {
+	if m.lookupClient != nil {
+		return m.lookupClient(region)
+	}
+	return m.client.CloudTrailIn(region)
+}
+
+// multiRegion reports whether events are tagged with the region they came from
+func (m *Monitor) multiRegion() bool {
+	return m.client != nil && len(m.client.Regions) > 1
+}
+
+// fetchPages pages through LookupEvents in each region, at most
+// regionConcurrency regions at a time, and delivers the pages on the returned
+// channel, which is closed once every region is done. Pages from one region
+// arrive in order; pages from different regions interleave. resumeToken
+// continues a single-region scan. Cancel ctx to stop early.
+func (m *Monitor) fetchPages(ctx context.Context, input *cloudtrail.LookupEventsInput, regions []string, resumeToken string) <-chan regionPage {
+	pages := make(chan regionPage)
+	limiter := newScanLimiter(m.regionConcurrency())
+	var wg sync.WaitGroup
+
+	for _, region := range regions {
+		wg.Add(1)
+		go func(region string) {
+			defer wg.Done()
+			if !limiter.acquire(ctx) {
+				return
+			}
+			defer limiter.release()
+
+			paginator := newEventPager(m.cloudTrailIn(region), input, resumeToken)
+			if m.output.MaxAttempts > 0 {
+				paginator.policy.MaxAttempts = m.output.MaxAttempts
+			}
+			paginator.throttleCooldown(m.output.ThrottleCooldown, m.output.ThrottleResumes, func(wait time.Duration, resume, maxResumes int) {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+				if m.progress != nil {
+					m.progress.clear()
+				}
+				label := ""
+				if len(regions) > 1 {
+					label = " in " + region
+				}
+				fmt.Fprintf(m.out, theme.Warning("Throttled by CloudTrail%s: pausing %s before resuming (%d/%d)\n"), label, wait, resume, maxResumes)
+			})
+
+			for paginator.HasMorePages() {
+				output, err := paginator.NextPage(ctx)
+				page := regionPage{region: region, output: output, nextToken: paginator.NextToken(), err: err}
+				select {
+				case pages <- page:
+				case <-ctx.Done():
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}(region)
+	}
+
+	go func() {
+		wg.Wait()
+		close(pages)
+	}()
+	return pages
+}
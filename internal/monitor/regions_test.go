@@ -0,0 +1,159 @@
+// internal/monitor/regions_test.go
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+// countingLookup returns a single empty page per call and records how many
+// calls were in flight at once
+type countingLookup struct {
+	mu           sync.Mutex
+	active, peak int
+	calls        map[string]int
+}
+
+func (c *countingLookup) client(region string) cloudtrail.LookupEventsAPIClient {
+	return lookupFunc(func(ctx context.Context, input *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error) {
+		c.mu.Lock()
+		c.active++
+		if c.active > c.peak {
+			c.peak = c.active
+		}
+		c.calls[region]++
+		c.mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		c.mu.Lock()
+		c.active--
+		c.mu.Unlock()
+		return &cloudtrail.LookupEventsOutput{}, nil
+	})
+}
+
+// lookupFunc adapts a function to cloudtrail.LookupEventsAPIClient
+type lookupFunc func(ctx context.Context, input *cloudtrail.LookupEventsInput) (*cloudtrail.LookupEventsOutput, error)
+
+func (f lookupFunc) LookupEvents(ctx context.Context, input *cloudtrail.LookupEventsInput, _ ...func(*cloudtrail.Options)) (*cloudtrail.LookupEventsOutput, error) {
+	return f(ctx, input)
+}
+
+func TestFetchPagesBoundsRegionConcurrency(t *testing.T) {
+	regions := make([]string, 10)
+	for i := range regions {
+		regions[i] = fmt.Sprintf("region-%d", i)
+	}
+
+	for _, tc := range []struct {
+		setting, want int
+	}{
+		{setting: 3, want: 3},
+		{setting: 1, want: 1},
+		{setting: 0, want: DefaultRegionConcurrency},
+	} {
+		t.Run(fmt.Sprintf("limit %d", tc.setting), func(t *testing.T) {
+			fake := &countingLookup{calls: make(map[string]int)}
+			m := &Monitor{
+				output:       OutputOptions{RegionConcurrency: tc.setting},
+				out:          io.Discard,
+				lookupClient: fake.client,
+			}
+
+			delivered := make(map[string]int)
+			for page := range m.fetchPages(context.Background(), &cloudtrail.LookupEventsInput{}, regions, "") {
+				if page.err != nil {
+					t.Fatalf("unexpected error from %s: %v", page.region, page.err)
+				}
+				delivered[page.region]++
+			}
+
+			for _, region := range regions {
+				if delivered[region] != 1 || fake.calls[region] != 1 {
+					t.Errorf("%s: got %d pages from %d calls, want 1 of each", region, delivered[region], fake.calls[region])
+				}
+			}
+			if fake.peak > tc.want {
+				t.Errorf("%d regions scanned at once, want at most %d", fake.peak, tc.want)
+			}
+			if tc.want > 1 && fake.peak < 2 {
+				t.Errorf("regions were scanned one at a time with a limit of %d", tc.want)
+			}
+		})
+	}
+}
+
+// regionEvent is an event whose body carries its id, so it can be found in a log
+func regionEvent(id, name, user string, minute int) types.Event {
+	return newEvent(id, name, user, minute, map[string]interface{}{"eventID": id})
+}
+
+func TestMultiRegionScan(t *testing.T) {
+	trails := map[string]*fakeTrail{
+		"us-east-1": {pages: [][]types.Event{{regionEvent("east-1", "Decrypt", "alice", 1), regionEvent("east-2", "Encrypt", "alice", 3)}}},
+		"eu-west-1": {pages: [][]types.Event{{regionEvent("west-1", "Decrypt", "bob", 2)}}},
+	}
+	var console bytes.Buffer
+	dir := t.TempDir()
+	client := &aws.AWSClient{Region: "us-east-1", Regions: []string{"us-east-1", "eu-west-1"}, Profile: "test"}
+	export := &writer.ExportOptions{Format: writer.FormatNDJSON, RegionDirs: true, FilenameTemplate: "events.log"}
+	m := NewKMSMonitor(client, dir, export, &OutputOptions{Console: &console, Summary: true})
+	m.lookupClient = func(region string) cloudtrail.LookupEventsAPIClient { return trails[region] }
+	if err := m.MonitorKMSEvents(context.Background(), FilterOptions{}, testStart, testStart.Add(24*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	out := console.String()
+
+	if !strings.Contains(out, "Found 3 matching events") {
+		t.Errorf("want the events of both regions:\n%s", out)
+	}
+	if !strings.Contains(out, "  Region: eu-west-1") || !strings.Contains(out, "  Region: us-east-1") {
+		t.Errorf("events not tagged with their region:\n%s", out)
+	}
+	if !strings.Contains(out, "  Region     Count\n  us-east-1  2\n  eu-west-1  1\n") {
+		t.Errorf("summary not aggregated across regions:\n%s", out)
+	}
+
+	// Each region's events are logged to that region's file, tagged with it
+	for region, want := range map[string][]string{"us-east-1": {"east-1", "east-2"}, "eu-west-1": {"west-1"}} {
+		data, err := os.ReadFile(filepath.Join(dir, "kms", region, "events.log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var record struct {
+				Region  string `json:"region"`
+				Details struct {
+					EventID string `json:"eventID"`
+				} `json:"details"`
+			}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatal(err)
+			}
+			if record.Region != region {
+				t.Errorf("%s file has an event tagged %q", region, record.Region)
+			}
+			ids = append(ids, record.Details.EventID)
+		}
+		if !reflect.DeepEqual(ids, want) {
+			t.Errorf("%s file has events %v, want %v", region, ids, want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// internal/monitor/response.go
+package monitor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateResponseParam checks a --response-param key=value filter
+func ValidateResponseParam(filter string) error {
+	key, _, ok := strings.Cut(filter, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("invalid --response-param %q: use key=value, e.g. keyMetadata.keyId=1234abcd-...", filter)
+	}
+	return nil
+}
+
+// responseParamMatches reports whether the dotted key of a key=value filter
+// holds value in responseElements. Numbers and booleans compare by their
+// printed form.
+func responseParamMatches(details map[string]interface{}, filter string) bool {
+	key, want, _ := strings.Cut(filter, "=")
+	value, ok := walkPath(details, "responseElements."+key)
+	if !ok || value == nil {
+		return false
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		return false
+	}
+	return fmt.Sprint(value) == want
+}
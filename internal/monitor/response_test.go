@@ -0,0 +1,56 @@
+// internal/monitor/response_test.go
+package monitor
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestResponseParamFilter(t *testing.T) {
+	keyID := "1234abcd-12ab-34cd-56ef-1234567890ab"
+	createKey := newEvent("1", "CreateKey", "alice", 0, map[string]interface{}{
+		"responseElements": map[string]interface{}{
+			"keyMetadata": map[string]interface{}{
+				"keyId":       keyID,
+				"enabled":     true,
+				"keyUsage":    "ENCRYPT_DECRYPT",
+				"aliasNames":  []interface{}{"alias/payments"},
+				"creationDay": float64(15),
+			},
+		},
+		"requestParameters": map[string]interface{}{"keyId": "other"},
+	})
+	noResponse := newEvent("2", "Decrypt", "alice", 0, map[string]interface{}{"responseElements": nil})
+
+	tests := []struct {
+		filter string
+		event  types.Event
+		want   bool
+	}{
+		{"keyMetadata.keyId=" + keyID, createKey, true},
+		{"keyMetadata.keyId=0000", createKey, false},
+		{"keyMetadata.enabled=true", createKey, true},
+		{"keyMetadata.creationDay=15", createKey, true},
+		{"keyMetadata.aliasNames=alias/payments", createKey, false}, // only scalars compare
+		{"keyMetadata=" + keyID, createKey, false},
+		{"keyId=other", createKey, false}, // request parameters aren't searched
+		{"keyMetadata.keyId=" + keyID, noResponse, false},
+	}
+	for _, tc := range tests {
+		if got := passes(tc.event, FilterOptions{ResponseParam: tc.filter}); got != tc.want {
+			t.Errorf("response-param %q on event %s: got %v, want %v", tc.filter, *tc.event.EventId, got, tc.want)
+		}
+	}
+}
+
+func TestValidateResponseParam(t *testing.T) {
+	if err := ValidateResponseParam("keyMetadata.keyId="); err != nil {
+		t.Errorf("ValidateResponseParam with an empty value = %v", err)
+	}
+	for _, filter := range []string{"keyMetadata.keyId", "=value", ""} {
+		if err := ValidateResponseParam(filter); err == nil {
+			t.Errorf("ValidateResponseParam(%q) accepted", filter)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// internal/monitor/sample.go
+package monitor
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// sampler keeps a reproducible fraction of matched events. Rather than drawing
+// from a shared random stream, each event is scored by hashing the seed with its
+// EventId, so the same seed selects the same subset regardless of page order.
+type sampler struct {
+	rate float64
+	seed int64
+}
+
+func newSampler(rate float64, seed int64) *sampler {
+	return &sampler{rate: rate, seed: seed}
+}
+
+func (s *sampler) keep(event types.Event) bool {
+	if s.rate >= 1 {
+		return true
+	}
+
+	h := fnv.New64a()
+	var seedBytes [8]byte
+	binary.BigEndian.PutUint64(seedBytes[:], uint64(s.seed))
+	h.Write(seedBytes[:])
+	if event.EventId != nil {
+		h.Write([]byte(*event.EventId))
+	} else {
+		h.Write([]byte(SafeString(event.CloudTrailEvent)))
+	}
+
+	// FNV's high bits barely change between similar ids, so mix them in
+	// (the murmur3 finalizer) before mapping the hash onto [0, 1)
+	sum := h.Sum64()
+	sum ^= sum >> 33
+	sum *= 0xff51afd7ed558ccd
+	sum ^= sum >> 33
+	sum *= 0xc4ceb9fe1a85ec53
+	sum ^= sum >> 33
+	score := float64(sum>>11) / float64(1<<53)
+	return score < s.rate
+}
@@ -0,0 +1,65 @@
+// internal/monitor/sample_test.go
+package monitor
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func sampleEvents(n int) []types.Event {
+	events := make([]types.Event, n)
+	for i := range events {
+		events[i] = newEvent(fmt.Sprintf("event-%d", i), "Decrypt", "alice", i, nil)
+	}
+	return events
+}
+
+// kept returns the ids of the events the sampler keeps
+func kept(s *sampler, events []types.Event) []string {
+	var ids []string
+	for _, event := range events {
+		if s.keep(event) {
+			ids = append(ids, *event.EventId)
+		}
+	}
+	return ids
+}
+
+func TestSamplerSameSeedSameSubset(t *testing.T) {
+	events := sampleEvents(1000)
+
+	first := kept(newSampler(0.1, 42), events)
+	second := kept(newSampler(0.1, 42), events)
+	if !reflect.DeepEqual(first, second) {
+		t.Error("the same seed selected different events")
+	}
+
+	// Page order doesn't change which events are kept
+	reversed := make([]types.Event, len(events))
+	for i, event := range events {
+		reversed[len(events)-1-i] = event
+	}
+	backwards := kept(newSampler(0.1, 42), reversed)
+	if len(backwards) != len(first) {
+		t.Errorf("kept %d events in reverse order, want %d", len(backwards), len(first))
+	}
+
+	if other := kept(newSampler(0.1, 7), events); reflect.DeepEqual(first, other) {
+		t.Error("different seeds selected the same events")
+	}
+
+	// Roughly the requested fraction is kept
+	if len(first) < 50 || len(first) > 150 {
+		t.Errorf("kept %d of 1000 events at rate 0.1", len(first))
+	}
+}
+
+func TestSamplerFullRate(t *testing.T) {
+	events := sampleEvents(50)
+	if got := kept(newSampler(1, 0), events); len(got) != len(events) {
+		t.Errorf("rate 1 kept %d of %d events", len(got), len(events))
+	}
+}
@@ -0,0 +1,92 @@
+// internal/monitor/sequence_test.go
+package monitor
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+// newestFirst spreads three events over two pages, newest first as
+// CloudTrail returns them
+func newestFirst() *fakeTrail {
+	return &fakeTrail{pages: [][]types.Event{
+		{newEvent("c", "Decrypt", "alice", 3, nil), newEvent("b", "Decrypt", "alice", 2, nil)},
+		{newEvent("a", "Decrypt", "alice", 1, nil)},
+	}}
+}
+
+// loggedIndices lists each ndjson record as "<time>#<index>"
+func loggedIndices(t *testing.T, path string) []string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var got []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var record struct {
+			Index     int    `json:"index"`
+			Timestamp string `json:"timestamp"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("invalid record %q: %v", scanner.Text(), err)
+		}
+		got = append(got, fmt.Sprintf("%s#%d", strings.TrimPrefix(record.Timestamp, "2024-01-15 "), record.Index))
+	}
+	return got
+}
+
+func TestSequentialIndices(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "events.ndjson")
+	export := &writer.ExportOptions{Filename: logFile, Format: writer.FormatNDJSON}
+	out, err := scan(t, newestFirst(), FilterOptions{}, OutputOptions{ShowIndex: true}, export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Numbered in match order, continuing across pages
+	want := []string{"00:03:00#1", "00:02:00#2", "00:01:00#3"}
+	if got := loggedIndices(t, logFile); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("logged indices = %v, want %v", got, want)
+	}
+	first, second, third := strings.Index(out, "#1 [2024-01-15 00:03:00]"), strings.Index(out, "#2 [2024-01-15 00:02:00]"), strings.Index(out, "#3 [2024-01-15 00:01:00]")
+	if first < 0 || second < first || third < second {
+		t.Errorf("console indices aren't sequential:\n%s", out)
+	}
+}
+
+func TestIndicesFollowOldestFirstOrder(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "events.ndjson")
+	export := &writer.ExportOptions{Filename: logFile, Format: writer.FormatNDJSON}
+	_, err := scan(t, newestFirst(), FilterOptions{}, OutputOptions{ShowIndex: true, Order: OrderOldest}, export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"00:01:00#1", "00:02:00#2", "00:03:00#3"}
+	if got := loggedIndices(t, logFile); strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("logged indices = %v, want %v", got, want)
+	}
+}
+
+func TestNoIndicesByDefault(t *testing.T) {
+	out, err := scan(t, newestFirst(), FilterOptions{}, OutputOptions{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "#1 ") {
+		t.Errorf("indices shown without ShowIndex:\n%s", out)
+	}
+}
@@ -0,0 +1,60 @@
+// internal/monitor/severity.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/classify"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// severityColor picks the console color for a classified event
+func severityColor(severity classify.Severity) func(a ...interface{}) string {
+	switch {
+	case severity >= classify.SeverityHigh:
+		return theme.Error
+	case severity == classify.SeverityMedium:
+		return theme.Warning
+	default:
+		return theme.Success
+	}
+}
+
+// severityAlerts counts matched events at or above the alert severity
+type severityAlerts struct {
+	threshold classify.Severity
+	counts    map[string]int
+}
+
+func newSeverityAlerts(threshold classify.Severity) *severityAlerts {
+	return &severityAlerts{threshold: threshold, counts: make(map[string]int)}
+}
+
+func (s *severityAlerts) add(eventName string, classification classify.Classification) {
+	if classification.Severity >= s.threshold {
+		s.counts[eventName]++
+	}
+}
+
+// report prints the alerting events and reports whether there were any
+func (s *severityAlerts) report(out io.Writer) bool {
+	if len(s.counts) == 0 {
+		return false
+	}
+
+	names := make([]string, 0, len(s.counts))
+	total := 0
+	for name, count := range s.counts {
+		names = append(names, name)
+		total += count
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(out, theme.Error(fmt.Sprintf("\nAlert: %d events at or above %s severity:", total, s.threshold)))
+	for _, name := range names {
+		fmt.Fprintf(out, "  - %s: %d events\n", name, s.counts[name])
+	}
+	return true
+}
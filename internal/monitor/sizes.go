@@ -0,0 +1,68 @@
+// internal/monitor/sizes.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/bytesize"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+)
+
+// sizeStats measures the raw CloudTrailEvent JSON of matched events, for
+// capacity planning of downstream ingestion
+type sizeStats struct {
+	sizes   []int
+	total   int64
+	largest types.Event
+}
+
+func newSizeStats() *sizeStats {
+	return &sizeStats{}
+}
+
+func (s *sizeStats) add(event types.Event) {
+	size := 0
+	if event.CloudTrailEvent != nil {
+		size = len(*event.CloudTrailEvent)
+	}
+	if len(s.sizes) == 0 || size > s.max() {
+		s.largest = event
+	}
+	s.sizes = append(s.sizes, size)
+	s.total += int64(size)
+}
+
+func (s *sizeStats) max() int {
+	largest := 0
+	if s.largest.CloudTrailEvent != nil {
+		largest = len(*s.largest.CloudTrailEvent)
+	}
+	return largest
+}
+
+// median of the measured sizes; the mean of the middle two for an even count
+func (s *sizeStats) median() float64 {
+	sorted := append([]int(nil), s.sizes...)
+	sort.Ints(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return float64(sorted[middle-1]+sorted[middle]) / 2
+	}
+	return float64(sorted[middle])
+}
+
+func (s *sizeStats) report(out io.Writer) {
+	if len(s.sizes) == 0 {
+		return
+	}
+	fmt.Fprintln(out, theme.Info("\nEvent size stats (raw CloudTrail JSON):"))
+	fmt.Fprintf(out, "  Total:   %s (%d bytes)\n", bytesize.Format(s.total), s.total)
+	fmt.Fprintf(out, "  Average: %.0f bytes\n", float64(s.total)/float64(len(s.sizes)))
+	fmt.Fprintf(out, "  Median:  %.0f bytes\n", s.median())
+	fmt.Fprintf(out, "  Largest: %d bytes (%s %s at %s)\n", s.max(),
+		SafeString(s.largest.EventName), SafeString(s.largest.EventId), timeutil.FormatTime(*s.largest.EventTime))
+}
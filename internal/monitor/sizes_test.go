@@ -0,0 +1,62 @@
+// internal/monitor/sizes_test.go
+package monitor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// sizedEvent has a raw CloudTrailEvent of exactly size bytes
+func sizedEvent(id string, minute, size int) types.Event {
+	event := newEvent(id, "Decrypt", "alice", minute, nil)
+	body := `{"pad":"` + strings.Repeat("x", size-10) + `"}`
+	event.CloudTrailEvent = &body
+	return event
+}
+
+func TestSizeStats(t *testing.T) {
+	stats := newSizeStats()
+	for i, size := range []int{100, 400, 200, 300} {
+		stats.add(sizedEvent(string(rune('a'+i)), i, size))
+	}
+
+	var out bytes.Buffer
+	stats.report(&out)
+	for _, want := range []string{
+		"(1000 bytes)",
+		"Average: 250 bytes",
+		"Median:  250 bytes", // the mean of 200 and 300
+		"Largest: 400 bytes (Decrypt b at 2024-01-15 00:01:00)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, out.String())
+		}
+	}
+
+	stats.add(sizedEvent("e", 4, 50))
+	if median := stats.median(); median != 200 {
+		t.Errorf("median of an odd count = %v, want 200", median)
+	}
+}
+
+func TestSizeStatsEmpty(t *testing.T) {
+	var out bytes.Buffer
+	newSizeStats().report(&out)
+	if out.Len() != 0 {
+		t.Errorf("reported stats with no events:\n%s", out.String())
+	}
+}
+
+func TestSizeStatsInScan(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{sizedEvent("1", 1, 120), sizedEvent("2", 2, 80)}}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{SizeStats: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "(200 bytes)") || !strings.Contains(out, "Largest: 120 bytes (Decrypt 1") {
+		t.Errorf("size stats don't cover the matched events:\n%s", out)
+	}
+}
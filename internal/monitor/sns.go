@@ -0,0 +1,23 @@
+// internal/monitor/sns.go
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/aws"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+const SNSEventSource = "sns.amazonaws.com"
+
+func NewSNSMonitor(client *aws.AWSClient, outputDir string, exportOptions *writer.ExportOptions, outputOptions *OutputOptions) *Monitor {
+	return newMonitor(client, outputDir, "sns", exportOptions, outputOptions)
+}
+
+// MonitorSNSEvents reports SNS API calls matching filters, optionally
+// narrowed to one topic with filters.TopicARN
+func (m *Monitor) MonitorSNSEvents(ctx context.Context, filters FilterOptions, start, end time.Time) error {
+	filters.EventSource = SNSEventSource
+	return m.monitorEvents(ctx, filters, start, end)
+}
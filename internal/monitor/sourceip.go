@@ -0,0 +1,37 @@
+// internal/monitor/sourceip.go
+package monitor
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ValidateSourceIP checks a --source-ip filter: an IPv4/IPv6 address or CIDR
+func ValidateSourceIP(filter string) error {
+	if strings.Contains(filter, "/") {
+		if _, _, err := net.ParseCIDR(filter); err != nil {
+			return fmt.Errorf("invalid --source-ip CIDR %q: %v", filter, err)
+		}
+		return nil
+	}
+	if net.ParseIP(filter) == nil {
+		return fmt.Errorf("invalid --source-ip %q: use an IP address (e.g. 203.0.113.7) or CIDR (e.g. 10.0.0.0/8)", filter)
+	}
+	return nil
+}
+
+// sourceIPMatches reports whether the event's sourceIPAddress equals the
+// filter address or falls inside the filter CIDR. Service principals (e.g.
+// "kms.amazonaws.com") recorded in place of an address never match.
+func sourceIPMatches(details map[string]interface{}, filter string) bool {
+	source, _ := details["sourceIPAddress"].(string)
+	ip := net.ParseIP(source)
+	if ip == nil {
+		return false
+	}
+	if _, network, err := net.ParseCIDR(filter); err == nil {
+		return network.Contains(ip)
+	}
+	return ip.Equal(net.ParseIP(filter))
+}
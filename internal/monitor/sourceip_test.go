@@ -0,0 +1,61 @@
+// internal/monitor/sourceip_test.go
+package monitor
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// fromAddress is a call recorded from source, or with no sourceIPAddress when it's empty
+func fromAddress(id, source string) types.Event {
+	body := map[string]interface{}{"eventName": "Decrypt"}
+	if source != "" {
+		body["sourceIPAddress"] = source
+	}
+	return newEvent(id, "Decrypt", "alice", 0, body)
+}
+
+func TestSourceIPFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		source string
+		want   bool
+	}{
+		{"203.0.113.7", "203.0.113.7", true},
+		{"203.0.113.7", "203.0.113.8", false},
+		{"10.0.0.0/8", "10.20.30.40", true},
+		{"10.0.0.0/8", "11.0.0.1", false},
+		{"192.168.1.0/24", "192.168.1.255", true},
+		{"2001:db8::1", "2001:db8::1", true},
+		{"2001:db8::1", "2001:0db8:0000::0001", true}, // same address, longer spelling
+		{"2001:db8::1", "2001:db8::2", false},
+		{"2001:db8::/32", "2001:db8:ffff::1", true},
+		{"2001:db8::/32", "2001:db9::1", false},
+		{"10.0.0.0/8", "kms.amazonaws.com", false}, // a service principal, not an address
+		{"203.0.113.7", "", false},                 // no sourceIPAddress
+	}
+	for _, tc := range tests {
+		if got := passes(fromAddress("1", tc.source), FilterOptions{SourceIP: tc.filter}); got != tc.want {
+			t.Errorf("--source-ip %s on %q: got %v, want %v", tc.filter, tc.source, got, tc.want)
+		}
+	}
+
+	bodiless := newEvent("2", "Decrypt", "alice", 0, nil)
+	if passes(bodiless, FilterOptions{SourceIP: "10.0.0.0/8"}) {
+		t.Error("an event without a body matched --source-ip")
+	}
+}
+
+func TestValidateSourceIP(t *testing.T) {
+	for _, filter := range []string{"203.0.113.7", "10.0.0.0/8", "2001:db8::1", "2001:db8::/32"} {
+		if err := ValidateSourceIP(filter); err != nil {
+			t.Errorf("ValidateSourceIP(%s) = %v", filter, err)
+		}
+	}
+	for _, filter := range []string{"203.0.113", "10.0.0.0/33", "example.com", "2001:db8::/129"} {
+		if err := ValidateSourceIP(filter); err == nil {
+			t.Errorf("ValidateSourceIP(%s) accepted it", filter)
+		}
+	}
+}
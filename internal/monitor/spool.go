@@ -0,0 +1,184 @@
+// internal/monitor/spool.go
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// Parsed event bodies take several times the size of the raw JSON in memory
+const parsedSizeFactor = 4
+
+// spool holds matched events that are printed after the scan. Once their
+// estimated size passes limit, everything is moved to a temp file and later
+// events are appended there, so large result sets don't have to fit in memory.
+type spool struct {
+	limit int64 // bytes; 0 keeps everything in memory
+	size  int64
+
+	memory []matchedEvent
+
+	file    *os.File
+	offsets []int64 // start of each spilled record; the last entry is the end
+
+	// Kept in memory either way for grouping
+	requestIDs []string
+	resources  [][]string
+}
+
+// spooledEvent is the on-disk form of a matchedEvent
+type spooledEvent struct {
+	Event      types.Event            `json:"event"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	Missing    []string               `json:"missing,omitempty"`
+	Unexpected bool                   `json:"unexpected,omitempty"`
+	NoContext  bool                   `json:"noContext,omitempty"`
+	NewCombo   bool                   `json:"newCombo,omitempty"`
+	Index      int                    `json:"index,omitempty"`
+	Explain    []spooledResult        `json:"explain,omitempty"`
+	Region     string                 `json:"region,omitempty"`
+}
+
+type spooledResult struct {
+	Name    string `json:"name"`
+	Matched bool   `json:"matched"`
+}
+
+func newSpool(limit int64) *spool {
+	return &spool{limit: limit}
+}
+
+func (s *spool) len() int {
+	return len(s.requestIDs)
+}
+
+// spilled reports whether events have been moved to disk
+func (s *spool) spilled() bool {
+	return s.file != nil
+}
+
+// add holds one more event. An event that can't be written to disk is
+// dropped and the error returned; the events already held stay readable.
+func (s *spool) add(match matchedEvent) error {
+	if s.spilled() {
+		if err := s.write(match); err != nil {
+			return fmt.Errorf("%v; event dropped from the output", err)
+		}
+		s.track(match)
+		return nil
+	}
+
+	s.memory = append(s.memory, match)
+	s.track(match)
+	if match.event.CloudTrailEvent != nil {
+		s.size += int64(len(*match.event.CloudTrailEvent)) * parsedSizeFactor
+	}
+	if s.limit <= 0 || s.size <= s.limit {
+		return nil
+	}
+	if err := s.spill(); err != nil {
+		// Stay in memory rather than retrying the spill for every event
+		s.limit = 0
+		return fmt.Errorf("%v; keeping held events in memory", err)
+	}
+	return nil
+}
+
+// track records what grouping needs from an event once it is held
+func (s *spool) track(match matchedEvent) {
+	s.requestIDs = append(s.requestIDs, requestID(match.details))
+	s.resources = append(s.resources, resourceNames(match.event.Resources))
+}
+
+// spill moves the events held in memory to a temp file. On failure the file
+// is discarded and the events stay in memory.
+func (s *spool) spill() error {
+	file, err := os.CreateTemp("", "cloudtrail-logs-spool-*.jsonl")
+	if err != nil {
+		return fmt.Errorf("failed to create spool file: %v", err)
+	}
+	s.file = file
+	s.offsets = []int64{0}
+	for _, held := range s.memory {
+		if err := s.write(held); err != nil {
+			s.close()
+			s.file, s.offsets = nil, nil
+			return err
+		}
+	}
+	s.memory = nil
+	return nil
+}
+
+// write appends one event to the spool file. Writing at the last recorded
+// offset means a failed write is overwritten by the next one.
+func (s *spool) write(match matchedEvent) error {
+	record := spooledEvent{
+		Event:      match.event,
+		Details:    match.details,
+		Missing:    match.missing,
+		Unexpected: match.unexpected,
+		NoContext:  match.noContext,
+		NewCombo:   match.newCombo,
+		Index:      match.index,
+		Region:     match.region,
+	}
+	for _, result := range match.explain {
+		record.Explain = append(record.Explain, spooledResult{Name: result.name, Matched: result.matched})
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode spooled event: %v", err)
+	}
+	data = append(data, '\n')
+	end := s.offsets[len(s.offsets)-1]
+	if _, err := s.file.WriteAt(data, end); err != nil {
+		return fmt.Errorf("failed to write spool file: %v", err)
+	}
+	s.offsets = append(s.offsets, end+int64(len(data)))
+	return nil
+}
+
+// at returns the i-th event added, reading it back from disk if spilled
+func (s *spool) at(i int) (matchedEvent, error) {
+	if !s.spilled() {
+		return s.memory[i], nil
+	}
+
+	data := make([]byte, s.offsets[i+1]-s.offsets[i])
+	if _, err := s.file.ReadAt(data, s.offsets[i]); err != nil && err != io.EOF {
+		return matchedEvent{}, fmt.Errorf("failed to read spool file: %v", err)
+	}
+	var record spooledEvent
+	if err := json.Unmarshal(data, &record); err != nil {
+		return matchedEvent{}, fmt.Errorf("invalid spooled event: %v", err)
+	}
+
+	match := matchedEvent{
+		event:      record.Event,
+		details:    record.Details,
+		missing:    record.Missing,
+		unexpected: record.Unexpected,
+		noContext:  record.NoContext,
+		newCombo:   record.NewCombo,
+		index:      record.Index,
+		region:     record.Region,
+	}
+	for _, result := range record.Explain {
+		match.explain = append(match.explain, filterResult{name: result.Name, matched: result.Matched})
+	}
+	return match, nil
+}
+
+// close removes the spool file
+func (s *spool) close() {
+	if s.file != nil {
+		s.file.Close()
+		os.Remove(s.file.Name())
+	}
+}
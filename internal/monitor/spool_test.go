@@ -0,0 +1,151 @@
+// internal/monitor/spool_test.go
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSpoolSpillsPastLimit(t *testing.T) {
+	body := map[string]interface{}{"requestID": "req-1", "requestParameters": map[string]interface{}{"keyId": "key-1"}}
+	first := matchedEvent{event: newEvent("1", "Decrypt", "alice", 1, body), index: 1, region: "eu-west-1"}
+	first.details = body
+	first.explain = []filterResult{{name: "event name Decrypt", matched: true}}
+	second := matchedEvent{event: newEvent("2", "Encrypt", "bob", 2, map[string]interface{}{}), unexpected: true}
+
+	s := newSpool(int64(len(*first.event.CloudTrailEvent)) * parsedSizeFactor)
+	defer s.close()
+	if err := s.add(first); err != nil {
+		t.Fatal(err)
+	}
+	if s.spilled() {
+		t.Fatal("spilled at the limit, want only past it")
+	}
+	if err := s.add(second); err != nil {
+		t.Fatal(err)
+	}
+	if !s.spilled() {
+		t.Fatal("didn't spill past the limit")
+	}
+	spoolFile := s.file.Name()
+
+	got, err := s.at(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params, _ := got.details["requestParameters"].(map[string]interface{})
+	if *got.event.EventId != "1" || got.index != 1 || got.region != "eu-west-1" || params["keyId"] != "key-1" ||
+		len(got.explain) != 1 || !got.explain[0].matched {
+		t.Errorf("event 0 read back as %+v", got)
+	}
+	if got, err = s.at(1); err != nil || *got.event.EventId != "2" || !got.unexpected {
+		t.Errorf("event 1 read back as %+v, %v", got, err)
+	}
+	if s.len() != 2 || s.requestIDs[0] != "req-1" {
+		t.Errorf("len = %d, requestIDs = %v", s.len(), s.requestIDs)
+	}
+
+	s.close()
+	if _, err := os.Stat(spoolFile); !os.IsNotExist(err) {
+		t.Errorf("spool file left behind: %v", err)
+	}
+}
+
+// withBodies is newestFirst with request parameters to hold in the spool
+func withBodies() *fakeTrail {
+	trail := newestFirst()
+	for _, page := range trail.pages {
+		for i, event := range page {
+			body := map[string]interface{}{"requestParameters": map[string]interface{}{"keyId": *event.EventId}}
+			page[i] = newEvent(*event.EventId, "Decrypt", "alice", int(event.EventTime.Sub(testStart).Minutes()), body)
+		}
+	}
+	return trail
+}
+
+// withoutOutputFile drops the line naming the per-test log file
+func withoutOutputFile(out string) string {
+	var kept []string
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "Output file: ") {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func TestMaxMemoryKeepsResults(t *testing.T) {
+	inMemory, err := scan(t, withBodies(), FilterOptions{}, OutputOptions{Order: OrderOldest}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spilled, err := scan(t, withBodies(), FilterOptions{}, OutputOptions{Order: OrderOldest, MaxMemory: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inMemory, spilled = withoutOutputFile(inMemory), withoutOutputFile(spilled)
+
+	notice := "Reading 3 held events back from disk (over --max-memory)\n"
+	if !strings.Contains(spilled, notice) {
+		t.Fatalf("events weren't spooled to disk:\n%s", spilled)
+	}
+	if strings.Replace(spilled, notice, "", 1) != inMemory {
+		t.Errorf("spooled output differs:\n%s\nwant\n%s", spilled, inMemory)
+	}
+}
+
+func TestSpoolWriteErrorDropsOnlyThatEvent(t *testing.T) {
+	events := []matchedEvent{
+		{event: newEvent("1", "Decrypt", "alice", 1, map[string]interface{}{})},
+		{event: newEvent("2", "Decrypt", "alice", 2, map[string]interface{}{})},
+		{event: newEvent("3", "Decrypt", "alice", 3, map[string]interface{}{})},
+	}
+	s := newSpool(1)
+	defer s.close()
+	for _, match := range events[:2] {
+		if err := s.add(match); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !s.spilled() {
+		t.Fatal("didn't spill past the limit")
+	}
+
+	// Closing the file underneath the spool makes the next write fail
+	s.file.Close()
+	if err := s.add(events[2]); err == nil {
+		t.Fatal("add succeeded with the spool file closed")
+	}
+	if s.len() != 2 || len(s.resources) != 2 {
+		t.Fatalf("len = %d with %d resources after a failed write, want 2", s.len(), len(s.resources))
+	}
+	for i := 0; i < s.len(); i++ {
+		// Reads fail on the closed file too; what matters is that every
+		// index below len has an offset rather than panicking
+		s.at(i)
+	}
+}
+
+func TestSpoolStaysInMemoryWhenSpillFails(t *testing.T) {
+	t.Setenv("TMPDIR", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("TMP", filepath.Join(t.TempDir(), "missing"))
+
+	s := newSpool(1)
+	defer s.close()
+	if err := s.add(matchedEvent{event: newEvent("1", "Decrypt", "alice", 1, map[string]interface{}{})}); err == nil {
+		t.Fatal("add succeeded without a temp dir to spill to")
+	}
+	if err := s.add(matchedEvent{event: newEvent("2", "Decrypt", "alice", 2, map[string]interface{}{})}); err != nil {
+		t.Fatalf("add retried the spill: %v", err)
+	}
+	if s.spilled() || s.len() != 2 {
+		t.Fatalf("spilled = %v, len = %d; want both events in memory", s.spilled(), s.len())
+	}
+	for i, id := range []string{"1", "2"} {
+		if got, err := s.at(i); err != nil || *got.event.EventId != id {
+			t.Errorf("event %d read back as %+v, %v", i, got, err)
+		}
+	}
+}
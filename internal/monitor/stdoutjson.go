@@ -0,0 +1,44 @@
+// internal/monitor/stdoutjson.go
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+)
+
+// writeStdoutJSON prints a matched event as one compact JSON object for
+// --stdout-json. details is the full parsed CloudTrailEvent, untruncated.
+func writeStdoutJSON(out io.Writer, match matchedEvent, details map[string]interface{}) error {
+	event := match.event
+	record := map[string]interface{}{
+		"eventName":       SafeString(event.EventName),
+		"eventSource":     SafeString(event.EventSource),
+		"user":            SafeString(event.Username),
+		"cloudTrailEvent": details,
+	}
+	if event.EventTime != nil {
+		record["timestamp"] = event.EventTime.In(timeutil.Location()).Format(time.RFC3339)
+	}
+
+	region := match.region
+	if region == "" {
+		region, _ = details["awsRegion"].(string)
+	}
+	if region != "" {
+		record["region"] = region
+	}
+	if errorCode, _ := details["errorCode"].(string); errorCode != "" {
+		record["errorCode"] = errorCode
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %v", err)
+	}
+	_, err = fmt.Fprintf(out, "%s\n", data)
+	return err
+}
@@ -0,0 +1,79 @@
+// internal/monitor/summary.go
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+)
+
+// Rows shown per breakdown in the end-of-run summary
+const summaryRows = 10
+
+// eventSummary tallies matched events by name, user, and outcome
+type eventSummary struct {
+	events    map[string]int
+	users     map[string]int
+	regions   map[string]int // only filled in multi-region scans
+	errors    int
+	successes int
+	unknown   int // no readable body, so the outcome can't be told
+}
+
+func newEventSummary() *eventSummary {
+	return &eventSummary{events: make(map[string]int), users: make(map[string]int), regions: make(map[string]int)}
+}
+
+func (s *eventSummary) add(event types.Event, details map[string]interface{}, region string) {
+	s.events[SafeString(event.EventName)]++
+	s.users[SafeString(event.Username)]++
+	if region != "" {
+		s.regions[region]++
+	}
+	if details == nil {
+		s.unknown++
+		return
+	}
+	if errorCode, _ := details["errorCode"].(string); errorCode != "" {
+		s.errors++
+	} else {
+		s.successes++
+	}
+}
+
+func (s *eventSummary) report(out io.Writer) {
+	if len(s.events) == 0 {
+		return
+	}
+	fmt.Fprintln(out, theme.Info("\nSummary:"))
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	s.writeCounts(tw, "Event", s.events)
+	fmt.Fprintln(tw)
+	s.writeCounts(tw, "User", s.users)
+	fmt.Fprintln(tw)
+	if len(s.regions) > 0 {
+		s.writeCounts(tw, "Region", s.regions)
+		fmt.Fprintln(tw)
+	}
+	fmt.Fprintln(tw, "  Outcome\tCount")
+	fmt.Fprintf(tw, "  success\t%d\n", s.successes)
+	fmt.Fprintf(tw, "  error\t%d\n", s.errors)
+	if s.unknown > 0 {
+		fmt.Fprintf(tw, "  unknown\t%d\n", s.unknown)
+	}
+	tw.Flush()
+}
+
+// writeCounts prints the largest counts under a header, noting how many were left out
+func (s *eventSummary) writeCounts(w io.Writer, header string, counts map[string]int) {
+	fmt.Fprintf(w, "  %s\tCount\n", header)
+	for _, row := range topCounts(counts, summaryRows) {
+		fmt.Fprintf(w, "  %s\t%d\n", row.Name, row.Count)
+	}
+	if extra := len(counts) - summaryRows; extra > 0 {
+		fmt.Fprintf(w, "  (%d more)\t\n", extra)
+	}
+}
@@ -0,0 +1,97 @@
+// internal/monitor/summary_test.go
+package monitor
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestEventSummaryTally(t *testing.T) {
+	failed := map[string]interface{}{"errorCode": "AccessDenied"}
+	succeeded := map[string]interface{}{"eventName": "Decrypt"}
+	events := []struct {
+		event   types.Event
+		details map[string]interface{}
+		region  string
+	}{
+		{newEvent("1", "Decrypt", "alice", 1, nil), succeeded, ""},
+		{newEvent("2", "Decrypt", "bob", 2, nil), failed, ""},
+		{newEvent("3", "Encrypt", "alice", 3, nil), succeeded, ""},
+		{newEvent("4", "Decrypt", "alice", 4, nil), nil, ""},
+		{newEvent("5", "Encrypt", "alice", 5, nil), map[string]interface{}{"errorCode": ""}, ""},
+	}
+	summary := newEventSummary()
+	for _, e := range events {
+		summary.add(e.event, e.details, e.region)
+	}
+
+	if want := map[string]int{"Decrypt": 3, "Encrypt": 2}; !reflect.DeepEqual(summary.events, want) {
+		t.Errorf("events = %v, want %v", summary.events, want)
+	}
+	if want := map[string]int{"alice": 4, "bob": 1}; !reflect.DeepEqual(summary.users, want) {
+		t.Errorf("users = %v, want %v", summary.users, want)
+	}
+	if summary.successes != 3 || summary.errors != 1 || summary.unknown != 1 {
+		t.Errorf("outcomes = %d success, %d error, %d unknown; want 3, 1, 1", summary.successes, summary.errors, summary.unknown)
+	}
+	if len(summary.regions) != 0 {
+		t.Errorf("regions tallied in a single-region scan: %v", summary.regions)
+	}
+
+	var out bytes.Buffer
+	summary.report(&out)
+	for _, want := range []string{"Summary:", "  Event    Count\n  Decrypt  3\n  Encrypt  2\n", "  User   Count\n  alice  4\n  bob    1\n", "  success  3\n  error    1\n  unknown  1\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("report is missing %q:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestEventSummaryRegionsAndOverflow(t *testing.T) {
+	summary := newEventSummary()
+	for i := 0; i < summaryRows+2; i++ {
+		summary.add(newEvent(fmt.Sprint(i), fmt.Sprintf("Event%02d", i), "alice", i, nil), nil, "eu-west-1")
+	}
+	if summary.regions["eu-west-1"] != summaryRows+2 {
+		t.Errorf("regions = %v", summary.regions)
+	}
+
+	var out bytes.Buffer
+	summary.report(&out)
+	if !strings.Contains(out.String(), "(2 more)") || strings.Contains(out.String(), "Event11") {
+		t.Errorf("want the breakdown capped at %d rows:\n%s", summaryRows, out.String())
+	}
+	if !strings.Contains(out.String(), "Region") {
+		t.Errorf("region breakdown missing:\n%s", out.String())
+	}
+
+	var empty bytes.Buffer
+	newEventSummary().report(&empty)
+	if empty.Len() != 0 {
+		t.Errorf("summary printed with no events:\n%s", empty.String())
+	}
+}
+
+func TestSummaryFlag(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{newEvent("1", "Decrypt", "alice", 1, nil)}}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{Summary: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "Summary:") {
+		t.Errorf("summary missing:\n%s", out)
+	}
+
+	trail = &fakeTrail{pages: [][]types.Event{{newEvent("1", "Decrypt", "alice", 1, nil)}}}
+	if out, err = scan(t, trail, FilterOptions{}, OutputOptions{}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "Summary:") {
+		t.Errorf("summary printed with --summary=false:\n%s", out)
+	}
+}
@@ -0,0 +1,54 @@
+// internal/monitor/syslog.go
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/syslog"
+	"github.com/dhairya13703/cloudtrail-logs/internal/theme"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+// syslogMessage shapes a matched event for a syslog collector. Failed calls
+// are sent as warnings and successful ones as informational.
+func syslogMessage(entry writer.Entry) syslog.Message {
+	event := entry.Event
+	params := map[string]string{
+		"eventID":     SafeString(event.EventId),
+		"eventSource": SafeString(event.EventSource),
+		"user":        SafeString(event.Username),
+	}
+
+	severity := syslog.SeverityInformational
+	if errorCode, ok := entry.Details["errorCode"].(string); ok && errorCode != "" {
+		severity = syslog.SeverityWarning
+		params["errorCode"] = errorCode
+	}
+
+	msg := syslog.Message{
+		Severity: severity,
+		MsgID:    SafeString(event.EventName),
+		Params:   params,
+	}
+	if event.EventTime != nil {
+		msg.Time = *event.EventTime
+	}
+	if entry.Details != nil {
+		if body, err := json.Marshal(entry.Details); err == nil {
+			msg.Body = string(body)
+		}
+	}
+	return msg
+}
+
+// sendSyslog forwards a matched event, warning once if the collector fails
+func (m *Monitor) sendSyslog(entry writer.Entry) {
+	if m.syslog == nil || m.syslogFailed {
+		return
+	}
+	if err := m.syslog.Send(syslogMessage(entry)); err != nil {
+		fmt.Fprintf(m.out, theme.Warning("Warning: %v; no further events will be sent to syslog\n"), err)
+		m.syslogFailed = true
+	}
+}
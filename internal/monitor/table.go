@@ -0,0 +1,44 @@
+// internal/monitor/table.go
+package monitor
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/table"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+)
+
+var tableHeaders = []string{"Timestamp", "Event", "User", "Source", "Error"}
+
+// tableRow flattens a matched event into the --table columns
+func tableRow(match matchedEvent) []string {
+	errorCode, _ := match.details["errorCode"].(string)
+	row := []string{
+		timeutil.FormatTime(*match.event.EventTime),
+		SafeString(match.event.EventName),
+		SafeString(match.event.Username),
+		SafeString(match.event.EventSource),
+		errorCode,
+	}
+	if match.index > 0 {
+		row = append([]string{strconv.Itoa(match.index)}, row...)
+	}
+	return row
+}
+
+// renderTable prints the rows collected in table mode
+func (m *Monitor) renderTable() {
+	if len(m.rows) == 0 {
+		return
+	}
+	headers := tableHeaders
+	if m.output.ShowIndex {
+		headers = append([]string{"#"}, tableHeaders...)
+	}
+	width := m.output.TableWidth
+	if width <= 0 {
+		width = table.TerminalWidth(os.Stdout)
+	}
+	table.Render(m.out, headers, m.rows, width)
+}
@@ -0,0 +1,35 @@
+// internal/monitor/table_test.go
+package monitor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestTableOutput(t *testing.T) {
+	trail := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice", 2, nil),
+		newEvent("2", "Decrypt", "bob", 1, map[string]interface{}{"errorCode": "AccessDenied"}),
+	}}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{Table: true, TableWidth: 100, ShowIndex: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `+---+---------------------+---------+-------+-------------------+--------------+
+| # | Timestamp           | Event   | User  | Source            | Error        |
++---+---------------------+---------+-------+-------------------+--------------+
+| 1 | 2024-01-15 00:02:00 | Decrypt | alice | kms.amazonaws.com |              |
+| 2 | 2024-01-15 00:01:00 | Decrypt | bob   | kms.amazonaws.com | AccessDenied |
++---+---------------------+---------+-------+-------------------+--------------+
+`
+	if !strings.Contains(out, want) {
+		t.Errorf("table output =\n%s\nwant\n%s", out, want)
+	}
+	// Events are only shown in the table
+	if strings.Contains(out, "  User: alice") {
+		t.Errorf("per-event blocks printed in table mode:\n%s", out)
+	}
+}
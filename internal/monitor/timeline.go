@@ -0,0 +1,69 @@
+// internal/monitor/timeline.go
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// Timeline views
+const TimelineResource = "resource"
+
+// ValidateTimelineBy checks the --timeline-by option
+func ValidateTimelineBy(by string) error {
+	if by != "" && by != TimelineResource {
+		return fmt.Errorf("invalid --timeline-by value %q: use %q", by, TimelineResource)
+	}
+	return nil
+}
+
+type resourceTimeline struct {
+	resource string // empty for events that name no resource
+	events   []int  // positions of the resource's events, oldest first
+}
+
+// resourceNames lists the distinct resources an event names
+func resourceNames(resources []types.Resource) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, resource := range resources {
+		name := SafeString(resource.ResourceName)
+		if resource.ResourceName == nil || name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// timelineByResource files the events at the given chronological positions
+// under every resource they name. Timelines are ordered by their first event,
+// with events naming no resource collected at the end.
+func timelineByResource(positions []int, resources [][]string) []resourceTimeline {
+	var timelines []resourceTimeline
+	index := make(map[string]int)
+	var unattached []int
+
+	for _, position := range positions {
+		if len(resources[position]) == 0 {
+			unattached = append(unattached, position)
+			continue
+		}
+		for _, name := range resources[position] {
+			i, ok := index[name]
+			if !ok {
+				i = len(timelines)
+				index[name] = i
+				timelines = append(timelines, resourceTimeline{resource: name})
+			}
+			timelines[i].events = append(timelines[i].events, position)
+		}
+	}
+
+	if len(unattached) > 0 {
+		timelines = append(timelines, resourceTimeline{events: unattached})
+	}
+	return timelines
+}
@@ -0,0 +1,76 @@
+// internal/monitor/timeline_test.go
+package monitor
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	sdkaws "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// onResources is a Decrypt at minute naming the given resources
+func onResources(id string, minute int, names ...string) types.Event {
+	event := newEvent(id, "Decrypt", "alice", minute, nil)
+	for _, name := range names {
+		event.Resources = append(event.Resources, types.Resource{ResourceName: sdkaws.String(name), ResourceType: sdkaws.String("AWS::KMS::Key")})
+	}
+	return event
+}
+
+func TestTimelineByResource(t *testing.T) {
+	// Positions are chronological; 2 shares both keys, 3 names none
+	resources := [][]string{{"key-a"}, {"key-b"}, {"key-a", "key-b"}, nil, {"key-c"}}
+	got := timelineByResource([]int{0, 1, 2, 3, 4}, resources)
+	want := []resourceTimeline{
+		{resource: "key-a", events: []int{0, 2}},
+		{resource: "key-b", events: []int{1, 2}},
+		{resource: "key-c", events: []int{4}},
+		{events: []int{3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("timelineByResource = %+v, want %+v", got, want)
+	}
+}
+
+func TestResourceNamesDeduplicated(t *testing.T) {
+	event := onResources("1", 0, "key-a", "key-a", "", "key-b")
+	if got := resourceNames(event.Resources); !reflect.DeepEqual(got, []string{"key-a", "key-b"}) {
+		t.Errorf("resourceNames = %v", got)
+	}
+}
+
+func TestTimelineOutput(t *testing.T) {
+	// Newest first, as LookupEvents returns them
+	trail := &fakeTrail{pages: [][]types.Event{
+		{onResources("4", 4), onResources("3", 3, "key-a", "key-b")},
+		{onResources("2", 2, "key-b"), onResources("1", 1, "key-a")},
+	}}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{TimelineBy: TimelineResource}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Reduce the output to the headers and event times
+	line := regexp.MustCompile(`^(Resource: .*|\[2024-01-15 (\d{2}:\d{2}):00\].*)$`)
+	var got []string
+	for _, text := range strings.Split(out, "\n") {
+		if match := line.FindStringSubmatch(text); match != nil {
+			if match[2] != "" {
+				got = append(got, match[2])
+			} else {
+				got = append(got, match[1])
+			}
+		}
+	}
+	want := []string{
+		"Resource: key-a (2 events)", "00:01", "00:03",
+		"Resource: key-b (2 events)", "00:02", "00:03",
+		"Resource: (no resource) (1 events)", "00:04",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("timeline =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,53 @@
+// internal/monitor/tls.go
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTLSVersion converts "TLSv1.2", "1.2", or "TLSv1" into a comparable
+// number (12, 12, 10)
+func parseTLSVersion(version string) (int, bool) {
+	v := strings.TrimSpace(strings.ToLower(version))
+	v = strings.TrimPrefix(v, "tlsv")
+	v = strings.TrimPrefix(v, "tls")
+
+	major, minor := v, "0"
+	if i := strings.Index(v, "."); i >= 0 {
+		major, minor = v[:i], v[i+1:]
+	}
+	majorNum, err := strconv.Atoi(major)
+	if err != nil || majorNum < 1 {
+		return 0, false
+	}
+	minorNum, err := strconv.Atoi(minor)
+	if err != nil || minorNum < 0 || minorNum > 9 {
+		return 0, false
+	}
+	return majorNum*10 + minorNum, true
+}
+
+// ValidateTLSVersion checks the --min-tls value
+func ValidateTLSVersion(version string) error {
+	if _, ok := parseTLSVersion(version); !ok {
+		return fmt.Errorf("invalid TLS version %q: use e.g. 1.2 or TLSv1.2", version)
+	}
+	return nil
+}
+
+// weakTLS reports whether the event was made over a TLS version below minimum.
+// Events without tlsDetails (e.g. calls made by AWS services) never match.
+func weakTLS(details map[string]interface{}, minimum string) bool {
+	threshold, ok := parseTLSVersion(minimum)
+	if !ok {
+		return false
+	}
+	version, _ := lookupPath(details, "tlsDetails.tlsVersion").(string)
+	if version == "" {
+		return false
+	}
+	actual, ok := parseTLSVersion(version)
+	return ok && actual < threshold
+}
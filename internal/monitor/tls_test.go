@@ -0,0 +1,72 @@
+// internal/monitor/tls_test.go
+package monitor
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func tlsEvent(id, version string) types.Event {
+	return newEvent(id, "Decrypt", "alice", 0, map[string]interface{}{
+		"tlsDetails": map[string]interface{}{
+			"tlsVersion":  version,
+			"cipherSuite": "ECDHE-RSA-AES128-GCM-SHA256",
+		},
+	})
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    int
+		ok      bool
+	}{
+		{"TLSv1.2", 12, true},
+		{"1.2", 12, true},
+		{"TLSv1", 10, true},
+		{"tls1.3", 13, true},
+		{" TLSv1.1 ", 11, true},
+		{"", 0, false},
+		{"TLSv", 0, false},
+		{"SSLv3", 0, false},
+		{"1.10", 0, false},
+		{"0.9", 0, false},
+	}
+	for _, tc := range tests {
+		got, ok := parseTLSVersion(tc.version)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("parseTLSVersion(%q) = %d, %v; want %d, %v", tc.version, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestMinTLSFilter(t *testing.T) {
+	tests := []struct {
+		event types.Event
+		want  bool
+	}{
+		{tlsEvent("1", "TLSv1"), true},
+		{tlsEvent("2", "TLSv1.1"), true},
+		{tlsEvent("3", "TLSv1.2"), false},
+		{tlsEvent("4", "TLSv1.3"), false},
+		{tlsEvent("5", "garbage"), false},
+		// Calls made by AWS services carry no tlsDetails
+		{newEvent("6", "Decrypt", "kms.amazonaws.com", 0, map[string]interface{}{}), false},
+		{newEvent("7", "Decrypt", "alice", 0, nil), false},
+	}
+	for _, tc := range tests {
+		if got := passes(tc.event, FilterOptions{MinTLS: "1.2"}); got != tc.want {
+			t.Errorf("event %s below TLS 1.2: got %v, want %v", *tc.event.EventId, got, tc.want)
+		}
+	}
+}
+
+func TestValidateTLSVersion(t *testing.T) {
+	if err := ValidateTLSVersion("TLSv1.2"); err != nil {
+		t.Errorf("ValidateTLSVersion(TLSv1.2) = %v", err)
+	}
+	if err := ValidateTLSVersion("latest"); err == nil {
+		t.Error("ValidateTLSVersion accepted latest")
+	}
+}
@@ -0,0 +1,39 @@
+// internal/monitor/truncate.go
+package monitor
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// truncateString shortens s to limit runes, noting the original length
+func truncateString(s string, limit int) string {
+	length := utf8.RuneCountInString(s)
+	if limit <= 0 || length <= limit {
+		return s
+	}
+	return fmt.Sprintf("%s… (%d chars)", string([]rune(s)[:limit]), length)
+}
+
+// truncateValues returns a copy of value with every string longer than limit
+// shortened. Maps and slices are copied so the parsed event is left intact.
+func truncateValues(value interface{}, limit int) interface{} {
+	switch v := value.(type) {
+	case string:
+		return truncateString(v, limit)
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			copied[key] = truncateValues(item, limit)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = truncateValues(item, limit)
+		}
+		return copied
+	default:
+		return value
+	}
+}
@@ -0,0 +1,84 @@
+// internal/monitor/truncate_test.go
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/writer"
+)
+
+func TestTruncateStringBoundary(t *testing.T) {
+	tests := []struct {
+		value string
+		limit int
+		want  string
+	}{
+		{"abcde", 5, "abcde"}, // exactly at the limit is kept
+		{"abcdef", 5, "abcde… (6 chars)"},
+		{"ééééé", 4, "éééé… (5 chars)"}, // counted in runes, not bytes
+		{"abcdef", 0, "abcdef"},
+		{"", 3, ""},
+	}
+	for _, tc := range tests {
+		if got := truncateString(tc.value, tc.limit); got != tc.want {
+			t.Errorf("truncateString(%q, %d) = %q, want %q", tc.value, tc.limit, got, tc.want)
+		}
+	}
+}
+
+func TestTruncateValuesLeavesOriginal(t *testing.T) {
+	details := map[string]interface{}{
+		"requestParameters": map[string]interface{}{
+			"ciphertext": "0123456789",
+			"grants":     []interface{}{"short", "a-longer-one"},
+			"count":      float64(123456789),
+		},
+	}
+	truncated := truncateValues(details, 5).(map[string]interface{})
+
+	params := truncated["requestParameters"].(map[string]interface{})
+	if params["ciphertext"] != "01234… (10 chars)" {
+		t.Errorf("ciphertext = %v", params["ciphertext"])
+	}
+	if grants := params["grants"].([]interface{}); grants[0] != "short" || grants[1] != "a-lon… (12 chars)" {
+		t.Errorf("grants = %v", grants)
+	}
+	if params["count"] != float64(123456789) {
+		t.Errorf("count = %v, want numbers left alone", params["count"])
+	}
+	if original := details["requestParameters"].(map[string]interface{}); original["ciphertext"] != "0123456789" {
+		t.Errorf("the parsed event was modified: %v", original["ciphertext"])
+	}
+}
+
+func TestTruncateValuesInOutput(t *testing.T) {
+	long := strings.Repeat("x", 40)
+	trail := &fakeTrail{pages: [][]types.Event{{
+		newEvent("1", "Decrypt", "alice", 1, map[string]interface{}{
+			"requestParameters": map[string]interface{}{"ciphertext": long},
+		}),
+	}}}
+	logFile := filepath.Join(t.TempDir(), "events.log")
+	export := &writer.ExportOptions{Filename: logFile, Format: writer.FormatText}
+	out, err := scan(t, trail, FilterOptions{}, OutputOptions{TruncateValues: 10}, export)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logged, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, text := range map[string]string{"console": out, "log file": string(logged)} {
+		if strings.Contains(text, long) {
+			t.Errorf("%s has the full value:\n%s", name, text)
+		}
+		if !strings.Contains(text, "xxxxxxxxxx… (40 chars)") {
+			t.Errorf("%s has no truncated value:\n%s", name, text)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// internal/monitor/workers.go
+package monitor
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// evaluatedEvent is a worker's verdict on one event of a page
+type evaluatedEvent struct {
+	matched  bool // passed the filters and the sample
+	results  []filterResult
+	details  map[string]interface{}
+	parseErr error
+}
+
+// concurrency returns how many workers process each page's events
+func (m *Monitor) concurrency() int {
+	if m.output.Concurrency > 0 {
+		return m.output.Concurrency
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// evaluatePage filters each event and parses the body of those that match,
+// spreading the events across workers. LookupEvents pages can only be fetched
+// one after another (each needs the previous NextToken), so this per-event
+// work is the only part of a region's scan that runs in parallel. Results
+// come back in page order so output stays the same at any worker count.
+func evaluatePage(events []types.Event, filters FilterOptions, sample *sampler, workers int) []evaluatedEvent {
+	evaluated := make([]evaluatedEvent, len(events))
+	if workers > len(events) {
+		workers = len(events)
+	}
+	if workers <= 1 {
+		for i, event := range events {
+			evaluated[i] = evaluateEvent(event, filters, sample)
+		}
+		return evaluated
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				evaluated[i] = evaluateEvent(events[i], filters, sample)
+			}
+		}()
+	}
+	for i := range events {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return evaluated
+}
+
+// evaluateEvent is the per-event work of a scan that doesn't touch shared state
+func evaluateEvent(event types.Event, filters FilterOptions, sample *sampler) evaluatedEvent {
+	results := evaluateFilters(event, filters)
+	if len(results) > 0 && !results[len(results)-1].matched {
+		return evaluatedEvent{results: results}
+	}
+	if sample != nil && !sample.keep(event) {
+		return evaluatedEvent{results: results}
+	}
+
+	evaluated := evaluatedEvent{matched: true, results: results}
+	if event.CloudTrailEvent != nil {
+		evaluated.parseErr = decodeDetails(*event.CloudTrailEvent, &evaluated.details)
+	}
+	return evaluated
+}
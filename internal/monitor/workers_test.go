@@ -0,0 +1,84 @@
+// internal/monitor/workers_test.go
+package monitor
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// fullPage is a page of LookupEvents' maximum 50 events with realistic bodies,
+// every third one failed
+func fullPage() []types.Event {
+	events := make([]types.Event, 50)
+	for i := range events {
+		body := map[string]interface{}{
+			"eventID":           fmt.Sprintf("event-%d", i),
+			"sourceIPAddress":   "203.0.113.7",
+			"userIdentity":      map[string]interface{}{"type": "IAMUser", "userName": "alice"},
+			"requestParameters": map[string]interface{}{"keyId": "arn:aws:kms:us-east-1:123456789012:key/key-1"},
+			"resources":         []interface{}{map[string]interface{}{"ARN": "arn:aws:kms:us-east-1:123456789012:key/key-1"}},
+		}
+		if i%3 == 0 {
+			body["errorCode"] = "AccessDenied"
+		}
+		events[i] = newEvent(fmt.Sprint(i), "Decrypt", "alice", i, body)
+	}
+	return events
+}
+
+func TestEvaluatePageSameAtAnyWorkerCount(t *testing.T) {
+	events := fullPage()
+	filters := FilterOptions{EventName: "Decrypt", ErrorsOnly: true}
+	sequential := evaluatePage(events, filters, nil, 1)
+
+	matched := 0
+	for _, e := range sequential {
+		if e.matched {
+			matched++
+		}
+	}
+	if matched != 17 {
+		t.Fatalf("%d events matched, want the 17 failed ones", matched)
+	}
+
+	for _, workers := range []int{2, 8, 100} {
+		if pooled := evaluatePage(events, filters, nil, workers); !reflect.DeepEqual(pooled, sequential) {
+			t.Errorf("%d workers evaluated the page differently from one", workers)
+		}
+	}
+}
+
+func TestScanOutputSameAtAnyWorkerCount(t *testing.T) {
+	var outputs []string
+	for _, workers := range []int{1, 8} {
+		trail := &fakeTrail{pages: [][]types.Event{fullPage(), fullPage()}}
+		out, err := scan(t, trail, FilterOptions{ErrorsOnly: true}, OutputOptions{Concurrency: workers}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		outputs = append(outputs, withoutOutputFile(out))
+	}
+	if outputs[0] != outputs[1] {
+		t.Errorf("output changed with the worker count:\n%s\n---\n%s", outputs[0], outputs[1])
+	}
+	if !strings.Contains(outputs[0], "Found 34 matching events") {
+		t.Errorf("want the failed events of both pages:\n%s", outputs[0])
+	}
+}
+
+func benchmarkEvaluatePage(b *testing.B, workers int) {
+	events := fullPage()
+	filters := FilterOptions{KeyID: "key-1", SourceIP: "203.0.113.0/24", ErrorsOnly: true}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluatePage(events, filters, nil, workers)
+	}
+}
+
+func BenchmarkEvaluatePageSequential(b *testing.B) { benchmarkEvaluatePage(b, 1) }
+
+func BenchmarkEvaluatePagePooled(b *testing.B) { benchmarkEvaluatePage(b, 8) }
@@ -0,0 +1,72 @@
+// internal/retry/retry.go
+package retry
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	sdkretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+// Policy controls how many times a call is attempted and how long to wait
+// between attempts
+type Policy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration // ceiling for the first backoff, doubled per attempt
+	MaxDelay    time.Duration // backoff never exceeds this
+}
+
+// Default is applied to every AWS call the tool makes
+var Default = Policy{
+	MaxAttempts: 5,
+	BaseDelay:   250 * time.Millisecond,
+	MaxDelay:    20 * time.Second,
+}
+
+// Backoff returns a jittered delay before the given retry (1 for the first).
+// Full jitter: a random duration in [0, min(MaxDelay, BaseDelay*2^(retry-1))).
+func (p Policy) Backoff(retry int) time.Duration {
+	ceiling := p.BaseDelay
+	for i := 1; i < retry && ceiling < p.MaxDelay; i++ {
+		ceiling *= 2
+	}
+	if ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling)
+}
+
+// Do calls fn until it succeeds, returns an error that isn't retryable, the
+// attempts run out, or ctx is done. The last error from fn is returned.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil || !Retryable(err) || attempt >= policy.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// Throttled reports whether err is a throttling or rate-limit error
+func Throttled(err error) bool {
+	return sdkretry.IsErrorThrottles(sdkretry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary
+}
+
+// Retryable reports whether err is transient: throttling, a 5xx response, a
+// connection failure, or a request timeout. Canceled requests are not retried.
+func Retryable(err error) bool {
+	return sdkretry.IsErrorRetryables(sdkretry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
@@ -0,0 +1,115 @@
+// internal/retry/retry_test.go
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+var (
+	throttled   = &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	denied      = &smithy.GenericAPIError{Code: "AccessDeniedException"}
+	quickPolicy = Policy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 4 * time.Millisecond}
+)
+
+func TestBackoffCeiling(t *testing.T) {
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	ceilings := map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second, // capped
+		9: time.Second,
+	}
+	for retry, ceiling := range ceilings {
+		var longest time.Duration
+		for i := 0; i < 500; i++ {
+			delay := policy.Backoff(retry)
+			if delay < 0 || delay >= ceiling {
+				t.Fatalf("Backoff(%d) = %s, want within [0, %s)", retry, delay, ceiling)
+			}
+			longest = max(longest, delay)
+		}
+		// Full jitter spreads the delays over the whole range
+		if longest < ceiling/2 {
+			t.Errorf("Backoff(%d) never exceeded %s in 500 draws; ceiling is %s", retry, longest, ceiling)
+		}
+	}
+}
+
+func TestBackoffZero(t *testing.T) {
+	if got := (Policy{}).Backoff(3); got != 0 {
+		t.Errorf("Backoff without delays = %s, want 0", got)
+	}
+}
+
+func TestDoRetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), quickPolicy, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return throttled
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("Do = %v after %d calls, want success on the third", err, calls)
+	}
+}
+
+func TestDoGivesUp(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), quickPolicy, func(context.Context) error {
+		calls++
+		return throttled
+	})
+	if !errors.Is(err, throttled) || calls != quickPolicy.MaxAttempts {
+		t.Errorf("Do = %v after %d calls, want the last error after %d", err, calls, quickPolicy.MaxAttempts)
+	}
+}
+
+func TestDoStopsOnPermanentError(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), quickPolicy, func(context.Context) error {
+		calls++
+		return denied
+	})
+	if !errors.Is(err, denied) || calls != 1 {
+		t.Errorf("Do = %v after %d calls, want AccessDenied without retrying", err, calls)
+	}
+}
+
+func TestDoStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := Policy{MaxAttempts: 5, BaseDelay: time.Hour, MaxDelay: time.Hour}
+	calls := 0
+	started := time.Now()
+	err := Do(ctx, policy, func(context.Context) error {
+		calls++
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return throttled
+	})
+	if !errors.Is(err, throttled) || calls != 1 {
+		t.Errorf("Do = %v after %d calls, want the last error once cancelled", err, calls)
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("Do waited %s after cancellation", elapsed)
+	}
+}
+
+func TestClassification(t *testing.T) {
+	if !Throttled(throttled) || !Retryable(throttled) {
+		t.Error("ThrottlingException isn't throttled and retryable")
+	}
+	if Throttled(denied) || Retryable(denied) {
+		t.Error("AccessDeniedException is treated as transient")
+	}
+	if Retryable(context.Canceled) {
+		t.Error("a cancelled request is retryable")
+	}
+}
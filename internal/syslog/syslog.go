@@ -0,0 +1,150 @@
+// internal/syslog/syslog.go
+package syslog
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severities used for events (RFC5424 section 6.2.1)
+const (
+	SeverityWarning       = 4
+	SeverityInformational = 6
+)
+
+// Events are sent with the local0 facility
+const facilityLocal0 = 16
+
+const appName = "cloudtrail-logs"
+
+// Private enterprise number used for the structured data ID
+const sdID = "cloudtrail@32473"
+
+// Largest message sent over UDP; longer bodies are truncated
+const maxUDPMessage = 64000
+
+// Message is one event to send
+type Message struct {
+	Time     time.Time
+	Severity int
+	MsgID    string            // e.g. the event name
+	Params   map[string]string // structured data parameters
+	Body     string
+}
+
+// Sender writes RFC5424 messages to a syslog collector
+type Sender struct {
+	network  string
+	conn     net.Conn
+	hostname string
+	pid      int
+}
+
+// ParseAddress splits a [udp://|tcp://]host:port address into its network
+// and host:port. UDP is the default.
+func ParseAddress(address string) (string, string, error) {
+	network := "udp"
+	if scheme, rest, ok := strings.Cut(address, "://"); ok {
+		network, address = scheme, rest
+	}
+	if network != "udp" && network != "tcp" {
+		return "", "", fmt.Errorf("invalid syslog protocol %q: use udp:// or tcp://", network)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return "", "", fmt.Errorf("invalid syslog address %q: use host:port", address)
+	}
+	return network, address, nil
+}
+
+// Dial connects to the collector at a [udp://|tcp://]host:port address
+func Dial(address string) (*Sender, error) {
+	network, hostPort, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout(network, hostPort, 10*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog at %s: %v", address, err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return &Sender{network: network, conn: conn, hostname: hostname, pid: os.Getpid()}, nil
+}
+
+// Send writes one message. TCP messages are framed by octet counting (RFC6587).
+func (s *Sender) Send(msg Message) error {
+	data := s.Format(msg)
+	if s.network == "tcp" {
+		data = append([]byte(fmt.Sprintf("%d ", len(data))), data...)
+	} else if len(data) > maxUDPMessage {
+		data = data[:maxUDPMessage]
+	}
+	if _, err := s.conn.Write(data); err != nil {
+		return fmt.Errorf("failed to send syslog message: %v", err)
+	}
+	return nil
+}
+
+func (s *Sender) Close() error {
+	return s.conn.Close()
+}
+
+// Format renders msg as an RFC5424 message:
+// <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD] MSG
+func (s *Sender) Format(msg Message) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<%d>1 %s %s %s %d %s ",
+		facilityLocal0*8+msg.Severity,
+		msg.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+		headerField(s.hostname, 255), appName, s.pid, headerField(msg.MsgID, 32))
+
+	if len(msg.Params) == 0 {
+		sb.WriteString("-")
+	} else {
+		names := make([]string, 0, len(msg.Params))
+		for name := range msg.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		sb.WriteString("[" + sdID)
+		for _, name := range names {
+			fmt.Fprintf(&sb, ` %s="%s"`, name, escapeParam(msg.Params[name]))
+		}
+		sb.WriteString("]")
+	}
+
+	if msg.Body != "" {
+		sb.WriteString(" " + msg.Body)
+	}
+	return []byte(sb.String())
+}
+
+// headerField keeps a header value to printable ASCII without spaces, using
+// the nil value "-" when it's empty
+func headerField(value string, maxLen int) string {
+	value = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return '_'
+		}
+		return r
+	}, value)
+	if value == "" {
+		return "-"
+	}
+	if len(value) > maxLen {
+		value = value[:maxLen]
+	}
+	return value
+}
+
+// escapeParam escapes the characters RFC5424 reserves in parameter values
+func escapeParam(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`).Replace(value)
+}
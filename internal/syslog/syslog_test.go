@@ -0,0 +1,126 @@
+// internal/syslog/syslog_test.go
+package syslog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testMessage = Message{
+	Time:     time.Date(2024, 1, 15, 10, 30, 0, 123000000, time.UTC),
+	Severity: SeverityWarning,
+	MsgID:    "Decrypt",
+	Params:   map[string]string{"user": "alice", "errorCode": "AccessDenied", "note": `a "quoted" [x] \ value`},
+	Body:     `{"eventName":"Decrypt"}`,
+}
+
+func TestSendUDP(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	sender, err := Dial(listener.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.Close()
+	if err := sender.Send(testMessage); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, maxUDPMessage)
+	listener.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := listener.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hostname, _ := os.Hostname()
+	want := fmt.Sprintf(`<132>1 2024-01-15T10:30:00.123Z %s cloudtrail-logs %d Decrypt `, headerField(hostname, 255), os.Getpid()) +
+		`[cloudtrail@32473 errorCode="AccessDenied" note="a \"quoted\" [x\] \\ value" user="alice"] {"eventName":"Decrypt"}`
+	if got := string(buf[:n]); got != want {
+		t.Errorf("received\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSendTCPFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- err.Error()
+			return
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var length int
+		reader := bufio.NewReader(conn)
+		if _, err := fmt.Fscanf(reader, "%d ", &length); err != nil {
+			received <- err.Error()
+			return
+		}
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(reader, frame); err != nil {
+			received <- err.Error()
+			return
+		}
+		received <- string(frame)
+	}()
+
+	sender, err := Dial("tcp://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sender.Close()
+	msg := Message{Time: testMessage.Time, Severity: SeverityInformational, MsgID: "Encrypt"}
+	if err := sender.Send(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := <-received, string(sender.Format(msg)); got != want {
+		t.Errorf("framed message = %q, want %q", got, want)
+	}
+}
+
+func TestFormatNilValues(t *testing.T) {
+	s := &Sender{hostname: "host", pid: 42}
+	got := string(s.Format(Message{Time: testMessage.Time, Severity: SeverityInformational, MsgID: "with space"}))
+	if want := "<134>1 2024-01-15T10:30:00.123Z host cloudtrail-logs 42 with_space -"; got != want {
+		t.Errorf("Format = %q, want %q", got, want)
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address, network, hostPort string
+	}{
+		{"collector:514", "udp", "collector:514"},
+		{"udp://collector:514", "udp", "collector:514"},
+		{"tcp://10.0.0.1:6514", "tcp", "10.0.0.1:6514"},
+	}
+	for _, tc := range tests {
+		network, hostPort, err := ParseAddress(tc.address)
+		if err != nil || network != tc.network || hostPort != tc.hostPort {
+			t.Errorf("ParseAddress(%s) = %s, %s, %v", tc.address, network, hostPort, err)
+		}
+	}
+	for _, address := range []string{"collector", "tls://collector:514", ""} {
+		if _, _, err := ParseAddress(address); err == nil || !strings.Contains(err.Error(), "invalid syslog") {
+			t.Errorf("ParseAddress(%q) = %v, want an error", address, err)
+		}
+	}
+}
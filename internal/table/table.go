@@ -0,0 +1,121 @@
+// internal/table/table.go
+package table
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Width used when the terminal size can't be determined
+const DefaultWidth = 120
+
+// Columns are never squeezed narrower than this
+const minColumnWidth = 5
+
+const ellipsis = "…"
+
+// Render writes rows as a bordered table no wider than width, truncating the
+// widest cells with an ellipsis until the table fits
+func Render(out io.Writer, headers []string, rows [][]string, width int) {
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = utf8.RuneCountInString(header)
+	}
+	for _, row := range rows {
+		for i := range headers {
+			if i < len(row) {
+				widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+			}
+		}
+	}
+	fit(widths, width)
+
+	border := borderLine(widths)
+	io.WriteString(out, border)
+	io.WriteString(out, rowLine(headers, widths))
+	io.WriteString(out, border)
+	for _, row := range rows {
+		io.WriteString(out, rowLine(row, widths))
+	}
+	io.WriteString(out, border)
+}
+
+// fit shrinks the widest column one character at a time until the table,
+// including borders and padding, fits in width
+func fit(widths []int, width int) {
+	for total(widths) > width {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+// total is the rendered width: "| " + cells joined by " | " + " |"
+func total(widths []int) int {
+	sum := 1
+	for _, w := range widths {
+		sum += w + 3
+	}
+	return sum
+}
+
+func borderLine(widths []int) string {
+	var sb strings.Builder
+	sb.WriteString("+")
+	for _, w := range widths {
+		sb.WriteString(strings.Repeat("-", w+2))
+		sb.WriteString("+")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+func rowLine(cells []string, widths []int) string {
+	var sb strings.Builder
+	sb.WriteString("|")
+	for i, w := range widths {
+		cell := ""
+		if i < len(cells) {
+			cell = truncate(cells[i], w)
+		}
+		sb.WriteString(" ")
+		sb.WriteString(cell)
+		sb.WriteString(strings.Repeat(" ", w-utf8.RuneCountInString(cell)))
+		sb.WriteString(" |")
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// truncate shortens s to width runes, ending in an ellipsis when cut.
+// Newlines and tabs are flattened so a cell stays on one line.
+func truncate(s string, width int) string {
+	s = strings.NewReplacer("\n", " ", "\r", " ", "\t", " ").Replace(s)
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + ellipsis
+}
+
+// TerminalWidth returns the width of the terminal attached to f, then
+// $COLUMNS, falling back to DefaultWidth
+func TerminalWidth(f *os.File) int {
+	if width, ok := terminalWidth(f); ok && width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return DefaultWidth
+}
@@ -0,0 +1,77 @@
+// internal/table/table_test.go
+package table
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRender(t *testing.T) {
+	var out bytes.Buffer
+	Render(&out, []string{"Event", "User"}, [][]string{{"Decrypt", "alice"}, {"ScheduleKeyDeletion", "bob"}}, 80)
+
+	want := `+---------------------+-------+
+| Event               | User  |
++---------------------+-------+
+| Decrypt             | alice |
+| ScheduleKeyDeletion | bob   |
++---------------------+-------+
+`
+	if got := out.String(); got != want {
+		t.Errorf("Render =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTruncatesToWidth(t *testing.T) {
+	var out bytes.Buffer
+	Render(&out, []string{"Event", "User"}, [][]string{{"ScheduleKeyDeletion", "arn:aws:iam::123456789012:role/deploy"}}, 40)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(line); n != 40 {
+			t.Errorf("line is %d wide, want 40: %q", n, line)
+		}
+	}
+	// The widest column gives way until the two are level
+	if row := lines[3]; row != "| ScheduleKeyDele… | arn:aws:iam::123… |" {
+		t.Errorf("row = %q, want both cells truncated with an ellipsis", row)
+	}
+}
+
+func TestRenderMinimumColumnWidth(t *testing.T) {
+	var out bytes.Buffer
+	Render(&out, []string{"Event", "User"}, [][]string{{"ScheduleKeyDeletion", "alice"}}, 5)
+	if row := strings.Split(out.String(), "\n")[3]; row != "| Sche… | alice |" {
+		t.Errorf("row = %q, want columns kept at the minimum width", row)
+	}
+}
+
+func TestTruncateFlattensWhitespace(t *testing.T) {
+	if got := truncate("line one\nline\ttwo", 20); got != "line one line two" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := truncate("abcdef", 4); got != "abc…" {
+		t.Errorf("truncate = %q, want abc…", got)
+	}
+}
+
+func TestTerminalWidthFallback(t *testing.T) {
+	// A regular file is never a terminal
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	t.Setenv("COLUMNS", "97")
+	if got := TerminalWidth(f); got != 97 {
+		t.Errorf("TerminalWidth = %d, want $COLUMNS", got)
+	}
+	t.Setenv("COLUMNS", "")
+	if got := TerminalWidth(f); got != DefaultWidth {
+		t.Errorf("TerminalWidth = %d, want DefaultWidth", got)
+	}
+}
@@ -0,0 +1,10 @@
+// internal/table/width_other.go
+//go:build !unix
+
+package table
+
+import "os"
+
+func terminalWidth(f *os.File) (int, bool) {
+	return 0, false
+}
@@ -0,0 +1,22 @@
+// internal/terminal/terminal.go
+package terminal
+
+import (
+	"os"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+)
+
+// Interactive reports whether f is a terminal a person is watching. Pipes,
+// redirected files, cron, and CI runs (CI set, even with a pseudo-terminal)
+// get plain output without colors or progress updates.
+func Interactive(f *os.File) bool {
+	if ci := strings.ToLower(os.Getenv("CI")); ci != "" && ci != "false" && ci != "0" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
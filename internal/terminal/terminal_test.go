@@ -0,0 +1,31 @@
+// internal/terminal/terminal_test.go
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNonTerminalsArentInteractive(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	file, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	t.Setenv("CI", "")
+	t.Setenv("TERM", "xterm-256color")
+	for name, f := range map[string]*os.File{"pipe": w, "redirected file": file} {
+		if Interactive(f) {
+			t.Errorf("%s treated as a terminal", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// internal/theme/theme.go
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Semantic colors shared by all console output
+var (
+	Error     = color.New(color.FgRed).SprintFunc()
+	Warning   = color.New(color.FgYellow).SprintFunc()
+	Success   = color.New(color.FgGreen).SprintFunc()
+	Info      = color.New(color.FgCyan).SprintFunc()
+	Highlight = color.New(color.FgMagenta, color.Bold).SprintFunc()
+)
+
+// SetColorEnabled turns ANSI colors on or off for all themed output
+func SetColorEnabled(enabled bool) {
+	color.NoColor = !enabled
+}
+
+// Legend describes what each color means
+func Legend() string {
+	entries := []struct {
+		paint   func(a ...interface{}) string
+		name    string
+		meaning string
+	}{
+		{Success, "success", "event completed without an error"},
+		{Error, "error", "event failed (has an errorCode) or an alert fired"},
+		{Warning, "warning", "tool warnings, skipped or incomplete events, anomalies"},
+		{Info, "info", "headers and run information"},
+		{Highlight, "highlight", "the resource you searched for, e.g. the target key"},
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Color Legend:\n")
+	for _, entry := range entries {
+		sb.WriteString(fmt.Sprintf("  %s  %s\n", entry.paint(fmt.Sprintf("%-9s", entry.name)), entry.meaning))
+	}
+	return sb.String()
+}
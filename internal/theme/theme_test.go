@@ -0,0 +1,62 @@
+// internal/theme/theme_test.go
+package theme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+// withColor runs fn with colors forced on or off, restoring the setting after
+func withColor(t *testing.T, enabled bool, fn func()) {
+	t.Helper()
+	saved := color.NoColor
+	defer func() { color.NoColor = saved }()
+	SetColorEnabled(enabled)
+	fn()
+}
+
+func TestLegendWithoutColor(t *testing.T) {
+	withColor(t, false, func() {
+		legend := Legend()
+		if strings.Contains(legend, "\x1b[") {
+			t.Errorf("legend has escape codes with color disabled: %q", legend)
+		}
+		want := []string{
+			"Color Legend:",
+			"  success    event completed without an error",
+			"  error      event failed (has an errorCode) or an alert fired",
+			"  warning    tool warnings, skipped or incomplete events, anomalies",
+			"  info       headers and run information",
+			"  highlight  the resource you searched for, e.g. the target key",
+		}
+		if got := strings.Split(strings.TrimSuffix(legend, "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+			t.Errorf("legend =\n%s\nwant\n%s", legend, strings.Join(want, "\n"))
+		}
+	})
+}
+
+func TestLegendWithColor(t *testing.T) {
+	withColor(t, true, func() {
+		legend := Legend()
+		for name, code := range map[string]string{"error": "\x1b[31m", "warning": "\x1b[33m", "success": "\x1b[32m"} {
+			if !strings.Contains(legend, code+name) {
+				t.Errorf("%s isn't painted with %q in %q", name, code, legend)
+			}
+		}
+	})
+}
+
+func TestSetColorEnabled(t *testing.T) {
+	withColor(t, false, func() {
+		if got := Error("failed"); got != "failed" {
+			t.Errorf("Error with color disabled = %q, want plain text", got)
+		}
+	})
+	withColor(t, true, func() {
+		if got := Error("failed"); got != "\x1b[31mfailed\x1b[0m" {
+			t.Errorf("Error with color enabled = %q, want it in red", got)
+		}
+	})
+}
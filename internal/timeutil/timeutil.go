@@ -15,20 +15,56 @@ type TimeRange struct {
 	Display  string
 }
 
-// RelativeTimeRange parses and validates a relative time range (e.g., "5m", "2h")
+// location is the zone --start/--end are read in and times are shown in
+var location = time.UTC
+
+// SetLocation selects the time zone by IANA name (e.g. "America/New_York"),
+// "UTC", or "Local"
+func SetLocation(name string) error {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("invalid --timezone %q: %v", name, err)
+	}
+	location = loc
+	return nil
+}
+
+// Location returns the zone set with SetLocation, UTC by default
+func Location() *time.Location {
+	return location
+}
+
+// Now returns the current time in the configured zone
+func Now() time.Time {
+	return time.Now().In(location)
+}
+
+// FormatTime renders t in the configured zone as YYYY-MM-DD HH:mm:ss
+func FormatTime(t time.Time) string {
+	return t.In(location).Format("2006-01-02 15:04:05")
+}
+
+// MaxRange is the longest window that can be scanned: CloudTrail event
+// history keeps 90 days of management events
+const MaxRange = 90 * 24 * time.Hour
+
+// RelativeTimeRange parses and validates a relative time range (e.g., "5m", "2h", "7d")
 func RelativeTimeRange(timeRange string) (time.Time, time.Time, error) {
-	now := time.Now()
+	return relativeTimeRange(timeRange, Now())
+}
 
-	// Regular expression to match number + unit (m for minutes, h for hours)
-	re := regexp.MustCompile(`^(\d+)(m|h)$`)
+func relativeTimeRange(timeRange string, now time.Time) (time.Time, time.Time, error) {
+	// Regular expression to match number + unit (m for minutes, h for hours, d for days)
+	re := regexp.MustCompile(`^(\d+)(m|h|d)$`)
 	matches := re.FindStringSubmatch(timeRange)
 
 	if matches == nil {
 		return time.Time{}, time.Time{}, fmt.Errorf(
-			"invalid time range format. Use: "+
-				"\n  - Minutes: e.g., '5m' for last 5 minutes"+
-				"\n  - Hours: e.g., '2h' for last 2 hours"+
-				"\n  Maximum allowed: 24h")
+			"invalid time range format. Use: " +
+				"\n  - Minutes: e.g., '5m' for last 5 minutes" +
+				"\n  - Hours: e.g., '2h' for last 2 hours" +
+				"\n  - Days: e.g., '7d' for last 7 days" +
+				"\n  Maximum allowed: 90d")
 	}
 
 	value, _ := strconv.Atoi(matches[1])
@@ -37,23 +73,28 @@ func RelativeTimeRange(timeRange string) (time.Time, time.Time, error) {
 	var duration time.Duration
 	switch unit {
 	case "m":
-		if value <= 0 || value > 1440 { // 1440 minutes = 24 hours
-			return time.Time{}, time.Time{}, fmt.Errorf("minutes must be between 1 and 1440 (24 hours)")
+		if value <= 0 || value > 129600 { // 129600 minutes = 90 days
+			return time.Time{}, time.Time{}, fmt.Errorf("minutes must be between 1 and 129600 (90 days)")
 		}
 		duration = time.Duration(value) * time.Minute
 	case "h":
-		if value <= 0 || value > 24 {
-			return time.Time{}, time.Time{}, fmt.Errorf("hours must be between 1 and 24")
+		if value <= 0 || value > 2160 {
+			return time.Time{}, time.Time{}, fmt.Errorf("hours must be between 1 and 2160 (90 days)")
 		}
 		duration = time.Duration(value) * time.Hour
+	case "d":
+		if value <= 0 || value > 90 {
+			return time.Time{}, time.Time{}, fmt.Errorf("days must be between 1 and 90 (CloudTrail keeps 90 days of event history)")
+		}
+		duration = time.Duration(value) * 24 * time.Hour
 	default:
-		return time.Time{}, time.Time{}, fmt.Errorf("unsupported time unit. Use 'm' for minutes or 'h' for hours")
+		return time.Time{}, time.Time{}, fmt.Errorf("unsupported time unit. Use 'm' for minutes, 'h' for hours, or 'd' for days")
 	}
 
 	return now.Add(-duration), now, nil
 }
 
-// CustomTimeRange parses custom start and end times
+// CustomTimeRange parses custom start and end times in the configured zone
 func CustomTimeRange(start, end string) (time.Time, time.Time, error) {
 	layouts := []string{
 		"2006-01-02 15:04:05",
@@ -68,7 +109,7 @@ func CustomTimeRange(start, end string) (time.Time, time.Time, error) {
 
 	// Try each layout for start time
 	for _, layout := range layouts {
-		startTime, err = time.Parse(layout, start)
+		startTime, err = time.ParseInLocation(layout, start, location)
 		if err == nil {
 			startParsed = true
 			break
@@ -76,15 +117,15 @@ func CustomTimeRange(start, end string) (time.Time, time.Time, error) {
 	}
 
 	if !startParsed {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid start time format. Use one of:"+
-			"\n  - YYYY-MM-DD HH:mm:ss"+
-			"\n  - YYYY-MM-DD HH:mm"+
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start time format. Use one of:" +
+			"\n  - YYYY-MM-DD HH:mm:ss" +
+			"\n  - YYYY-MM-DD HH:mm" +
 			"\n  - YYYY-MM-DD")
 	}
 
 	// Try each layout for end time
 	for _, layout := range layouts {
-		endTime, err = time.Parse(layout, end)
+		endTime, err = time.ParseInLocation(layout, end, location)
 		if err == nil {
 			endParsed = true
 			break
@@ -92,15 +133,16 @@ func CustomTimeRange(start, end string) (time.Time, time.Time, error) {
 	}
 
 	if !endParsed {
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time format. Use one of:"+
-			"\n  - YYYY-MM-DD HH:mm:ss"+
-			"\n  - YYYY-MM-DD HH:mm"+
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time format. Use one of:" +
+			"\n  - YYYY-MM-DD HH:mm:ss" +
+			"\n  - YYYY-MM-DD HH:mm" +
 			"\n  - YYYY-MM-DD")
 	}
 
-	// If only date was provided, set end time to end of day
+	// If only date was provided, set end time to end of day. Days aren't
+	// always 24 hours long in zones with DST, so count back from midnight.
 	if strings.Contains(end, ":") == false {
-		endTime = endTime.Add(23 * time.Hour).Add(59 * time.Minute).Add(59 * time.Second)
+		endTime = endTime.AddDate(0, 0, 1).Add(-time.Second)
 	}
 
 	// Validate time range
@@ -109,8 +151,8 @@ func CustomTimeRange(start, end string) (time.Time, time.Time, error) {
 		return time.Time{}, time.Time{}, fmt.Errorf("end time cannot be before start time")
 	}
 
-	if duration > 24*time.Hour {
-		return time.Time{}, time.Time{}, fmt.Errorf("time range cannot exceed 24 hours")
+	if duration > MaxRange {
+		return time.Time{}, time.Time{}, fmt.Errorf("time range cannot exceed 90 days (CloudTrail keeps 90 days of event history)")
 	}
 
 	return startTime, endTime, nil
@@ -136,13 +178,79 @@ func FormatDuration(d time.Duration) string {
 	return strings.Join(parts, " ")
 }
 
+// Calendar-aligned named ranges
+const (
+	Today     = "today"
+	Yesterday = "yesterday"
+	ThisWeek  = "this-week"
+	LastWeek  = "last-week"
+	ThisMonth = "this-month"
+	LastMonth = "last-month"
+)
+
+// IsNamedRange reports whether name is one of the calendar-aligned ranges
+func IsNamedRange(name string) bool {
+	switch name {
+	case Today, Yesterday, ThisWeek, LastWeek, ThisMonth, LastMonth:
+		return true
+	}
+	return false
+}
+
+// NamedTimeRange resolves a calendar-aligned range relative to now, in now's location.
+// Weeks follow ISO 8601 and start on Monday. "today" and "this-" ranges end at
+// now, while "yesterday" and "last-" ranges end one second before the current
+// period starts.
+func NamedTimeRange(name string, now time.Time) (time.Time, time.Time, error) {
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	// Days since Monday (time.Sunday == 0)
+	offset := (int(today.Weekday()) + 6) % 7
+	weekStart := today.AddDate(0, 0, -offset)
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+
+	switch name {
+	case Today:
+		return today, now, nil
+	case Yesterday:
+		return today.AddDate(0, 0, -1), today.Add(-time.Second), nil
+	case ThisWeek:
+		return weekStart, now, nil
+	case LastWeek:
+		return weekStart.AddDate(0, 0, -7), weekStart.Add(-time.Second), nil
+	case ThisMonth:
+		return monthStart, now, nil
+	case LastMonth:
+		// time.Date normalizes month 0 to December of the previous year
+		previous := time.Date(now.Year(), now.Month()-1, 1, 0, 0, 0, 0, loc)
+		return previous, monthStart.Add(-time.Second), nil
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf("unknown named range %q. Use one of: %s, %s, %s, %s, %s, %s",
+			name, Today, Yesterday, ThisWeek, LastWeek, ThisMonth, LastMonth)
+	}
+}
+
 // ValidateAndParseTimeRange handles both relative and custom time ranges
 func ValidateAndParseTimeRange(lastN, start, end string) (time.Time, time.Time, error) {
+	return ParseTimeRange(lastN, start, end, Now())
+}
+
+// ParseTimeRange is the single time-range parser shared by every command.
+// lastN takes a relative duration ("5m", "2h") or a named range ("yesterday",
+// "this-week", ...); otherwise start and end give an absolute range.
+func ParseTimeRange(lastN, start, end string, now time.Time) (time.Time, time.Time, error) {
 	if lastN != "" {
 		if start != "" || end != "" {
 			return time.Time{}, time.Time{}, fmt.Errorf("cannot use --last-n with --start/--end flags")
 		}
-		return RelativeTimeRange(lastN)
+		if expression, ok := presets[lastN]; ok {
+			return presetTimeRange(lastN, expression, now)
+		}
+		if IsNamedRange(lastN) {
+			return NamedTimeRange(lastN, now)
+		}
+		return relativeTimeRange(lastN, now)
 	}
 
 	if (start != "" && end == "") || (start == "" && end != "") {
@@ -154,4 +262,76 @@ func ValidateAndParseTimeRange(lastN, start, end string) (time.Time, time.Time,
 	}
 
 	return CustomTimeRange(start, end)
-}
\ No newline at end of file
+}
+
+// DayRange resolves a single day: "today", "yesterday" (the default when day
+// is empty), or a YYYY-MM-DD date. It resolves exactly as ParseTimeRange does
+// for the same input, with the end clipped to now.
+func DayRange(day string, now time.Time) (time.Time, time.Time, error) {
+	if day == "" {
+		day = Yesterday
+	}
+
+	var start, end time.Time
+	var err error
+	switch day {
+	case Today, Yesterday:
+		start, end, err = ParseTimeRange(day, "", "", now)
+	default:
+		if _, parseErr := time.Parse("2006-01-02", day); parseErr != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD, %s, or %s", day, Today, Yesterday)
+		}
+		start, end, err = ParseTimeRange("", day, day, now)
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !start.Before(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%s is in the future", day)
+	}
+	if end.After(now) {
+		end = now
+	}
+	return start, end, nil
+}
+
+// Named --last-n presets loaded from the config file
+var presets map[string]string
+
+// SetPresets registers named time ranges. Each maps to a relative or named
+// range ("8h", "yesterday"), "since <time>" (until now), or "<start> to <end>".
+// Built-in range names can't be redefined.
+func SetPresets(ranges map[string]string) error {
+	for name := range ranges {
+		if IsNamedRange(name) {
+			return fmt.Errorf("time range preset %q redefines a built-in range", name)
+		}
+	}
+	presets = ranges
+	return nil
+}
+
+// presetTimeRange resolves a preset's expression with the built-in parsers.
+// Presets can't refer to other presets.
+func presetTimeRange(name, expression string, now time.Time) (time.Time, time.Time, error) {
+	expression = strings.TrimSpace(expression)
+	var start, end time.Time
+	var err error
+	switch {
+	case strings.HasPrefix(expression, "since "):
+		// Custom times are read in the configured zone, so "now" is given in it too
+		start, end, err = CustomTimeRange(strings.TrimSpace(strings.TrimPrefix(expression, "since ")), FormatTime(now))
+	case strings.Contains(expression, " to "):
+		from, to, _ := strings.Cut(expression, " to ")
+		start, end, err = CustomTimeRange(strings.TrimSpace(from), strings.TrimSpace(to))
+	case IsNamedRange(expression):
+		start, end, err = NamedTimeRange(expression, now)
+	default:
+		start, end, err = relativeTimeRange(expression, now)
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("time range preset %q (%s): %v", name, expression, err)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,240 @@
+// internal/timeutil/timeutil_test.go
+package timeutil
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day, hour, min, sec int) time.Time {
+	return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+}
+
+func TestNamedTimeRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		// New Year's Day 2024 is a Monday, so every range crosses a year boundary
+		{Today, date(2024, 1, 1, 10, 0, 0), date(2024, 1, 1, 0, 0, 0), date(2024, 1, 1, 10, 0, 0)},
+		{Yesterday, date(2024, 1, 1, 10, 0, 0), date(2023, 12, 31, 0, 0, 0), date(2023, 12, 31, 23, 59, 59)},
+		{ThisWeek, date(2024, 1, 1, 10, 0, 0), date(2024, 1, 1, 0, 0, 0), date(2024, 1, 1, 10, 0, 0)},
+		{LastWeek, date(2024, 1, 1, 10, 0, 0), date(2023, 12, 25, 0, 0, 0), date(2023, 12, 31, 23, 59, 59)},
+		{ThisMonth, date(2024, 1, 1, 10, 0, 0), date(2024, 1, 1, 0, 0, 0), date(2024, 1, 1, 10, 0, 0)},
+		{LastMonth, date(2024, 1, 1, 10, 0, 0), date(2023, 12, 1, 0, 0, 0), date(2023, 12, 31, 23, 59, 59)},
+
+		// Friday 1 March 2024, just after a leap day
+		{Yesterday, date(2024, 3, 1, 8, 0, 0), date(2024, 2, 29, 0, 0, 0), date(2024, 2, 29, 23, 59, 59)},
+		{ThisWeek, date(2024, 3, 1, 8, 0, 0), date(2024, 2, 26, 0, 0, 0), date(2024, 3, 1, 8, 0, 0)},
+		{LastMonth, date(2024, 3, 1, 8, 0, 0), date(2024, 2, 1, 0, 0, 0), date(2024, 2, 29, 23, 59, 59)},
+
+		// Weeks start on Monday, even when today is Sunday
+		{ThisWeek, date(2024, 3, 10, 12, 0, 0), date(2024, 3, 4, 0, 0, 0), date(2024, 3, 10, 12, 0, 0)},
+	}
+	for _, tc := range tests {
+		start, end, err := NamedTimeRange(tc.name, tc.now)
+		if err != nil {
+			t.Errorf("NamedTimeRange(%q, %s): %v", tc.name, tc.now, err)
+			continue
+		}
+		if !start.Equal(tc.wantStart) || !end.Equal(tc.wantEnd) {
+			t.Errorf("NamedTimeRange(%q, %s) = %s to %s, want %s to %s", tc.name, tc.now, start, end, tc.wantStart, tc.wantEnd)
+		}
+	}
+
+	if _, _, err := NamedTimeRange("next-week", date(2024, 1, 1, 0, 0, 0)); err == nil {
+		t.Error("NamedTimeRange(next-week) = nil error, want an error")
+	}
+}
+
+func TestParseTimeRangeTokens(t *testing.T) {
+	now := date(2024, 3, 15, 12, 0, 0) // a Friday
+	tests := []struct {
+		lastN, start, end string
+		wantStart         time.Time
+		wantEnd           time.Time
+	}{
+		{"30m", "", "", date(2024, 3, 15, 11, 30, 0), now},
+		{"6h", "", "", date(2024, 3, 15, 6, 0, 0), now},
+		{"2d", "", "", date(2024, 3, 13, 12, 0, 0), now},
+		{"7d", "", "", date(2024, 3, 8, 12, 0, 0), now},
+		{"90d", "", "", date(2023, 12, 16, 12, 0, 0), now},
+		{"2160h", "", "", date(2023, 12, 16, 12, 0, 0), now},
+		{"129600m", "", "", date(2023, 12, 16, 12, 0, 0), now},
+		{Today, "", "", date(2024, 3, 15, 0, 0, 0), now},
+		{Yesterday, "", "", date(2024, 3, 14, 0, 0, 0), date(2024, 3, 14, 23, 59, 59)},
+		{LastWeek, "", "", date(2024, 3, 4, 0, 0, 0), date(2024, 3, 10, 23, 59, 59)},
+		{"", "2024-03-01 08:30:15", "2024-03-01 09:00", date(2024, 3, 1, 8, 30, 15), date(2024, 3, 1, 9, 0, 0)},
+		{"", "2024-03-01", "2024-03-02", date(2024, 3, 1, 0, 0, 0), date(2024, 3, 2, 23, 59, 59)},
+		{"", "2024-01-01 00:00", "2024-03-31 00:00", date(2024, 1, 1, 0, 0, 0), date(2024, 3, 31, 0, 0, 0)}, // exactly 90 days
+	}
+	for _, tc := range tests {
+		start, end, err := ParseTimeRange(tc.lastN, tc.start, tc.end, now)
+		if err != nil {
+			t.Errorf("ParseTimeRange(%q, %q, %q): %v", tc.lastN, tc.start, tc.end, err)
+			continue
+		}
+		if !start.Equal(tc.wantStart) || !end.Equal(tc.wantEnd) {
+			t.Errorf("ParseTimeRange(%q, %q, %q) = %s to %s, want %s to %s",
+				tc.lastN, tc.start, tc.end, start, end, tc.wantStart, tc.wantEnd)
+		}
+	}
+}
+
+func TestParseTimeRangeErrors(t *testing.T) {
+	now := date(2024, 3, 15, 12, 0, 0)
+	tests := []struct {
+		lastN, start, end string
+		want              string
+	}{
+		{"1h", "2024-03-01", "", "cannot use --last-n with --start/--end"},
+		{"", "2024-03-01", "", "both --start and --end"},
+		{"", "", "", "either --last-n or both --start and --end"},
+		{"5w", "", "", "invalid time range format"},
+		{"91d", "", "", "days must be between 1 and 90"},
+		{"0d", "", "", "days must be between 1 and 90"},
+		{"2161h", "", "", "hours must be between 1 and 2160"},
+		{"129601m", "", "", "minutes must be between 1 and 129600"},
+		{"", "2024-01-01", "2024-03-31", "cannot exceed 90 days"}, // through the end of the 91st day
+		{"", "03/01/2024", "2024-03-02", "invalid start time format"},
+		{"", "2024-03-02", "2024-03-01", "end time cannot be before start time"},
+	}
+	for _, tc := range tests {
+		_, _, err := ParseTimeRange(tc.lastN, tc.start, tc.end, now)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("ParseTimeRange(%q, %q, %q) = %v, want %q", tc.lastN, tc.start, tc.end, err, tc.want)
+		}
+	}
+}
+
+// The digest command's DayRange must resolve a day exactly as the scan
+// commands' ParseTimeRange does
+func TestDayRangeMatchesParseTimeRange(t *testing.T) {
+	now := date(2024, 3, 15, 12, 0, 0)
+	for _, tc := range []struct {
+		day, lastN, start, end string
+	}{
+		{Yesterday, Yesterday, "", ""},
+		{"", Yesterday, "", ""},
+		{"2024-03-01", "", "2024-03-01", "2024-03-01"},
+	} {
+		dayStart, dayEnd, err := DayRange(tc.day, now)
+		if err != nil {
+			t.Fatalf("DayRange(%q): %v", tc.day, err)
+		}
+		start, end, err := ParseTimeRange(tc.lastN, tc.start, tc.end, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !dayStart.Equal(start) || !dayEnd.Equal(end) {
+			t.Errorf("DayRange(%q) = %s to %s, ParseTimeRange = %s to %s", tc.day, dayStart, dayEnd, start, end)
+		}
+	}
+
+	// Today is clipped to now, as ParseTimeRange already does
+	if _, end, _ := DayRange(Today, now); !end.Equal(now) {
+		t.Errorf("DayRange(today) ends %s, want now", end)
+	}
+	if _, _, err := DayRange("2024-03-16", now); err == nil {
+		t.Error("DayRange accepted a future day")
+	}
+	if _, _, err := DayRange("15/03/2024", now); err == nil {
+		t.Error("DayRange accepted a malformed date")
+	}
+}
+
+func TestPresets(t *testing.T) {
+	if err := SetPresets(map[string]string{
+		"business-hours": "2024-03-14 09:00 to 2024-03-14 17:00",
+		"since-deploy":   "since 2024-03-15 08:30",
+		"standup":        "15m",
+		"last-sprint":    LastWeek,
+		"broken":         "since tuesday",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetPresets(nil) })
+
+	now := date(2024, 3, 15, 12, 0, 0)
+	tests := []struct {
+		name      string
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"business-hours", date(2024, 3, 14, 9, 0, 0), date(2024, 3, 14, 17, 0, 0)},
+		{"since-deploy", date(2024, 3, 15, 8, 30, 0), now},
+		{"standup", date(2024, 3, 15, 11, 45, 0), now},
+		{"last-sprint", date(2024, 3, 4, 0, 0, 0), date(2024, 3, 10, 23, 59, 59)},
+	}
+	for _, tc := range tests {
+		start, end, err := ParseTimeRange(tc.name, "", "", now)
+		if err != nil {
+			t.Errorf("preset %s: %v", tc.name, err)
+			continue
+		}
+		if !start.Equal(tc.wantStart) || !end.Equal(tc.wantEnd) {
+			t.Errorf("preset %s = %s to %s, want %s to %s", tc.name, start, end, tc.wantStart, tc.wantEnd)
+		}
+	}
+
+	if _, _, err := ParseTimeRange("broken", "", "", now); err == nil || !strings.Contains(err.Error(), `preset "broken"`) {
+		t.Errorf("broken preset = %v, want an error naming it", err)
+	}
+	// Built-in names are still resolved as before
+	if start, _, err := ParseTimeRange(Today, "", "", now); err != nil || !start.Equal(date(2024, 3, 15, 0, 0, 0)) {
+		t.Errorf("today = %s, %v", start, err)
+	}
+}
+
+func TestPresetsCantRedefineBuiltIns(t *testing.T) {
+	if err := SetPresets(map[string]string{Yesterday: "48h"}); err == nil {
+		t.Error("SetPresets redefined yesterday")
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	if err := SetLocation("America/New_York"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { location = time.UTC })
+	ny := Location()
+
+	// 2024-03-10 is 23 hours long in New York: clocks jump from 02:00 to 03:00
+	start, end, err := CustomTimeRange("2024-03-10", "2024-03-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC); !start.Equal(want) {
+		t.Errorf("start = %s, want %s", start.UTC(), want)
+	}
+	if want := time.Date(2024, 3, 11, 3, 59, 59, 0, time.UTC); !end.Equal(want) {
+		t.Errorf("end = %s, want %s", end.UTC(), want)
+	}
+	if start.Location() != ny || end.Location() != ny {
+		t.Errorf("range parsed in %s and %s, want %s", start.Location(), end.Location(), ny)
+	}
+
+	if start, _, err := CustomTimeRange("2024-07-04 09:30", "2024-07-04 17:00"); err != nil || !start.Equal(time.Date(2024, 7, 4, 13, 30, 0, 0, time.UTC)) {
+		t.Errorf("summer start = %s, %v", start.UTC(), err)
+	}
+
+	if got := FormatTime(time.Date(2024, 1, 15, 17, 0, 0, 0, time.UTC)); got != "2024-01-15 12:00:00" {
+		t.Errorf("FormatTime in winter = %s", got)
+	}
+	if got := FormatTime(time.Date(2024, 7, 15, 17, 0, 0, 0, time.UTC)); got != "2024-07-15 13:00:00" {
+		t.Errorf("FormatTime in summer = %s", got)
+	}
+	if Now().Location() != ny {
+		t.Errorf("Now() in %s, want %s", Now().Location(), ny)
+	}
+
+	if err := SetLocation("Mars/Olympus_Mons"); err == nil || !strings.Contains(err.Error(), "--timezone") {
+		t.Errorf("SetLocation(unknown zone) = %v", err)
+	}
+	if Location() != ny {
+		t.Error("a rejected zone replaced the configured one")
+	}
+}
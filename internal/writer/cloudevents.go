@@ -0,0 +1,54 @@
+// internal/writer/cloudevents.go
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// cloudEvent is a CloudEvents 1.0 envelope in structured JSON mode
+type cloudEvent struct {
+	SpecVersion     string                 `json:"specversion"`
+	Type            string                 `json:"type"`
+	Source          string                 `json:"source"`
+	ID              string                 `json:"id"`
+	Time            string                 `json:"time,omitempty"`
+	Subject         string                 `json:"subject,omitempty"`
+	Sequence        string                 `json:"sequence,omitempty"` // sequence extension
+	DataContentType string                 `json:"datacontenttype"`
+	Data            map[string]interface{} `json:"data"`
+}
+
+// formatCloudEvent wraps the parsed CloudTrail event in a CloudEvents envelope,
+// one compact JSON object per line
+func formatCloudEvent(entry Entry) (string, error) {
+	event, eventDetails := entry.Event, entry.Details
+	eventType := "AwsApiCall"
+	if detailType, ok := eventDetails["eventType"].(string); ok && detailType != "" {
+		eventType = detailType
+	}
+
+	envelope := cloudEvent{
+		SpecVersion:     "1.0",
+		Type:            "com.amazonaws.cloudtrail." + eventType,
+		Source:          SafeString(event.EventSource),
+		ID:              SafeString(event.EventId),
+		Subject:         SafeString(event.EventName),
+		DataContentType: "application/json",
+		Data:            eventDetails,
+	}
+	if entry.Index > 0 {
+		envelope.Sequence = strconv.Itoa(entry.Index)
+	}
+	if event.EventTime != nil {
+		envelope.Time = event.EventTime.UTC().Format(time.RFC3339)
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal CloudEvent: %v", err)
+	}
+	return string(data) + "\n", nil
+}
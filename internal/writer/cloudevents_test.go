@@ -0,0 +1,77 @@
+// internal/writer/cloudevents_test.go
+package writer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCloudEventsExport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.ndjson")
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: FormatCloudEvents})
+	first, second := testEntry("event-1", 1), testEntry("event-2", 2)
+	first.Index, second.Index = 1, 2
+	if err := w.WriteBatch([]Entry{first, second}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one CloudEvent per event:\n%s", len(lines), data)
+	}
+
+	var envelope map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &envelope); err != nil {
+		t.Fatalf("invalid JSON %q: %v", lines[0], err)
+	}
+	want := map[string]interface{}{
+		"specversion":     "1.0",
+		"type":            "com.amazonaws.cloudtrail.AwsApiCall",
+		"source":          "kms.amazonaws.com",
+		"id":              "event-1",
+		"time":            "2024-01-15T00:01:00Z",
+		"subject":         "Decrypt",
+		"sequence":        "1",
+		"datacontenttype": "application/json",
+	}
+	for attribute, value := range want {
+		if envelope[attribute] != value {
+			t.Errorf("%s = %v, want %v", attribute, envelope[attribute], value)
+		}
+	}
+	payload, _ := envelope["data"].(map[string]interface{})
+	if payload["eventID"] != "event-1" {
+		t.Errorf("data = %v, want the parsed CloudTrail event", envelope["data"])
+	}
+}
+
+func TestCloudEventTypeAndOptionalAttributes(t *testing.T) {
+	entry := testEntry("insight-1", 0)
+	entry.Details["eventType"] = "AwsCloudTrailInsight"
+	entry.Event.EventTime = nil
+
+	line, err := formatCloudEvent(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var envelope map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope["type"] != "com.amazonaws.cloudtrail.AwsCloudTrailInsight" {
+		t.Errorf("type = %v, want it taken from eventType", envelope["type"])
+	}
+	for _, attribute := range []string{"time", "sequence"} {
+		if _, ok := envelope[attribute]; ok {
+			t.Errorf("%s present without a value: %s", attribute, line)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// internal/writer/compress.go
+package writer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// compressedName adds the .gz suffix compressed output is written under
+func compressedName(filename string) string {
+	if strings.HasSuffix(filename, ".gz") {
+		return filename
+	}
+	return filename + ".gz"
+}
+
+// writeCompressed streams content through the gzip writer for filename,
+// opening it on first use and again when the target changes (e.g. the date
+// rolls over). An existing file gets a new gzip member appended, which gzip
+// readers treat as one continuous stream. Callers hold w.mu.
+func (w *LogWriter) writeCompressed(filename, content string) error {
+	if w.gz == nil || w.gzPath != filename {
+		if err := w.closeCompressed(); err != nil {
+			return err
+		}
+		var f *os.File
+		err := w.withWriteRetries(func() error {
+			var err error
+			f, err = os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return fmt.Errorf("failed to open log file: %w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		w.gzFile, w.gz, w.gzPath = f, gzip.NewWriter(f), filename
+	}
+
+	if _, err := io.WriteString(w.gz, content); err != nil {
+		return fmt.Errorf("failed to write to log file: %v", err)
+	}
+	// A size limit needs the file's real size, so nothing may stay buffered
+	if w.maxFileSize > 0 {
+		if err := w.gz.Flush(); err != nil {
+			return fmt.Errorf("failed to write to log file: %v", err)
+		}
+	}
+	return nil
+}
+
+// closeCompressed flushes the gzip stream and closes its file. Callers hold w.mu.
+func (w *LogWriter) closeCompressed() error {
+	if w.gz == nil {
+		return nil
+	}
+	err := w.gz.Close()
+	if closeErr := w.gzFile.Close(); err == nil {
+		err = closeErr
+	}
+	w.gz, w.gzFile, w.gzPath = nil, nil, ""
+	if err != nil {
+		return fmt.Errorf("failed to finish compressed log file: %v", err)
+	}
+	return nil
+}
+
+// gzipBytes compresses a whole file's contents at once
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress returns data unchanged unless it starts with the gzip magic
+// bytes, so readers accept compressed and plain logs alike
+func decompress(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip log: %v", err)
+	}
+	defer gz.Close()
+	plain, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip log: %v", err)
+	}
+	return plain, nil
+}
@@ -0,0 +1,115 @@
+// internal/writer/compress_test.go
+package writer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gunzipFile reads a whole gzip file, every member of it
+func gunzipFile(t *testing.T, file string) []byte {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("%s isn't gzip: %v", file, err)
+	}
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatalf("%s is a truncated gzip stream: %v", file, err)
+	}
+	return data
+}
+
+func TestCompressedRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatText, FormatJSON, FormatNDJSON, FormatJSONDocument} {
+		t.Run(format, func(t *testing.T) {
+			entries := testPage(5)
+			file := filepath.Join(t.TempDir(), "events.log")
+			w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: format, Compress: true})
+			if got := w.GetCurrentFile(); got != file+".gz" {
+				t.Errorf("GetCurrentFile = %s, want the .gz name", got)
+			}
+			if err := w.WriteEntry(entries[0]); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.WriteBatch(entries[1:]); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(file); !os.IsNotExist(err) {
+				t.Errorf("uncompressed %s written too", file)
+			}
+
+			// The decompressed file is exactly what an uncompressed export holds
+			plain := exportEntries(t, format, entries)
+			got := gunzipFile(t, file+".gz")
+			if format == FormatJSONDocument {
+				// Only the generation time differs
+				got, plain = withoutGeneratedAt(got), withoutGeneratedAt(plain)
+			}
+			if !bytes.Equal(got, plain) {
+				t.Errorf("decompressed export differs from the plain one:\n%s\n---\n%s", got, plain)
+			}
+
+			compressed, err := os.Open(file + ".gz")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer compressed.Close()
+			back, err := ReadEntries(compressed, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(back) != len(entries) {
+				t.Fatalf("read back %d entries, want %d", len(back), len(entries))
+			}
+			for i, entry := range back {
+				if summary(entry) != summary(entries[i]) {
+					t.Errorf("entry %d = %s, want %s", i, summary(entry), summary(entries[i]))
+				}
+			}
+		})
+	}
+}
+
+// withoutGeneratedAt drops the generatedAt line of an indented JSON document
+func withoutGeneratedAt(data []byte) []byte {
+	var kept []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.Contains(line, `"generatedAt"`) {
+			kept = append(kept, line)
+		}
+	}
+	return []byte(strings.Join(kept, "\n"))
+}
+
+func TestCompressedAppendAcrossRuns(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.log")
+	for run, id := range []string{"event-1", "event-2"} {
+		w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: FormatNDJSON, Compress: true})
+		if err := w.WriteEntry(testEntry(id, run)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The second run appends a gzip member, read as one stream
+	lines := strings.Split(strings.TrimSpace(string(gunzipFile(t, file+".gz"))), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "event-1") || !strings.Contains(lines[1], "event-2") {
+		t.Errorf("got %q, want both runs' events in order", lines)
+	}
+}
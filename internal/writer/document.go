@@ -0,0 +1,82 @@
+// internal/writer/document.go
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// documentMetadata describes the run that produced a json-document export
+type documentMetadata struct {
+	Service     string     `json:"service"`
+	Region      string     `json:"region,omitempty"`
+	Profile     string     `json:"profile,omitempty"`
+	StartTime   *time.Time `json:"startTime,omitempty"`
+	EndTime     *time.Time `json:"endTime,omitempty"`
+	GeneratedAt time.Time  `json:"generatedAt"`
+	EventCount  int        `json:"eventCount"`
+}
+
+type document struct {
+	Metadata documentMetadata         `json:"metadata"`
+	Events   []map[string]interface{} `json:"events"`
+}
+
+// SetTimeRange records the scanned window for the json-document metadata
+func (w *LogWriter) SetTimeRange(start, end time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.windowStart, w.windowEnd = &start, &end
+}
+
+// writeDocument renders the buffered events as one JSON document, replacing
+// any previous contents since a second document can't be appended to the first
+func (w *LogWriter) writeDocument() error {
+	doc := document{
+		Metadata: documentMetadata{
+			Service:     w.serviceTag,
+			Region:      w.region,
+			Profile:     w.profile,
+			StartTime:   w.windowStart,
+			EndTime:     w.windowEnd,
+			GeneratedAt: time.Now().UTC(),
+			EventCount:  len(w.documentEvents),
+		},
+		Events: w.documentEvents,
+	}
+	if doc.Events == nil {
+		doc.Events = []map[string]interface{}{}
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON document: %v", err)
+	}
+	data = append(data, '\n')
+
+	if w.streamMode != 0 {
+		if err := w.writeStream(string(data)); err != nil {
+			return err
+		}
+		w.recordFile(w.customFile, "", len(w.documentEvents))
+		return nil
+	}
+
+	filename := w.currentFile("")
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %v", err)
+	}
+	if w.compress {
+		if data, err = gzipBytes(data); err != nil {
+			return fmt.Errorf("failed to compress JSON document: %v", err)
+		}
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JSON document: %v", err)
+	}
+	w.recordFile(filename, "", len(w.documentEvents))
+	return nil
+}
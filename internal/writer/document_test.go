@@ -0,0 +1,82 @@
+// internal/writer/document_test.go
+package writer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func readDocument(t *testing.T, file string) document {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid JSON document: %v\n%s", err, data)
+	}
+	return doc
+}
+
+func TestJSONDocumentExport(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.json")
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: FormatJSONDocument, Region: "eu-west-1", Profile: "prod"})
+	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	w.SetTimeRange(start, start.Add(24*time.Hour))
+	if err := w.WriteEntry(testEntry("event-1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteBatch([]Entry{testEntry("event-2", 2), testEntry("event-3", 3)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	doc := readDocument(t, file)
+	meta := doc.Metadata
+	if meta.Service != "kms" || meta.Region != "eu-west-1" || meta.Profile != "prod" || meta.EventCount != 3 {
+		t.Errorf("metadata = %+v", meta)
+	}
+	if meta.StartTime == nil || !meta.StartTime.Equal(start) || meta.EndTime == nil || !meta.EndTime.Equal(start.Add(24*time.Hour)) {
+		t.Errorf("time range = %v - %v, want the scanned day", meta.StartTime, meta.EndTime)
+	}
+	if meta.GeneratedAt.IsZero() {
+		t.Error("generatedAt not set")
+	}
+
+	if len(doc.Events) != 3 {
+		t.Fatalf("got %d events, want 3", len(doc.Events))
+	}
+	for i, id := range []string{"event-1", "event-2", "event-3"} {
+		details, _ := doc.Events[i]["details"].(map[string]interface{})
+		if details["eventID"] != id {
+			t.Errorf("event %d = %v, want %s", i, doc.Events[i], id)
+		}
+	}
+}
+
+func TestEmptyJSONDocument(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.json")
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: FormatJSONDocument})
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	// An empty array rather than null, so consumers can range over it
+	if string(raw["events"]) != "[]" {
+		t.Errorf("events = %s, want []", raw["events"])
+	}
+}
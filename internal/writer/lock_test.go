@@ -0,0 +1,62 @@
+// internal/writer/lock_test.go
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockRejectsSecondWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "events.json")
+	first := NewLogWriter("", "kms", &ExportOptions{Filename: file})
+	second := NewLogWriter("", "kms", &ExportOptions{Filename: file})
+
+	if err := first.Lock(); err != nil {
+		t.Fatalf("first Lock: %v", err)
+	}
+
+	started := time.Now()
+	err := second.Lock()
+	if err == nil || !strings.Contains(err.Error(), "in use by another cloudtrail-logs run") {
+		t.Fatalf("second Lock = %v, want an in-use error", err)
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Errorf("second Lock took %s to fail, want it to fail fast", elapsed)
+	}
+
+	// Once released, the sidecar is gone and the file can be locked again
+	if err := first.Unlock(); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	if _, err := os.Stat(file + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind after Unlock (stat error %v)", err)
+	}
+	if err := second.Lock(); err != nil {
+		t.Fatalf("Lock after release: %v", err)
+	}
+	second.Unlock()
+}
+
+func TestUnlockWithoutLock(t *testing.T) {
+	w := NewLogWriter(t.TempDir(), "kms", nil)
+	if err := w.Unlock(); err != nil {
+		t.Errorf("Unlock without Lock = %v, want nil", err)
+	}
+}
+
+func TestLockUncreatableDirectory(t *testing.T) {
+	// A regular file where the output directory should be
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewLogWriter(blocker, "kms", &ExportOptions{})
+	err := w.Lock()
+	if err == nil || !strings.Contains(err.Error(), "can't be created") || !strings.Contains(err.Error(), "--output") {
+		t.Fatalf("Lock = %v, want a clear error before any event is written", err)
+	}
+}
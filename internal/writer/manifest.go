@@ -0,0 +1,91 @@
+// internal/writer/manifest.go
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ProducedFile describes one file the writer created or appended to
+type ProducedFile struct {
+	Path   string `json:"path"`
+	Format string `json:"format"`
+	Region string `json:"region,omitempty"`
+	Events int    `json:"events"`
+	Bytes  int64  `json:"bytes,omitempty"`
+}
+
+type manifest struct {
+	GeneratedAt time.Time      `json:"generatedAt"`
+	TotalEvents int            `json:"totalEvents"`
+	Files       []ProducedFile `json:"files"`
+}
+
+// recordFile counts events from region (empty for the writer's own) written
+// to path. A file holding several regions' events isn't attributed to any.
+// Callers hold w.mu.
+func (w *LogWriter) recordFile(path, region string, events int) {
+	if region == "" {
+		region = w.region
+	}
+	if w.producedIndex == nil {
+		w.producedIndex = make(map[string]int)
+	}
+	i, ok := w.producedIndex[path]
+	if !ok {
+		format := w.exportMode
+		if format == "" {
+			format = FormatText
+		}
+		i = len(w.produced)
+		w.producedIndex[path] = i
+		w.produced = append(w.produced, ProducedFile{Path: path, Format: format, Region: region})
+	} else if w.produced[i].Region != region {
+		w.produced[i].Region = ""
+	}
+	w.produced[i].Events += events
+}
+
+// Files lists the files written so far in the order they were first used,
+// with their current size on disk
+func (w *LogWriter) Files() []ProducedFile {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	files := make([]ProducedFile, len(w.produced))
+	copy(files, w.produced)
+	for i := range files {
+		if info, err := os.Stat(files[i].Path); err == nil && info.Mode().IsRegular() {
+			files[i].Bytes = info.Size()
+		}
+	}
+	return files
+}
+
+// WriteManifest writes a JSON index of the produced files to path
+func WriteManifest(path string, files []ProducedFile) error {
+	m := manifest{GeneratedAt: time.Now().UTC(), Files: files}
+	if m.Files == nil {
+		m.Files = []ProducedFile{}
+	}
+	for _, f := range files {
+		m.TotalEvents += f.Events
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %v", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create manifest directory: %v", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// internal/writer/manifest_test.go
+package writer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManifestListsEveryFile(t *testing.T) {
+	dir := t.TempDir()
+	w := NewLogWriter(dir, "kms", &ExportOptions{RegionDirs: true, Region: "us-east-1", FilenameTemplate: "events.log", Format: FormatNDJSON})
+	var entries []Entry
+	for i, region := range []string{"us-east-1", "eu-west-1", "us-east-1", "ap-south-1", "us-east-1"} {
+		entry := testEntry("event", i)
+		entry.Region = region
+		entries = append(entries, entry)
+	}
+	if err := w.WriteBatch(entries); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	path := filepath.Join(dir, "out", "manifest.json")
+	if err := WriteManifest(path, w.Files()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got manifest
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid manifest: %v\n%s", err, data)
+	}
+
+	want := []struct {
+		region string
+		events int
+	}{{"us-east-1", 3}, {"eu-west-1", 1}, {"ap-south-1", 1}}
+	if got.TotalEvents != 5 || len(got.Files) != len(want) {
+		t.Fatalf("manifest lists %d files with %d events, want 3 files with 5:\n%s", len(got.Files), got.TotalEvents, data)
+	}
+	for i, file := range got.Files {
+		wantPath := filepath.Join(dir, "kms", want[i].region, "events.log")
+		if file.Path != wantPath || file.Region != want[i].region || file.Events != want[i].events || file.Format != FormatNDJSON {
+			t.Errorf("file %d = %+v, want %s with %d events", i, file, wantPath, want[i].events)
+		}
+		if info, err := os.Stat(file.Path); err != nil || info.Size() != file.Bytes {
+			t.Errorf("file %d size = %d, want the size on disk (%v)", i, file.Bytes, err)
+		}
+	}
+	if got.GeneratedAt.IsZero() {
+		t.Error("generatedAt not set")
+	}
+}
+
+func TestEmptyManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := WriteManifest(path, nil); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	if string(raw["files"]) != "[]" || string(raw["totalEvents"]) != "0" {
+		t.Errorf("empty manifest = %s", data)
+	}
+}
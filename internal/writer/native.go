@@ -0,0 +1,114 @@
+// internal/writer/native.go
+package writer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// nativeEvent carries every field of the SDK's types.Event under stable
+// camelCase tags, with the CloudTrailEvent body as nested JSON rather than an
+// escaped string. Unlike the json format nothing is summarized or renamed, so
+// it round-trips back to the same event.
+type nativeEvent struct {
+	EventID         string                 `json:"eventId,omitempty"`
+	EventName       string                 `json:"eventName,omitempty"`
+	EventSource     string                 `json:"eventSource,omitempty"`
+	EventTime       *time.Time             `json:"eventTime,omitempty"` // RFC 3339
+	Username        string                 `json:"username,omitempty"`
+	AccessKeyID     string                 `json:"accessKeyId,omitempty"`
+	ReadOnly        string                 `json:"readOnly,omitempty"`
+	Resources       []nativeResource       `json:"resources,omitempty"`
+	CloudTrailEvent map[string]interface{} `json:"cloudTrailEvent,omitempty"`
+	Index           int                    `json:"index,omitempty"`
+}
+
+type nativeResource struct {
+	ResourceName string `json:"resourceName,omitempty"`
+	ResourceType string `json:"resourceType,omitempty"`
+}
+
+// stringValue returns a pointer field's value, or "" when it's unset
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// formatNativeEvent writes one compact native record per line
+func formatNativeEvent(entry Entry) (string, error) {
+	event := entry.Event
+	record := nativeEvent{
+		EventID:         stringValue(event.EventId),
+		EventName:       stringValue(event.EventName),
+		EventSource:     stringValue(event.EventSource),
+		EventTime:       event.EventTime,
+		Username:        stringValue(event.Username),
+		AccessKeyID:     stringValue(event.AccessKeyId),
+		ReadOnly:        stringValue(event.ReadOnly),
+		CloudTrailEvent: entry.Details,
+		Index:           entry.Index,
+	}
+	for _, resource := range event.Resources {
+		record.Resources = append(record.Resources, nativeResource{
+			ResourceName: stringValue(resource.ResourceName),
+			ResourceType: stringValue(resource.ResourceType),
+		})
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event: %v", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// readNative decodes one native record per line back into entries
+func readNative(data []byte) ([]Entry, error) {
+	var entries []Entry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var record nativeEvent
+		if err := decoder.Decode(&record); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid native log: %v", err)
+		}
+
+		event := types.Event{
+			EventId:     stringPointer(record.EventID),
+			EventName:   stringPointer(record.EventName),
+			EventSource: stringPointer(record.EventSource),
+			EventTime:   record.EventTime,
+			Username:    stringPointer(record.Username),
+			AccessKeyId: stringPointer(record.AccessKeyID),
+			ReadOnly:    stringPointer(record.ReadOnly),
+		}
+		for _, resource := range record.Resources {
+			event.Resources = append(event.Resources, types.Resource{
+				ResourceName: stringPointer(resource.ResourceName),
+				ResourceType: stringPointer(resource.ResourceType),
+			})
+		}
+		if record.CloudTrailEvent != nil {
+			if body, err := json.Marshal(record.CloudTrailEvent); err == nil {
+				event.CloudTrailEvent = stringPointer(string(body))
+			}
+		}
+		entries = append(entries, Entry{Event: event, Details: record.CloudTrailEvent, Index: record.Index})
+	}
+}
+
+// stringPointer is the inverse of stringValue: nil for "", so unset fields stay unset
+func stringPointer(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
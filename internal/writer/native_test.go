@@ -0,0 +1,76 @@
+// internal/writer/native_test.go
+package writer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+func TestNativeRoundTrip(t *testing.T) {
+	full := testEntry("event-1", 1)
+	full.Index = 3
+	full.Event.AccessKeyId = aws.String("ASIAEXAMPLE")
+	full.Event.ReadOnly = aws.String("true")
+	full.Event.Resources = []types.Resource{
+		{ResourceName: aws.String("arn:aws:kms:us-east-1:123456789012:key/key-1"), ResourceType: aws.String("AWS::KMS::Key")},
+		{ResourceName: aws.String("alias/payments")},
+	}
+	body, err := json.Marshal(full.Details)
+	if err != nil {
+		t.Fatal(err)
+	}
+	full.Event.CloudTrailEvent = aws.String(string(body))
+
+	// Unset fields stay unset rather than becoming empty strings
+	sparse := testEntry("event-2", 2)
+	sparse.Event.Username = nil
+	sparse.Details = nil
+
+	var log strings.Builder
+	for _, entry := range []Entry{full, sparse} {
+		line, err := formatNativeEvent(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		log.WriteString(line)
+	}
+	got, err := readNative([]byte(log.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("read %d entries, want 2", len(got))
+	}
+	for i, want := range []Entry{full, sparse} {
+		if !reflect.DeepEqual(got[i].Event, want.Event) {
+			t.Errorf("event %d = %+v, want %+v", i, got[i].Event, want.Event)
+		}
+		if !reflect.DeepEqual(got[i].Details, want.Details) || got[i].Index != want.Index {
+			t.Errorf("entry %d details = %v, index %d; want %v, %d", i, got[i].Details, got[i].Index, want.Details, want.Index)
+		}
+	}
+}
+
+func TestNativeBodyIsNestedJSON(t *testing.T) {
+	line, err := formatNativeEvent(testEntry("event-1", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		t.Fatal(err)
+	}
+	body, ok := record["cloudTrailEvent"].(map[string]interface{})
+	if !ok || body["eventID"] != "event-1" {
+		t.Errorf("cloudTrailEvent = %v, want the parsed body as an object", record["cloudTrailEvent"])
+	}
+	if record["eventTime"] != "2024-01-15T00:01:00Z" {
+		t.Errorf("eventTime = %v, want RFC 3339", record["eventTime"])
+	}
+}
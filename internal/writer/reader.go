@@ -0,0 +1,241 @@
+// internal/writer/reader.go
+package writer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+)
+
+// Matches the "#3 [2024-01-15 10:00:00] Decrypt" line that starts a text event
+var textHeaderPattern = regexp.MustCompile(`^(?:#(\d+) )?\[(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\] (.*)$`)
+
+// Matches a "  - name (type)" resource line
+var textResourcePattern = regexp.MustCompile(`^\s+- (.*) \((.*)\)$`)
+
+// DetectFormat guesses which of the tool's export formats data is in
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return FormatText
+	}
+
+	var first map[string]json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(trimmed)).Decode(&first); err != nil {
+		return FormatText
+	}
+	if _, ok := first["specversion"]; ok {
+		return FormatCloudEvents
+	}
+	if _, ok := first["events"]; ok {
+		return FormatJSONDocument
+	}
+	if _, ok := first["timestamp"]; !ok {
+		return FormatNative
+	}
+	return FormatJSON
+}
+
+// ReadEntries parses a log file written by this tool back into entries. Text
+// files only carry a summary of each event, so details read from them are
+// limited to the flat request parameters and response elements.
+func ReadEntries(r io.Reader, format string) ([]Entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log: %v", err)
+	}
+	if data, err = decompress(data); err != nil {
+		return nil, err
+	}
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	switch format {
+	case FormatJSON, FormatNDJSON:
+		return readJSON(data)
+	case FormatJSONDocument:
+		var doc struct {
+			Events []json.RawMessage `json:"events"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("invalid JSON document: %v", err)
+		}
+		entries := make([]Entry, 0, len(doc.Events))
+		for _, raw := range doc.Events {
+			entry, err := decodeJSONRecord(raw)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		}
+		return entries, nil
+	case FormatCloudEvents:
+		return readCloudEvents(data)
+	case FormatNative:
+		return readNative(data)
+	case FormatText:
+		return readText(data), nil
+	}
+	return nil, ValidateFormat(format)
+}
+
+// readJSON decodes the concatenated records of the json and ndjson formats
+func readJSON(data []byte) ([]Entry, error) {
+	var entries []Entry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid JSON log: %v", err)
+		}
+		entry, err := decodeJSONRecord(raw)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// jsonRecordFields mirrors the object written by jsonRecord
+type jsonRecordFields struct {
+	Timestamp   string                 `json:"timestamp"`
+	EventName   string                 `json:"eventName"`
+	EventSource string                 `json:"eventSource"`
+	User        string                 `json:"user"`
+	Resources   []types.Resource       `json:"resources"`
+	Details     map[string]interface{} `json:"details"`
+	Index       int                    `json:"index"`
+	Region      string                 `json:"region"`
+}
+
+func decodeJSONRecord(raw json.RawMessage) (Entry, error) {
+	var record jsonRecordFields
+	if err := json.Unmarshal(raw, &record); err != nil {
+		return Entry{}, fmt.Errorf("invalid JSON event: %v", err)
+	}
+
+	event := types.Event{
+		EventName:   optional(record.EventName),
+		EventSource: optional(record.EventSource),
+		Username:    optional(record.User),
+		Resources:   record.Resources,
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", record.Timestamp, timeutil.Location()); err == nil {
+		event.EventTime = &t
+	}
+	if id, ok := record.Details["eventID"].(string); ok {
+		event.EventId = &id
+	}
+	return Entry{Event: event, Details: record.Details, Index: record.Index, Region: record.Region}, nil
+}
+
+// readCloudEvents decodes one envelope per line
+func readCloudEvents(data []byte) ([]Entry, error) {
+	var entries []Entry
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var envelope cloudEvent
+		if err := decoder.Decode(&envelope); err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid CloudEvents log: %v", err)
+		}
+
+		event := types.Event{
+			EventId:     optional(envelope.ID),
+			EventName:   optional(envelope.Subject),
+			EventSource: optional(envelope.Source),
+		}
+		if t, err := time.Parse(time.RFC3339, envelope.Time); err == nil {
+			event.EventTime = &t
+		}
+		if identity, ok := envelope.Data["userIdentity"].(map[string]interface{}); ok {
+			if name, ok := identity["userName"].(string); ok {
+				event.Username = &name
+			}
+		}
+		index, _ := strconv.Atoi(envelope.Sequence)
+		entries = append(entries, Entry{Event: event, Details: envelope.Data, Index: index})
+	}
+}
+
+// readText rebuilds entries from the text format's event blocks
+func readText(data []byte) []Entry {
+	var entries []Entry
+	var current *Entry
+	var section map[string]interface{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if match := textHeaderPattern.FindStringSubmatch(line); match != nil {
+			entries = append(entries, Entry{})
+			current = &entries[len(entries)-1]
+			section = nil
+			current.Index, _ = strconv.Atoi(match[1])
+			if t, err := time.ParseInLocation("2006-01-02 15:04:05", match[2], timeutil.Location()); err == nil {
+				current.Event.EventTime = &t
+			}
+			current.Event.EventName = optional(match[3])
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Source: "):
+			current.Event.EventSource = optional(strings.TrimPrefix(line, "Source: "))
+		case strings.HasPrefix(line, "User: "):
+			current.Event.Username = optional(strings.TrimPrefix(line, "User: "))
+		case strings.HasPrefix(line, "Region: "):
+			current.Region = strings.TrimPrefix(line, "Region: ")
+		case line == "  Request Parameters:" || line == "  Response Elements:":
+			if current.Details == nil {
+				current.Details = make(map[string]interface{})
+			}
+			section = make(map[string]interface{})
+			key := "requestParameters"
+			if line == "  Response Elements:" {
+				key = "responseElements"
+			}
+			current.Details[key] = section
+		case strings.HasPrefix(line, "    ") && section != nil:
+			if key, value, ok := strings.Cut(strings.TrimSpace(line), ": "); ok {
+				section[key] = value
+			}
+		case strings.HasPrefix(line, "  Insight:"):
+			section = nil
+		default:
+			if match := textResourcePattern.FindStringSubmatch(line); match != nil {
+				current.Event.Resources = append(current.Event.Resources, types.Resource{
+					ResourceName: optional(match[1]),
+					ResourceType: optional(match[2]),
+				})
+			}
+		}
+	}
+	return entries
+}
+
+// optional returns nil for empty or "N/A" values so SafeString round-trips
+func optional(value string) *string {
+	if value == "" || value == "N/A" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,98 @@
+// internal/writer/reader_test.go
+package writer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// exportEntries writes entries to a new file in format and returns its contents
+func exportEntries(t *testing.T, format string, entries []Entry) []byte {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "events")
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: format})
+	if err := w.WriteBatch(entries); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// summary is what every format keeps of an entry
+func summary(entry Entry) string {
+	return safeTime(entry.Event.EventTime) + " " + SafeString(entry.Event.EventName) + " " +
+		SafeString(entry.Event.EventSource) + " " + SafeString(entry.Event.Username)
+}
+
+func TestDetectFormat(t *testing.T) {
+	entries := []Entry{testEntry("event-1", 1)}
+	for _, format := range []string{FormatText, FormatJSON, FormatJSONDocument, FormatCloudEvents, FormatNative} {
+		if got := DetectFormat(exportEntries(t, format, entries)); got != format {
+			t.Errorf("DetectFormat of a %s export = %s", format, got)
+		}
+	}
+	// ndjson records are the json records, one per line
+	if got := DetectFormat(exportEntries(t, FormatNDJSON, entries)); got != FormatJSON {
+		t.Errorf("DetectFormat of an ndjson export = %s, want json", got)
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	first := testEntry("event-1", 1)
+	first.Event.Resources = []types.Resource{{ResourceName: aws.String("key-1"), ResourceType: aws.String("AWS::KMS::Key")}}
+	first.Region = "eu-west-1"
+	entries := []Entry{first, testEntry("event-2", 2)}
+
+	// json -> text -> json keeps each event's summary and flat parameters
+	fromJSON, err := ReadEntries(bytes.NewReader(exportEntries(t, FormatJSON, entries)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromText, err := ReadEntries(bytes.NewReader(exportEntries(t, FormatText, fromJSON)), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := ReadEntries(bytes.NewReader(exportEntries(t, FormatJSON, fromText)), FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(back) != len(entries) {
+		t.Fatalf("round trip gave %d entries, want %d", len(back), len(entries))
+	}
+	for i, entry := range back {
+		if summary(entry) != summary(entries[i]) {
+			t.Errorf("entry %d = %s, want %s", i, summary(entry), summary(entries[i]))
+		}
+		if entry.Region != entries[i].Region {
+			t.Errorf("entry %d region = %q, want %q", i, entry.Region, entries[i].Region)
+		}
+		params, _ := entry.Details["requestParameters"].(map[string]interface{})
+		if params["keyId"] != "key-1" {
+			t.Errorf("entry %d requestParameters = %v", i, entry.Details["requestParameters"])
+		}
+	}
+	if resources := back[0].Event.Resources; len(resources) != 1 || SafeString(resources[0].ResourceName) != "key-1" {
+		t.Errorf("resources = %+v, want key-1", resources)
+	}
+}
+
+func TestReadEntriesInvalid(t *testing.T) {
+	if _, err := ReadEntries(bytes.NewReader([]byte(`{"timestamp": `)), FormatJSON); err == nil {
+		t.Error("ReadEntries accepted truncated JSON")
+	}
+	if _, err := ReadEntries(bytes.NewReader(nil), "csv"); err == nil {
+		t.Error("ReadEntries accepted an unknown format")
+	}
+}
@@ -0,0 +1,35 @@
+// internal/writer/retry.go
+package writer
+
+import (
+	"errors"
+	"io/fs"
+	"syscall"
+	"time"
+
+	"github.com/dhairya13703/cloudtrail-logs/internal/retry"
+)
+
+// Backoff between attempts to write a log file
+var writeRetryPolicy = retry.Policy{
+	BaseDelay: 100 * time.Millisecond,
+	MaxDelay:  2 * time.Second,
+}
+
+// permanentWriteError reports whether retrying a failed open or write can't
+// help, such as a permission problem or a read-only filesystem
+func permanentWriteError(err error) bool {
+	return errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS)
+}
+
+// withWriteRetries calls fn until it succeeds, fails permanently, or
+// w.writeRetries retries have been made
+func (w *LogWriter) withWriteRetries(fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || permanentWriteError(err) || attempt > w.writeRetries {
+			return err
+		}
+		time.Sleep(writeRetryPolicy.Backoff(attempt))
+	}
+}
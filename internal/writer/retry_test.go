@@ -0,0 +1,56 @@
+// internal/writer/retry_test.go
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// failingWrites returns a write that fails with errs in turn, then succeeds,
+// and a count of the attempts made
+func failingWrites(errs ...error) (func() error, *int) {
+	attempts := 0
+	return func() error {
+		attempts++
+		if attempts <= len(errs) {
+			return errs[attempts-1]
+		}
+		return nil
+	}, &attempts
+}
+
+func TestWriteRetries(t *testing.T) {
+	policy := writeRetryPolicy
+	writeRetryPolicy.BaseDelay, writeRetryPolicy.MaxDelay = time.Millisecond, time.Millisecond
+	t.Cleanup(func() { writeRetryPolicy = policy })
+
+	transient := fmt.Errorf("failed to write log: %w", syscall.EIO)
+	denied := fmt.Errorf("failed to open log file: %w", &fs.PathError{Op: "open", Path: "events.log", Err: fs.ErrPermission})
+
+	tests := []struct {
+		name         string
+		retries      int
+		errs         []error
+		wantErr      error
+		wantAttempts int
+	}{
+		{"fails once then succeeds", 2, []error{transient}, nil, 2},
+		{"no retries configured", 0, []error{transient}, transient, 1},
+		{"retries exhausted", 2, []error{transient, transient, transient}, transient, 3},
+		{"permission denied isn't retried", 2, []error{denied}, denied, 1},
+	}
+	for _, tc := range tests {
+		w := NewLogWriter(t.TempDir(), "kms", &ExportOptions{WriteRetries: tc.retries})
+		write, attempts := failingWrites(tc.errs...)
+		if err := w.withWriteRetries(write); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tc.name, err, tc.wantErr)
+		}
+		if *attempts != tc.wantAttempts {
+			t.Errorf("%s: %d attempts, want %d", tc.name, *attempts, tc.wantAttempts)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// internal/writer/rotate.go
+package writer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// rotatedName inserts the rotation number before the file extension, so
+// kms-events-2024-01-15.log becomes kms-events-2024-01-15.1.log. Number 0 is
+// the original file.
+func rotatedName(filename string, n int) string {
+	if n == 0 {
+		return filename
+	}
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s.%d%s", strings.TrimSuffix(filename, ext), n, ext)
+}
+
+// rotateIfFull moves region's file on to the next number while the current
+// one has reached the size limit. Each plain file name (one per region and
+// day) keeps its own numbering, and files left full by an earlier run are
+// skipped the same way. incoming is the size of the output about to be
+// appended. Callers hold w.mu.
+func (w *LogWriter) rotateIfFull(incoming int, region string) {
+	if w.maxFileSize <= 0 {
+		return
+	}
+	if w.rotations == nil {
+		w.rotations = make(map[string]int)
+	}
+	plain := w.plainFile(region)
+
+	pending := int64(incoming)
+	if w.compress {
+		// The compressed size of the output isn't known until it's written
+		pending = 0
+	}
+	for {
+		filename := w.currentFile(region)
+		if filename != w.sizePath || w.compress {
+			// Compressed files are measured on disk after every write
+			w.sizePath, w.size = filename, fileSize(filename)
+		}
+		if w.size == 0 || w.size+pending <= w.maxFileSize {
+			return
+		}
+		w.rotations[plain]++
+	}
+}
+
+// fileSize returns the size of filename, or 0 if it doesn't exist yet
+func fileSize(filename string) int64 {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
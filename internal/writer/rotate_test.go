@@ -0,0 +1,100 @@
+// internal/writer/rotate_test.go
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatedName(t *testing.T) {
+	tests := map[int]string{
+		0:  "/logs/kms-events-2024-01-15.log",
+		1:  "/logs/kms-events-2024-01-15.1.log",
+		12: "/logs/kms-events-2024-01-15.12.log",
+	}
+	for n, want := range tests {
+		if got := rotatedName("/logs/kms-events-2024-01-15.log", n); got != want {
+			t.Errorf("rotatedName(%d) = %s, want %s", n, got, want)
+		}
+	}
+}
+
+// eventIDs returns the ids of the ndjson records in file, in order
+func eventIDs(t *testing.T, file string) []string {
+	t.Helper()
+	f, err := os.Open(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	entries, err := ReadEntries(f, FormatNDJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		ids = append(ids, entry.Details["eventID"].(string))
+	}
+	return ids
+}
+
+func TestRotateBySize(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "events.log")
+	page := testPage(7)
+	record, err := (&LogWriter{exportMode: FormatNDJSON}).formatEvent(page[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Room for three events per file
+	limit := int64(3*len(record) + len(record)/2)
+
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: FormatNDJSON, MaxFileSize: limit})
+	for _, entry := range page[:4] {
+		if err := w.WriteEntry(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteBatch(page[4:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"events.log":   "event-0 event-1 event-2",
+		"events.1.log": "event-3",
+		"events.2.log": "event-4 event-5 event-6", // a batch is written whole
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "events*.log"))
+	if len(files) != len(want) {
+		t.Errorf("files = %v, want %d", files, len(want))
+	}
+	for name, ids := range want {
+		path := filepath.Join(dir, name)
+		if got := strings.Join(eventIDs(t, path), " "); got != ids {
+			t.Errorf("%s holds %s, want %s", name, got, ids)
+		}
+		if size := fileSize(path); size > limit {
+			t.Errorf("%s is %d bytes, over the %d limit", name, size, limit)
+		}
+	}
+	if got := w.GetCurrentFile(); got != filepath.Join(dir, "events.2.log") {
+		t.Errorf("GetCurrentFile = %s, want the last rotation", got)
+	}
+
+	// A later run skips the files that are already full
+	w = NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: FormatNDJSON, MaxFileSize: limit})
+	if err := w.WriteEntry(testEntry("event-7", 7)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(eventIDs(t, filepath.Join(dir, "events.1.log")), " "); got != "event-3 event-7" {
+		t.Errorf("events.1.log holds %s after the second run, want event-7 appended", got)
+	}
+}
@@ -0,0 +1,65 @@
+// internal/writer/split.go
+package writer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// splitDir holds the per-event files: the same directory the templated log
+// file for region would be written to
+func (w *LogWriter) splitDir(region string) string {
+	return filepath.Dir(w.currentFile(region))
+}
+
+// splitFilename names an event's file after its EventId. Events without an
+// id fall back to their sequence index or timestamp.
+func (w *LogWriter) splitFilename(entry Entry) string {
+	name := SafeString(entry.Event.EventId)
+	if entry.Event.EventId == nil {
+		switch {
+		case entry.Index > 0:
+			name = fmt.Sprintf("event-%d", entry.Index)
+		case entry.Event.EventTime != nil:
+			name = fmt.Sprintf("event-%d", entry.Event.EventTime.UnixNano())
+		default:
+			name = "event-unknown"
+		}
+	}
+	// EventIds are UUIDs, but never let a value escape the directory
+	name = strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(name)
+
+	extension := ".log"
+	switch w.exportMode {
+	case FormatJSON, FormatNDJSON, FormatCloudEvents, FormatNative:
+		extension = ".json"
+	}
+	return filepath.Join(w.splitDir(entry.Region), name+extension)
+}
+
+// writeSplitFile writes one event to its own file, replacing an earlier copy
+// of the same event so re-runs stay idempotent
+func (w *LogWriter) writeSplitFile(entry Entry) error {
+	content, err := w.formatEvent(entry)
+	if err != nil {
+		return err
+	}
+
+	filename := w.splitFilename(entry)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %v", err)
+	}
+	err = w.withWriteRetries(func() error {
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write event file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	w.recordFile(filename, entry.Region, 1)
+	return nil
+}
@@ -0,0 +1,79 @@
+// internal/writer/split_test.go
+package writer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSplitFilesOnePerEvent(t *testing.T) {
+	dir := t.TempDir()
+	w := NewLogWriter(dir, "kms", &ExportOptions{SplitFiles: true, Format: FormatJSON})
+	if err := w.WriteEntry(testEntry("event-1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteBatch([]Entry{testEntry("event-2", 2), testEntry("event-3", 3)}); err != nil {
+		t.Fatal(err)
+	}
+	// A repeated event replaces its file rather than adding one
+	if err := w.WriteEntry(testEntry("event-1", 1)); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	serviceDir := filepath.Join(dir, "kms")
+	files, err := os.ReadDir(serviceDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, file := range files {
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	if strings.Join(names, " ") != "event-1.json event-2.json event-3.json" {
+		t.Fatalf("files = %v, want one per event id", names)
+	}
+
+	data, err := os.ReadFile(filepath.Join(serviceDir, "event-2.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatalf("event file isn't a JSON document: %v\n%s", err, data)
+	}
+	if details, _ := record["details"].(map[string]interface{}); details["eventID"] != "event-2" {
+		t.Errorf("event-2.json holds %v", record)
+	}
+}
+
+func TestSplitFilename(t *testing.T) {
+	dir := t.TempDir()
+	w := NewLogWriter(dir, "kms", &ExportOptions{SplitFiles: true})
+
+	noID := testEntry("", 0)
+	noID.Event.EventId = nil
+	indexed := noID
+	indexed.Index = 7
+	escaping := testEntry("../../etc/passwd", 0)
+
+	tests := []struct {
+		entry Entry
+		want  string
+	}{
+		{testEntry("event-1", 0), "event-1.log"}, // text by default
+		{indexed, "event-7.log"},
+		{noID, "event-1705276800000000000.log"},
+		{escaping, "____etc_passwd.log"},
+	}
+	for _, tc := range tests {
+		if got := w.splitFilename(tc.entry); got != filepath.Join(dir, "kms", tc.want) {
+			t.Errorf("splitFilename(%s) = %s, want %s", SafeString(tc.entry.Event.EventId), got, tc.want)
+		}
+	}
+}
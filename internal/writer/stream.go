@@ -0,0 +1,118 @@
+// internal/writer/stream.go
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// How often OpenStream retries a named pipe that has no reader yet
+const pipePollInterval = 100 * time.Millisecond
+
+// streamTarget reports whether path is an existing named pipe or Unix domain
+// socket, returning the matching file mode
+func streamTarget(path string) (os.FileMode, bool) {
+	if path == "" {
+		return 0, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	mode := info.Mode() & (os.ModeNamedPipe | os.ModeSocket)
+	return mode, mode != 0
+}
+
+// OpenStream connects to the FIFO or socket being exported to, waiting for a
+// named pipe's reader to attach until ctx is done. Callers should open the
+// stream before a scan, so the wait can be interrupted; otherwise the first
+// write opens it and waits without a deadline. It does nothing when the
+// export isn't a stream.
+func (w *LogWriter) OpenStream(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.streamMode == 0 || w.stream != nil {
+		return nil
+	}
+	return w.openStream(ctx)
+}
+
+// writeStream writes to the FIFO or socket, connecting on first use and
+// keeping the connection open so the reader sees one continuous stream
+func (w *LogWriter) writeStream(content string) error {
+	if w.stream == nil {
+		if err := w.openStream(context.Background()); err != nil {
+			return err
+		}
+	}
+	if _, err := w.stream.Write([]byte(content)); err != nil {
+		return fmt.Errorf("failed to write to %s: %v", w.customFile, err)
+	}
+	return nil
+}
+
+func (w *LogWriter) openStream(ctx context.Context) error {
+	if w.streamMode == os.ModeSocket {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "unix", w.customFile)
+		if err != nil {
+			return fmt.Errorf("failed to connect to socket %s: %v", w.customFile, err)
+		}
+		w.stream = conn
+		return nil
+	}
+
+	// A blocking open of a FIFO for writing waits for a reader and can't be
+	// cancelled. A non-blocking one fails with ENXIO until a reader is
+	// attached, so poll instead.
+	for {
+		f, err := os.OpenFile(w.customFile, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+		if err == nil {
+			w.stream = f
+			return nil
+		}
+		if !errors.Is(err, syscall.ENXIO) {
+			return fmt.Errorf("failed to open pipe %s: %v", w.customFile, err)
+		}
+
+		timer := time.NewTimer(pipePollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("no reader attached to pipe %s: %w", w.customFile, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// Close writes any buffered json-document, finishes compressed output, and
+// releases any open stream. Uncompressed files are opened per write and need
+// no other cleanup.
+func (w *LogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	if w.exportMode == FormatJSONDocument && !w.documentWritten {
+		err = w.writeDocument()
+		w.documentEvents = nil
+		w.documentWritten = true
+	}
+	if closeErr := w.closeCompressed(); err == nil {
+		err = closeErr
+	}
+
+	if w.stream == nil {
+		return err
+	}
+	if closeErr := w.stream.Close(); err == nil {
+		err = closeErr
+	}
+	w.stream = nil
+	return err
+}
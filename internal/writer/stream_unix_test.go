@@ -0,0 +1,119 @@
+// internal/writer/stream_unix_test.go
+//go:build unix
+
+package writer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// writeStreamed writes two events as ndjson to target and closes the writer
+func writeStreamed(t *testing.T, target string) {
+	t.Helper()
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: target, Format: FormatNDJSON})
+	for _, entry := range []Entry{testEntry("event-1", 1), testEntry("event-2", 2)} {
+		if err := w.WriteEntry(entry); err != nil {
+			t.Fatalf("WriteEntry: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+// checkStreamed asserts the reader got both events, one JSON object per line
+func checkStreamed(t *testing.T, received string) {
+	t.Helper()
+	lines := strings.Split(strings.TrimSuffix(received, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("read %d lines, want 2:\n%s", len(lines), received)
+	}
+	for i, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d isn't JSON: %v", i+1, err)
+		}
+		details, _ := record["details"].(map[string]interface{})
+		if want := []string{"event-1", "event-2"}[i]; details["eventID"] != want {
+			t.Errorf("line %d holds %v, want %s", i+1, details["eventID"], want)
+		}
+	}
+}
+
+func TestWriteToNamedPipe(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "events.fifo")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Skipf("can't create a FIFO: %v", err)
+	}
+
+	received := make(chan string)
+	go func() {
+		f, err := os.Open(fifo)
+		if err != nil {
+			received <- "open failed: " + err.Error()
+			return
+		}
+		defer f.Close()
+		data, _ := io.ReadAll(f)
+		received <- string(data)
+	}()
+
+	writeStreamed(t, fifo)
+	checkStreamed(t, <-received)
+}
+
+func TestOpenStreamWithoutReaderIsCancelled(t *testing.T) {
+	fifo := filepath.Join(t.TempDir(), "events.fifo")
+	if err := syscall.Mkfifo(fifo, 0600); err != nil {
+		t.Skipf("can't create a FIFO: %v", err)
+	}
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: fifo, Format: FormatNDJSON})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- w.OpenStream(ctx) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("OpenStream with no reader = %v, want a deadline error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OpenStream kept waiting for a reader after its context ended")
+	}
+}
+
+func TestWriteToUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "events.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("can't listen on a Unix socket: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- "accept failed: " + err.Error()
+			return
+		}
+		defer conn.Close()
+		data, _ := io.ReadAll(conn)
+		received <- string(data)
+	}()
+
+	writeStreamed(t, socket)
+	checkStreamed(t, <-received)
+}
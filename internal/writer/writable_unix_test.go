@@ -0,0 +1,39 @@
+// internal/writer/writable_unix_test.go
+//go:build unix
+
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLockReadOnlyDirectory(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only directories")
+	}
+	dir := filepath.Join(t.TempDir(), "read-only")
+	if err := os.Mkdir(dir, 0555); err != nil {
+		t.Fatal(err)
+	}
+
+	w := NewLogWriter(dir, "kms", &ExportOptions{})
+	err := w.Lock()
+	if err == nil || !strings.Contains(err.Error(), "can't be created") {
+		t.Fatalf("Lock in a read-only directory = %v, want a clear error", err)
+	}
+	if !strings.Contains(err.Error(), "--output") {
+		t.Errorf("error %q doesn't suggest another location", err)
+	}
+
+	// The service directory can't be made, but an existing one can't take files either
+	existing := filepath.Join(t.TempDir(), "kms")
+	if err := os.Mkdir(existing, 0555); err != nil {
+		t.Fatal(err)
+	}
+	if err := checkWritable(existing); err == nil || !strings.Contains(err.Error(), "is not writable") {
+		t.Errorf("checkWritable(read-only) = %v", err)
+	}
+}
@@ -2,43 +2,161 @@
 package writer
 
 import (
+	"compress/gzip"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+	"github.com/dhairya13703/cloudtrail-logs/internal/timeutil"
+	"github.com/gofrs/flock"
 )
 
+// DefaultFilenameTemplate reproduces the original <service>-events-<date>.log naming
+const DefaultFilenameTemplate = "{service}-events-{date}.log"
+
+// Separators written between events in text output
+const (
+	SeparatorLine  = "line"  // 80-dash rule (default)
+	SeparatorBlank = "blank" // single blank line
+	SeparatorNone  = "none"
+)
+
+var placeholderPattern = regexp.MustCompile(`\{([^{}]*)\}`)
+
+var filenamePlaceholders = map[string]bool{
+	"service": true,
+	"date":    true,
+	"region":  true,
+	"profile": true,
+}
+
 type LogWriter struct {
-	outputDir    string
-	serviceTag   string
-	customFile   string
-	exportMode   string
-	mu           sync.Mutex
+	outputDir        string
+	serviceTag       string
+	customFile       string
+	exportMode       string
+	filenameTemplate string
+	region           string
+	profile          string
+	separator        string
+	splitFiles       bool
+	regionDirs       bool
+	writeRetries     int // extra attempts after a transient open or write failure
+	fileLock         *flock.Flock
+	streamMode       os.FileMode // os.ModeNamedPipe or os.ModeSocket when exporting to a stream
+	stream           io.WriteCloser
+	mu               sync.Mutex
+
+	// compressed output stays open between writes and is finished on Close
+	compress bool
+	gzFile   *os.File
+	gz       *gzip.Writer
+	gzPath   string
+
+	// with a size limit, full files roll over to <name>.1.log, <name>.2.log, ...
+	maxFileSize int64
+	rotations   map[string]int // numbered file in use, by plain file name
+	sizePath    string         // file whose size is tracked in size
+	size        int64
+
+	// json-document exports are buffered and written once on Close
+	documentEvents  []map[string]interface{}
+	documentWritten bool
+	windowStart     *time.Time
+	windowEnd       *time.Time
+
+	// files written during the run, for the manifest
+	produced      []ProducedFile
+	producedIndex map[string]int
 }
 
 type ExportOptions struct {
-	Filename string
-	Format   string // text, json
+	Filename         string
+	Format           string // text, json, ndjson, json-document, cloudevents, native
+	FilenameTemplate string // e.g. "{profile}/{region}/{service}-{date}.log"
+	Region           string
+	Profile          string
+	Separator        string // line, blank, or none (text format only)
+	SplitFiles       bool   // write each event to its own <EventId> file
+	RegionDirs       bool   // nest default log files under <output>/<service>/<region>/
+	WriteRetries     int    // retries for transient file errors; 0 fails on the first
+	Compress         bool   // gzip the log file and add .gz to its name; ignored for streams
+	MaxFileSize      int64  // roll over to a numbered file once the log file reaches this many bytes; 0 disables
+}
+
+// Supported export formats
+const (
+	FormatText         = "text"
+	FormatJSON         = "json"
+	FormatNDJSON       = "ndjson"        // the json record compacted to one line per event
+	FormatJSONDocument = "json-document" // {"metadata": {...}, "events": [...]}
+	FormatCloudEvents  = "cloudevents"
+	FormatNative       = "native" // SDK event fields with the body as nested JSON, one per line
+)
+
+// ValidateFormat checks the export format option
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatText, FormatJSON, FormatNDJSON, FormatJSONDocument, FormatCloudEvents, FormatNative:
+		return nil
+	}
+	return fmt.Errorf("invalid export format %q: use %s, %s, %s, %s, %s, or %s", format, FormatText, FormatJSON, FormatNDJSON, FormatJSONDocument, FormatCloudEvents, FormatNative)
+}
+
+// ValidateSeparator checks the text separator option
+func ValidateSeparator(separator string) error {
+	switch separator {
+	case "", SeparatorLine, SeparatorBlank, SeparatorNone:
+		return nil
+	}
+	return fmt.Errorf("invalid separator %q: use %s, %s, or %s", separator, SeparatorLine, SeparatorBlank, SeparatorNone)
+}
+
+// ValidateFilenameTemplate checks that a filename template only uses supported placeholders
+func ValidateFilenameTemplate(template string) error {
+	for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+		if !filenamePlaceholders[match[1]] {
+			return fmt.Errorf("unknown placeholder {%s} in filename template. Supported: {service}, {date}, {region}, {profile}", match[1])
+		}
+	}
+	return nil
 }
 
 func NewLogWriter(outputDir, serviceTag string, options *ExportOptions) *LogWriter {
 	writer := &LogWriter{
-		outputDir:  outputDir,
-		serviceTag: serviceTag,
+		outputDir:        outputDir,
+		serviceTag:       serviceTag,
+		filenameTemplate: DefaultFilenameTemplate,
 	}
 
 	if options != nil {
 		writer.customFile = options.Filename
 		writer.exportMode = options.Format
+		writer.region = options.Region
+		writer.profile = options.Profile
+		writer.separator = options.Separator
+		writer.splitFiles = options.SplitFiles
+		writer.regionDirs = options.RegionDirs
+		writer.writeRetries = options.WriteRetries
+		writer.compress = options.Compress
+		writer.maxFileSize = options.MaxFileSize
+		if options.FilenameTemplate != "" {
+			writer.filenameTemplate = options.FilenameTemplate
+		}
 	}
 
 	// Create output directory if it doesn't exist
-	if writer.customFile != "" {
+	if mode, ok := streamTarget(writer.customFile); ok {
+		writer.streamMode = mode
+		writer.compress = false
+	} else if writer.customFile != "" {
 		os.MkdirAll(filepath.Dir(writer.customFile), 0755)
 	} else {
 		os.MkdirAll(filepath.Join(outputDir, serviceTag), 0755)
@@ -47,16 +165,23 @@ func NewLogWriter(outputDir, serviceTag string, options *ExportOptions) *LogWrit
 	return writer
 }
 
-func formatEventAsText(event types.Event, eventDetails map[string]interface{}) string {
+func formatEventAsText(entry Entry, separator string) string {
+	event, eventDetails := entry.Event, entry.Details
 	var sb strings.Builder
 
-	// Write timestamp and event name
+	// Write sequence index, timestamp and event name
+	if entry.Index > 0 {
+		sb.WriteString(fmt.Sprintf("#%d ", entry.Index))
+	}
 	sb.WriteString(fmt.Sprintf("[%s] %s\n",
-		event.EventTime.Format("2006-01-02 15:04:05"),
+		safeTime(event.EventTime),
 		SafeString(event.EventName)))
 
 	// Write source
 	sb.WriteString(fmt.Sprintf("Source: %s\n", SafeString(event.EventSource)))
+	if entry.Region != "" {
+		sb.WriteString(fmt.Sprintf("Region: %s\n", entry.Region))
+	}
 
 	// Write username
 	username := "N/A"
@@ -78,6 +203,25 @@ func formatEventAsText(event types.Event, eventDetails map[string]interface{}) s
 	// Write event details
 	if eventDetails != nil {
 		sb.WriteString("Details:\n")
+		// CloudTrail Insights anomaly
+		if insight, ok := eventDetails["insightDetails"].(map[string]interface{}); ok {
+			sb.WriteString("  Insight:\n")
+			for _, key := range []string{"insightType", "state", "eventSource", "eventName", "errorCode"} {
+				if value, ok := insight[key]; ok && value != nil {
+					sb.WriteString(fmt.Sprintf("    %s: %v\n", key, value))
+				}
+			}
+			if context, ok := insight["insightContext"].(map[string]interface{}); ok {
+				if statistics, ok := context["statistics"].(map[string]interface{}); ok {
+					for _, key := range []string{"baseline", "insight"} {
+						if stat, ok := statistics[key].(map[string]interface{}); ok {
+							sb.WriteString(fmt.Sprintf("    %s average: %v\n", key, stat["average"]))
+						}
+					}
+				}
+			}
+		}
+
 		// Request Parameters
 		if reqParams, ok := eventDetails["requestParameters"].(map[string]interface{}); ok && len(reqParams) > 0 {
 			sb.WriteString("  Request Parameters:\n")
@@ -99,7 +243,13 @@ func formatEventAsText(event types.Event, eventDetails map[string]interface{}) s
 		}
 	}
 
-	sb.WriteString(strings.Repeat("-", 80) + "\n")
+	switch separator {
+	case SeparatorBlank:
+		sb.WriteString("\n")
+	case SeparatorNone:
+	default:
+		sb.WriteString(strings.Repeat("-", 80) + "\n")
+	}
 	return sb.String()
 }
 
@@ -110,62 +260,321 @@ func SafeString(s *string) string {
 	return *s
 }
 
+// safeTime formats an event timestamp, or "N/A" if it's missing
+func safeTime(t *time.Time) string {
+	if t == nil {
+		return "N/A"
+	}
+	return timeutil.FormatTime(*t)
+}
+
 func (w *LogWriter) WriteEvent(event types.Event, eventDetails map[string]interface{}) error {
+	return w.WriteEntry(Entry{Event: event, Details: eventDetails})
+}
+
+// Entry pairs an event with its parsed CloudTrail details
+type Entry struct {
+	Event   types.Event
+	Details map[string]interface{}
+	Index   int    // sequence number within the run; 0 omits it
+	Region  string // region the event was looked up in; set for multi-region scans
+}
+
+// WriteEntry writes a single event along with its sequence index
+func (w *LogWriter) WriteEntry(entry Entry) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	var filename string
-	if w.customFile != "" {
-		filename = w.customFile
-	} else {
-		filename = filepath.Join(
-			w.outputDir,
-			w.serviceTag,
-			fmt.Sprintf("%s-events-%s.log", w.serviceTag, time.Now().Format("2006-01-02")),
-		)
+	if w.exportMode == FormatJSONDocument {
+		w.documentEvents = append(w.documentEvents, jsonRecord(entry))
+		return nil
+	}
+	if w.splitFiles {
+		return w.writeSplitFile(entry)
 	}
 
-	// Open file in append mode
-	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	content, err := w.formatEvent(entry)
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %v", err)
+		return err
 	}
-	defer f.Close()
+	return w.writeContent(content, 1, entry.Region)
+}
 
-	var content string
-	switch w.exportMode {
-	case "json":
-		jsonData := map[string]interface{}{
-			"timestamp":     event.EventTime.Format("2006-01-02 15:04:05"),
-			"eventName":     SafeString(event.EventName),
-			"eventSource":   SafeString(event.EventSource),
-			"user":         SafeString(event.Username),
-			"resources":     event.Resources,
-			"details":      eventDetails,
-		}
-		jsonBytes, err := json.MarshalIndent(jsonData, "", "  ")
+// WriteBatch encodes all entries and writes them with a single open and write
+func (w *LogWriter) WriteBatch(entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.exportMode == FormatJSONDocument {
+		for _, entry := range entries {
+			w.documentEvents = append(w.documentEvents, jsonRecord(entry))
+		}
+		return nil
+	}
+	if w.splitFiles {
+		for _, entry := range entries {
+			if err := w.writeSplitFile(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// A batch is normally one page from one region, but each region's
+	// events still go to that region's file
+	var regions []string
+	contents := make(map[string]*strings.Builder)
+	counts := make(map[string]int)
+	for _, entry := range entries {
+		content, err := w.formatEvent(entry)
 		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %v", err)
+			return err
 		}
-		content = string(jsonBytes) + "\n"
-	default: // text format
-		content = formatEventAsText(event, eventDetails)
+		sb, ok := contents[entry.Region]
+		if !ok {
+			sb = &strings.Builder{}
+			contents[entry.Region] = sb
+			regions = append(regions, entry.Region)
+		}
+		sb.WriteString(content)
+		counts[entry.Region]++
 	}
+	for _, region := range regions {
+		if err := w.writeContent(contents[region].String(), counts[region], region); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeContent appends already formatted output holding events entries to
+// the current target for region (empty for the writer's own region)
+func (w *LogWriter) writeContent(content string, events int, region string) error {
+	// FIFOs and sockets receive a continuous stream rather than appends
+	if w.streamMode != 0 {
+		if err := w.writeStream(content); err != nil {
+			return err
+		}
+		w.recordFile(w.customFile, region, events)
+		return nil
+	}
+
+	w.rotateIfFull(len(content), region)
+	filename := w.currentFile(region)
 
-	if _, err := f.WriteString(content); err != nil {
-		return fmt.Errorf("failed to write to log file: %v", err)
+	// Templates may introduce subdirectories (e.g. per profile or region)
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %v", err)
 	}
 
+	if w.compress {
+		if err := w.writeCompressed(filename, content); err != nil {
+			return err
+		}
+		w.recordFile(filename, region, events)
+		return nil
+	}
+
+	// Track what has been written so a retry after a short write doesn't
+	// append the same output twice
+	written := 0
+	err := w.withWriteRetries(func() error {
+		// Open file in append mode
+		f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		defer f.Close()
+
+		n, err := f.WriteString(content[written:])
+		written += n
+		if err != nil {
+			return fmt.Errorf("failed to write to log file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if filename == w.sizePath {
+		w.size += int64(written)
+	}
+	w.recordFile(filename, region, events)
 	return nil
 }
 
+// jsonRecord is the object written per event by the json formats
+func jsonRecord(entry Entry) map[string]interface{} {
+	event := entry.Event
+	record := map[string]interface{}{
+		"timestamp":   safeTime(event.EventTime),
+		"eventName":   SafeString(event.EventName),
+		"eventSource": SafeString(event.EventSource),
+		"user":        SafeString(event.Username),
+		"resources":   event.Resources,
+		"details":     entry.Details,
+	}
+	if entry.Index > 0 {
+		record["index"] = entry.Index
+	}
+	if entry.Region != "" {
+		record["region"] = entry.Region
+	}
+	return record
+}
+
+// FormatJSONEvent renders an event as the indented record of the json format
+func FormatJSONEvent(entry Entry) (string, error) {
+	jsonBytes, err := json.MarshalIndent(jsonRecord(entry), "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+	return string(jsonBytes) + "\n", nil
+}
+
+// formatEvent renders an event in the configured export format
+func (w *LogWriter) formatEvent(entry Entry) (string, error) {
+	switch w.exportMode {
+	case FormatCloudEvents:
+		return formatCloudEvent(entry)
+	case FormatNative:
+		return formatNativeEvent(entry)
+	case FormatJSON:
+		return FormatJSONEvent(entry)
+	case FormatNDJSON:
+		jsonBytes, err := json.Marshal(jsonRecord(entry))
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal JSON: %v", err)
+		}
+		return string(jsonBytes) + "\n", nil
+	default: // text format
+		return formatEventAsText(entry, w.separator), nil
+	}
+}
+
 func (w *LogWriter) GetCurrentFile() string {
+	if w.splitFiles {
+		return w.splitDir("") + string(filepath.Separator)
+	}
+	return w.currentFile("")
+}
+
+// currentFile is the file events from region are appended to now. An empty
+// region means the writer's own.
+func (w *LogWriter) currentFile(region string) string {
+	filename := w.plainFile(region)
+	filename = rotatedName(filename, w.rotations[filename])
+	if w.compress {
+		return compressedName(filename)
+	}
+	return filename
+}
+
+// plainFile is the log file name before any rotation number or compression suffix
+func (w *LogWriter) plainFile(region string) string {
 	if w.customFile != "" {
 		return w.customFile
 	}
-	return filepath.Join(
-		w.outputDir,
-		w.serviceTag,
-		fmt.Sprintf("%s-events-%s.log", w.serviceTag, time.Now().Format("2006-01-02")),
+	if region == "" {
+		region = w.region
+	}
+	if w.regionDirs && region != "" {
+		return filepath.Join(w.outputDir, w.serviceTag, region, w.expandTemplate(timeutil.Now(), region))
+	}
+	return filepath.Join(w.outputDir, w.serviceTag, w.expandTemplate(timeutil.Now(), region))
+}
+
+// expandTemplate substitutes the filename placeholders for the given time and region
+func (w *LogWriter) expandTemplate(now time.Time, region string) string {
+	replacer := strings.NewReplacer(
+		"{service}", w.serviceTag,
+		"{date}", now.Format("2006-01-02"),
+		"{region}", region,
+		"{profile}", w.profile,
 	)
-}
\ No newline at end of file
+	return replacer.Replace(w.filenameTemplate)
+}
+
+// checkWritable creates dir if needed and probes it with a temp file, so an
+// unwritable output location fails before the scan instead of on every event
+func checkWritable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("output directory %s can't be created: %v (choose another with --output or --export-file)", dir, err)
+	}
+	probe, err := os.CreateTemp(dir, ".cloudtrail-logs-write-check-*")
+	if err != nil {
+		return fmt.Errorf("output directory %s is not writable: %v (choose another with --output or --export-file)", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	return nil
+}
+
+// Lock takes an advisory lock on the current output file so that two runs
+// targeting the same file can't interleave their writes. The lock lives in a
+// sidecar "<file>.lock" so it works the same on platforms with mandatory locks.
+//
+// Only the file name the run starts with is locked. Files it moves on to
+// later (numbered files under a size limit, the next day's file, or another
+// region's file in a multi-region scan) share that lock, so the same command
+// run twice on the same day excludes itself, but a run started after the
+// date rolls over locks the new name and isn't stopped by one still running
+// from the day before.
+func (w *LogWriter) Lock() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Several producers may legitimately share a FIFO or socket
+	if w.streamMode != 0 {
+		return nil
+	}
+
+	filename := w.currentFile("")
+	if err := checkWritable(filepath.Dir(filename)); err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		fileLock := flock.New(filename + ".lock")
+		locked, err := fileLock.TryLock()
+		if err != nil {
+			return fmt.Errorf("failed to lock output file %s: %v", filename, err)
+		}
+		if !locked {
+			return fmt.Errorf("output file %s is in use by another cloudtrail-logs run", filename)
+		}
+
+		// A run finishing at the same moment may have just removed the
+		// sidecar, leaving this lock on a file no one else will see
+		if _, err := os.Stat(fileLock.Path()); os.IsNotExist(err) && attempt == 0 {
+			fileLock.Unlock()
+			continue
+		}
+
+		w.fileLock = fileLock
+		return nil
+	}
+}
+
+// Unlock releases the lock taken by Lock
+func (w *LogWriter) Unlock() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.fileLock == nil {
+		return nil
+	}
+	// The sidecar is removed while the lock is still held, so no other run
+	// can be holding a lock on it when it disappears
+	removeErr := os.Remove(w.fileLock.Path())
+	err := w.fileLock.Unlock()
+	w.fileLock = nil
+	if err == nil && removeErr != nil && !os.IsNotExist(removeErr) {
+		err = fmt.Errorf("failed to remove lock file: %v", removeErr)
+	}
+	return err
+}
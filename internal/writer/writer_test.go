@@ -0,0 +1,295 @@
+// internal/writer/writer_test.go
+package writer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudtrail/types"
+)
+
+// testEntry builds a KMS Decrypt entry minute minutes after 2024-01-15 00:00 UTC
+func testEntry(id string, minute int) Entry {
+	when := time.Date(2024, 1, 15, 0, minute, 0, 0, time.UTC)
+	return Entry{
+		Event: types.Event{
+			EventId:     aws.String(id),
+			EventName:   aws.String("Decrypt"),
+			EventSource: aws.String("kms.amazonaws.com"),
+			Username:    aws.String("alice"),
+			EventTime:   &when,
+		},
+		Details: map[string]interface{}{
+			"eventID":           id,
+			"requestParameters": map[string]interface{}{"keyId": "key-1"},
+		},
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	w := NewLogWriter(t.TempDir(), "kms", &ExportOptions{Region: "eu-west-1", Profile: "prod"})
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{DefaultFilenameTemplate, "kms-events-2024-01-15.log"},
+		{"{service}.log", "kms.log"},
+		{"{date}.log", "2024-01-15.log"},
+		{"{region}.log", "eu-west-1.log"},
+		{"{profile}.log", "prod.log"},
+		{"{profile}/{region}/{service}-{date}.log", "prod/eu-west-1/kms-2024-01-15.log"},
+		{"{date}-{date}.log", "2024-01-15-2024-01-15.log"},
+		{"static.log", "static.log"},
+	}
+	for _, tc := range tests {
+		w.filenameTemplate = tc.template
+		if got := w.expandTemplate(now, w.region); got != tc.want {
+			t.Errorf("expandTemplate(%q) = %q, want %q", tc.template, got, tc.want)
+		}
+	}
+
+	// Events from another region in a multi-region scan get their own name
+	w.filenameTemplate = "{region}.log"
+	if got := w.expandTemplate(now, "us-west-2"); got != "us-west-2.log" {
+		t.Errorf("expandTemplate with region = %q, want us-west-2.log", got)
+	}
+}
+
+func TestTemplateSubdirectories(t *testing.T) {
+	dir := t.TempDir()
+	w := NewLogWriter(dir, "kms", &ExportOptions{FilenameTemplate: "{profile}/{region}/events.log", Region: "eu-west-1", Profile: "prod"})
+
+	want := filepath.Join(dir, "kms", "prod", "eu-west-1", "events.log")
+	if got := w.GetCurrentFile(); got != want {
+		t.Errorf("GetCurrentFile() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateFilenameTemplate(t *testing.T) {
+	for _, template := range []string{DefaultFilenameTemplate, "{profile}/{region}/{service}-{date}.log", "plain.log"} {
+		if err := ValidateFilenameTemplate(template); err != nil {
+			t.Errorf("ValidateFilenameTemplate(%q) = %v, want nil", template, err)
+		}
+	}
+	for _, template := range []string{"{account}.log", "{service}-{Date}.log", "{}.log"} {
+		if err := ValidateFilenameTemplate(template); err == nil {
+			t.Errorf("ValidateFilenameTemplate(%q) = nil, want an error", template)
+		}
+	}
+}
+
+func TestTextSeparator(t *testing.T) {
+	dash := strings.Repeat("-", 80)
+	tests := []struct {
+		separator string
+		want      string // between the first event and the second
+	}{
+		{"", "\n" + dash + "\n["},
+		{SeparatorLine, "\n" + dash + "\n["},
+		{SeparatorBlank, "key-1\n\n["},
+		{SeparatorNone, "key-1\n["},
+	}
+	for _, tc := range tests {
+		file := filepath.Join(t.TempDir(), "events.log")
+		w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Separator: tc.separator})
+		if err := w.WriteBatch([]Entry{testEntry("event-1", 1), testEntry("event-2", 2)}); err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		text := string(data)
+		if !strings.Contains(text, tc.want) {
+			t.Errorf("separator %q: events aren't separated by %q:\n%s", tc.separator, tc.want, text)
+		}
+		if tc.separator != "" && tc.separator != SeparatorLine && strings.Contains(text, dash) {
+			t.Errorf("separator %q: the dashed rule is still written:\n%s", tc.separator, text)
+		}
+	}
+}
+
+func TestValidateSeparator(t *testing.T) {
+	for _, separator := range []string{"", SeparatorLine, SeparatorBlank, SeparatorNone} {
+		if err := ValidateSeparator(separator); err != nil {
+			t.Errorf("ValidateSeparator(%q) = %v, want nil", separator, err)
+		}
+	}
+	if err := ValidateSeparator("dashes"); err == nil {
+		t.Error("ValidateSeparator(dashes) = nil, want an error")
+	}
+}
+
+// testPage is a page of size entries, as the monitor hands them over
+func testPage(size int) []Entry {
+	page := make([]Entry, size)
+	for i := range page {
+		page[i] = testEntry(fmt.Sprintf("event-%d", i), i%60)
+		page[i].Index = i + 1
+	}
+	return page
+}
+
+func TestWriteBatchMatchesWriteEntry(t *testing.T) {
+	page := testPage(10)
+	for _, format := range []string{FormatText, FormatNDJSON} {
+		dir := t.TempDir()
+		perEvent := filepath.Join(dir, "per-event.log")
+		perBatch := filepath.Join(dir, "per-batch.log")
+
+		w := NewLogWriter("", "kms", &ExportOptions{Filename: perEvent, Format: format})
+		for _, entry := range page {
+			if err := w.WriteEntry(entry); err != nil {
+				t.Fatal(err)
+			}
+		}
+		w.Close()
+		w = NewLogWriter("", "kms", &ExportOptions{Filename: perBatch, Format: format})
+		if err := w.WriteBatch(page); err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+
+		want, _ := os.ReadFile(perEvent)
+		got, _ := os.ReadFile(perBatch)
+		if string(got) != string(want) {
+			t.Errorf("%s: WriteBatch wrote\n%s\nwant\n%s", format, got, want)
+		}
+	}
+}
+
+func TestRegionDirs(t *testing.T) {
+	dir := t.TempDir()
+	w := NewLogWriter(dir, "kms", &ExportOptions{RegionDirs: true, Region: "us-east-1", FilenameTemplate: "events.log"})
+	east, west := testEntry("event-1", 1), testEntry("event-2", 2)
+	east.Region, west.Region = "us-east-1", "eu-west-1"
+	if err := w.WriteBatch([]Entry{east, west}); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	for _, region := range []string{"us-east-1", "eu-west-1"} {
+		data, err := os.ReadFile(filepath.Join(dir, "kms", region, "events.log"))
+		if err != nil {
+			t.Errorf("no log for %s: %v", region, err)
+			continue
+		}
+		if n := strings.Count(string(data), "] Decrypt"); n != 1 {
+			t.Errorf("%s log holds %d events, want 1", region, n)
+		}
+	}
+	if want := filepath.Join(dir, "kms", "us-east-1", "events.log"); w.GetCurrentFile() != want {
+		t.Errorf("GetCurrentFile() = %s, want the writer's own region %s", w.GetCurrentFile(), want)
+	}
+
+	// Without the option every region shares the service directory
+	flat := NewLogWriter(dir, "kms", &ExportOptions{Region: "us-east-1", FilenameTemplate: "events.log"})
+	if want := filepath.Join(dir, "kms", "events.log"); flat.currentFile("eu-west-1") != want {
+		t.Errorf("currentFile(eu-west-1) = %s, want %s", flat.currentFile("eu-west-1"), want)
+	}
+}
+
+func TestNilEventTime(t *testing.T) {
+	entry := testEntry("event-1", 0)
+	entry.Event.EventTime = nil
+
+	for _, format := range []string{FormatText, FormatJSON, FormatNDJSON, FormatJSONDocument, FormatCloudEvents, FormatNative} {
+		file := filepath.Join(t.TempDir(), "events.log")
+		w := NewLogWriter("", "kms", &ExportOptions{Filename: file, Format: format})
+		if err := w.WriteEntry(entry); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+		if err := w.Close(); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+		if data, _ := os.ReadFile(file); len(data) == 0 {
+			t.Errorf("%s: nothing written", format)
+		}
+	}
+
+	if text := formatEventAsText(entry, SeparatorLine); !strings.HasPrefix(text, "[N/A] Decrypt") {
+		t.Errorf("text event starts %q, want the N/A placeholder", strings.SplitN(text, "\n", 2)[0])
+	}
+}
+
+func TestNDJSONExport(t *testing.T) {
+	entries := testPage(3)
+	data := exportEntries(t, FormatNDJSON, entries)
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) != len(entries) {
+		t.Fatalf("got %d lines, want one per event:\n%s", len(lines), data)
+	}
+	for i, line := range lines {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d isn't a JSON object: %v\n%s", i+1, err, line)
+		}
+		details, _ := record["details"].(map[string]interface{})
+		if details["eventID"] != *entries[i].Event.EventId {
+			t.Errorf("line %d = %s, want event %s", i+1, line, *entries[i].Event.EventId)
+		}
+		indented, err := FormatJSONEvent(entries[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, []byte(indented)); err != nil {
+			t.Fatal(err)
+		}
+		if line != compact.String() {
+			t.Errorf("line %d isn't the json record compacted:\n%s\n%s", i+1, line, compact.String())
+		}
+	}
+
+	// Read back as a whole, the file is a stream of exactly those records
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	count := 0
+	for decoder.More() {
+		var record map[string]interface{}
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("decoding the file: %v", err)
+		}
+		count++
+	}
+	if count != len(entries) {
+		t.Errorf("decoded %d records, want %d", count, len(entries))
+	}
+}
+
+func BenchmarkWritePerEvent(b *testing.B) {
+	page := testPage(50)
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: filepath.Join(b.TempDir(), "events.log")})
+	defer w.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, entry := range page {
+			if err := w.WriteEntry(entry); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkWritePerBatch(b *testing.B) {
+	page := testPage(50)
+	w := NewLogWriter("", "kms", &ExportOptions{Filename: filepath.Join(b.TempDir(), "events.log")})
+	defer w.Close()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := w.WriteBatch(page); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
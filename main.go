@@ -1,15 +1,21 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/dhairya13703/cloudtrail-logs/cmd"
+	"github.com/dhairya13703/cloudtrail-logs/internal/exit"
 )
 
 func main() {
 	if err := cmd.Execute(); err != nil {
+		var exitErr *exit.Error
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}